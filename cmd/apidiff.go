@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gitr/internal/apidiff"
+	"gitr/internal/git"
+	"gitr/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	apiDiffAll      bool
+	apiDiffInternal bool
+	apiDiffExitCode bool
+)
+
+var apiDiffCmd = &cobra.Command{
+	Use:   "api-diff",
+	Short: "List changes to exported Go identifiers between HEAD and the index",
+	Long: `Compare the exported API of every Go package with staged changes at HEAD and
+in the index: removed or renamed functions, methods, types, constants and
+variables, changed signatures, removed struct fields and methods added to
+interfaces break callers or implementers and are listed first. Packages with
+a file that doesn't parse are marked with "?" and not compared, since their
+API can't be known.
+
+Main packages and packages under internal/ can't be imported by other modules
+and are skipped unless --internal is given. The same report is passed to the
+model when generating a message, so breaking changes get a "!" and a
+BREAKING CHANGE footer.`,
+	Example: `  gitr api-diff
+  gitr api-diff --all --internal
+  gitr api-diff --exit-code --format json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{format: outputFormat}
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+
+		report, err := apidiff.Compare(apidiff.Options{All: apiDiffAll, Internal: apiDiffInternal})
+		if err != nil {
+			opts.fail(output.ExitError, "Error comparing APIs: %v", err)
+		}
+
+		if outputFormat == output.FormatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(report)
+		} else {
+			printAPIDiff(report)
+		}
+		if apiDiffExitCode && len(report.Breaking()) > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	apiDiffCmd.Flags().BoolVarP(&apiDiffAll, "all", "a", false, "Compare with the working tree instead of the index")
+	apiDiffCmd.Flags().BoolVar(&apiDiffInternal, "internal", false, "Include main packages and packages under internal/")
+	apiDiffCmd.Flags().BoolVar(&apiDiffExitCode, "exit-code", false, "Exit with 1 when there are breaking changes")
+	rootCmd.AddCommand(apiDiffCmd)
+}
+
+// printAPIDiff prints the changes of every package, breaking ones marked with "!"
+func printAPIDiff(report apidiff.Report) {
+	for _, unknown := range report.Unknown {
+		fmt.Printf("? %s\n", unknown)
+	}
+	if len(report.Packages) == 0 {
+		if len(report.Unknown) == 0 {
+			fmt.Println("No staged changes to Go packages")
+		}
+		return
+	}
+	if len(report.Changes) == 0 {
+		if len(report.Packages) == 1 {
+			fmt.Printf("No API changes in %s\n", report.Packages[0])
+		} else {
+			fmt.Printf("No API changes in %d packages\n", len(report.Packages))
+		}
+		return
+	}
+	for _, change := range report.Changes {
+		mark := " "
+		if change.Breaking {
+			mark = "!"
+		}
+		fmt.Printf("%s %s\n", mark, change)
+	}
+	switch breaking := len(report.Breaking()); breaking {
+	case 0:
+	case 1:
+		fmt.Println("\n1 breaking change")
+	default:
+		fmt.Printf("\n%d breaking changes\n", breaking)
+	}
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/apidiff"
+)
+
+func TestPrintAPIDiffCounts(t *testing.T) {
+	one := apidiff.Report{
+		Packages: []string{"api"},
+		Changes:  []apidiff.Change{{Package: "api", Kind: apidiff.Removed, Object: "func Hello", Breaking: true}},
+	}
+	stdout, _ := run(t, "", func() { printAPIDiff(one) })
+	if !strings.HasSuffix(stdout, "\n1 breaking change\n") {
+		t.Errorf("output:\n%s", stdout)
+	}
+
+	two := one
+	two.Changes = append(two.Changes, apidiff.Change{Package: "api", Kind: apidiff.Removed, Object: "func Bye", Breaking: true})
+	stdout, _ = run(t, "", func() { printAPIDiff(two) })
+	if !strings.HasSuffix(stdout, "\n2 breaking changes\n") {
+		t.Errorf("output:\n%s", stdout)
+	}
+
+	stdout, _ = run(t, "", func() { printAPIDiff(apidiff.Report{Packages: []string{"api"}}) })
+	if stdout != "No API changes in api\n" {
+		t.Errorf("output = %q", stdout)
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"gitr/internal/cache"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the response cache",
+	Long: `GitR caches model responses keyed by the staged diff, prompt, model and parameters,
+so previewing a message with 'gitr' and then committing with 'gitr -c' only calls the provider once.`,
+}
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache statistics",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		responseCache := openCache()
+
+		stats, err := responseCache.Stats()
+		if err != nil {
+			fmt.Printf("Error reading cache: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("Cache directory: %s\n", stats.Dir)
+		fmt.Printf("Entries: %d (%d expired)\n", stats.Entries, stats.Expired)
+		fmt.Printf("Size: %.1f KB\n", float64(stats.Bytes)/1024)
+		if stats.Entries > 0 {
+			fmt.Printf("Oldest entry: %s\n", stats.Oldest.Format(time.RFC3339))
+			fmt.Printf("Newest entry: %s\n", stats.Newest.Format(time.RFC3339))
+		}
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached responses",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		responseCache := openCache()
+
+		removed, err := responseCache.Clear()
+		if err != nil {
+			fmt.Printf("Error clearing cache: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Removed %d cached responses\n", removed)
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd, cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// openCache opens the response cache with the limits from the configuration
+func openCache() *cache.Cache {
+	cfg, _ := loadConfigFile()
+
+	responseCache, err := cache.New(cfg.Cache)
+	if err != nil {
+		fmt.Printf("Error opening cache: %v\n", err)
+		exit(1)
+	}
+	return responseCache
+}
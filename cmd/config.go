@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gitr/internal/config"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	configFile        string
+	configGlobal      bool
+	configLocal       bool
+	configJSON        bool
+	configShowSecrets bool
+)
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a setting",
+	Long: `Print the value of a setting addressed by its dotted key, e.g. openai.model,
+commit_template.max_length or profiles.<name>.openai.base_url.
+
+Exits with status 1 when the key is not set.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _ := loadConfigTarget()
+
+		value, ok, err := cfg.Get(args[0])
+		if err != nil {
+			fmt.Printf("Error: %v\n", err)
+			exit(1)
+		}
+		if !ok {
+			exit(1)
+		}
+		fmt.Println(value)
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Change a setting",
+	Long: `Change a setting addressed by its dotted key. The value is converted to the
+setting's type and validated before the configuration file is written.
+
+List values (openai.stop, fallback) are given comma-separated. Setting a key of
+a profile that doesn't exist yet (profiles.<name>.openai.*) creates the profile.`,
+	Example: `  gitr config set openai.model gpt-4o
+  gitr config set commit_template.max_length 50
+  gitr config set --global profiles.local.openai.provider local
+  gitr config set fallback local,backup`,
+	Args:              cobra.ExactArgs(2),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfigTarget(func(cfg *config.Config) error {
+			return cfg.Set(args[0], args[1])
+		})
+	},
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting",
+	Long: `Remove a setting addressed by its dotted key. Optional settings fall back to
+the provider's default; unsetting profiles.<name> deletes the profile. Required
+settings, such as commit_template.max_length, can't be unset.`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeConfigKeys,
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfigTarget(func(cfg *config.Config) error {
+			return cfg.Unset(args[0])
+		})
+	},
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all settings",
+	Long: `List every setting that has a value as key=value lines, or as a JSON object
+with --json. API keys are masked unless --show-secrets is given.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _ := loadConfigTarget()
+
+		settings := cfg.Settings()
+		if !configShowSecrets {
+			for i := range settings {
+				if settings[i].Secret {
+					settings[i].Value = config.MaskAPIKey(settings[i].Value)
+					settings[i].Typed = settings[i].Value
+				}
+			}
+		}
+
+		if configJSON {
+			values := make(map[string]any, len(settings))
+			for _, setting := range settings {
+				values[setting.Key] = setting.Typed
+			}
+			data, err := json.MarshalIndent(values, "", "  ")
+			if err != nil {
+				fmt.Printf("Error: %v\n", err)
+				exit(1)
+			}
+			fmt.Println(string(data))
+			return
+		}
+
+		for _, setting := range settings {
+			fmt.Printf("%s=%s\n", setting.Key, setting.Value)
+		}
+	},
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{configGetCmd, configSetCmd, configUnsetCmd, configListCmd} {
+		cmd.Flags().StringVar(&configFile, "file", "", "Use the given configuration file")
+		cmd.Flags().BoolVar(&configGlobal, "global", false, "Use the global configuration file (~/.gitr_config or ~/.config/gitr/config)")
+		cmd.Flags().BoolVar(&configLocal, "local", false, "Use .gitr_config in the current directory")
+		cmd.MarkFlagsMutuallyExclusive("file", "global", "local")
+	}
+	configListCmd.Flags().BoolVar(&configJSON, "json", false, "Print settings as a JSON object")
+	configListCmd.Flags().BoolVar(&configShowSecrets, "show-secrets", false, "Print API keys unmasked")
+
+	configCmd.AddCommand(configGetCmd, configSetCmd, configUnsetCmd, configListCmd)
+}
+
+// configTargetPath returns the configuration file selected by --file, --global or --local
+func configTargetPath() string {
+	var (
+		path string
+		err  error
+	)
+	switch {
+	case configFile != "":
+		path = configFile
+	case configGlobal:
+		path, err = config.GlobalConfigFile()
+	case configLocal:
+		path, err = config.LocalConfigFile()
+	default:
+		path, err = config.FindConfigFile()
+	}
+	if err != nil {
+		fmt.Printf("Error finding config file: %v\n", err)
+		exit(1)
+	}
+	return path
+}
+
+// loadConfigTarget loads the selected configuration file, falling back to defaults when it doesn't exist yet
+func loadConfigTarget() (*config.Config, string) {
+	configPath := configTargetPath()
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return config.CreateDefaultConfig(), configPath
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		exit(1)
+	}
+	return cfg, configPath
+}
+
+// updateConfigTarget applies a change to the selected configuration file and saves it
+func updateConfigTarget(change func(cfg *config.Config) error) {
+	cfg, configPath := loadConfigTarget()
+
+	if err := change(cfg); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		exit(1)
+	}
+	if err := cfg.Save(configPath); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		exit(1)
+	}
+}
+
+// completeConfigKeys completes the key argument of get, set and unset
+func completeConfigKeys(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	cfg, _ := loadConfigTarget()
+	var keys []string
+	for _, key := range config.Keys() {
+		if !strings.Contains(key, "<name>") {
+			keys = append(keys, key)
+			continue
+		}
+		for _, name := range cfg.ProfileNames()[1:] {
+			keys = append(keys, strings.Replace(key, "<name>", name, 1))
+		}
+	}
+	return keys, cobra.ShellCompDirectiveNoFileComp
+}
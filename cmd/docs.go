@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+)
+
+var manCmd = &cobra.Command{
+	Use:    "man <dir>",
+	Short:  "Generate man pages",
+	Long:   `Generate a man page for every command into the given directory.`,
+	Args:   cobra.ExactArgs(1),
+	Hidden: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := os.MkdirAll(args[0], 0755); err != nil {
+			fmt.Printf("Error creating directory: %v\n", err)
+			exit(1)
+		}
+
+		header := &doc.GenManHeader{
+			Title:   "GITR",
+			Section: "1",
+			Source:  "GitR",
+			Manual:  "GitR Manual",
+		}
+		if err := doc.GenManTree(rootCmd, header, args[0]); err != nil {
+			fmt.Printf("Error generating man pages: %v\n", err)
+			exit(1)
+		}
+		fmt.Printf("Man pages written to %s\n", args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(manCmd)
+}
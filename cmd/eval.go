@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitr/internal/config"
+	"gitr/internal/eval"
+	"gitr/internal/git"
+	"gitr/internal/llm"
+	"gitr/internal/output"
+	"gitr/internal/tokens"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	evalHistory  int
+	evalRev      string
+	evalProfiles []string
+	evalJudge    string
+	evalOutput   string
+	evalSave     string
+	evalNoCache  bool
+)
+
+var evalCmd = &cobra.Command{
+	Use:   "eval [dataset.jsonl]",
+	Short: "Compare the messages of one or more profiles with reference messages",
+	Long: `Generate commit messages for a dataset of diffs with one or more profiles and
+score them against the reference messages: lint pass rate, header length and
+conventional type accuracy, plus an optional rating by an LLM judge.
+
+The dataset is a JSON Lines file with one {"id", "diff", "reference"} object per
+line. Without one, the samples are built from the history of the current
+repository. The report is Markdown, or JSON with --format json.`,
+	Example: `  gitr eval --history 30 --profiles default,local
+  gitr eval samples.jsonl --profiles default --judge release -o report.md
+  gitr eval --history 50 --save-dataset samples.jsonl`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{format: outputFormat}
+		cfg := loadConfig(opts)
+
+		var samples []eval.Sample
+		var dataset string
+		var err error
+		if len(args) == 1 {
+			dataset = args[0]
+			samples, err = eval.LoadDataset(dataset)
+		} else {
+			if !git.IsRepository() {
+				opts.fail(output.ExitNotRepository, "Error: not inside a git repository (pass a dataset file instead)")
+			}
+			dataset = fmt.Sprintf("history of %s, last %d commits", evalRev, evalHistory)
+			samples, err = eval.FromHistory(evalRev, evalHistory)
+		}
+		if err != nil {
+			opts.fail(output.ExitError, "Error loading samples: %v", err)
+		}
+		if len(samples) == 0 {
+			opts.fail(output.ExitError, "Error: the dataset has no samples")
+		}
+
+		if evalSave != "" {
+			if err := eval.SaveDataset(evalSave, samples); err != nil {
+				opts.fail(output.ExitError, "Error saving dataset: %v", err)
+			}
+			fmt.Fprintf(os.Stderr, "Saved %d samples to %s\n", len(samples), evalSave)
+		}
+
+		profiles := evalProfiles
+		if len(profiles) == 0 {
+			profiles = []string{cfg.ActiveProfileName()}
+		}
+		for _, profile := range profiles {
+			if !cfg.HasProfile(profile) {
+				opts.fail(output.ExitUsage, "Error: --profiles: profile %q not found (available: %s)", profile, strings.Join(cfg.ProfileNames(), ", "))
+			}
+		}
+		var judge *config.Config
+		if evalJudge != "" {
+			judgeCfg := *cfg
+			if err := judgeCfg.SelectProfile(evalJudge); err != nil {
+				opts.fail(output.ExitUsage, "Error: --judge: %v", err)
+			}
+			judge = &judgeCfg
+		}
+
+		lint := output.LintOptions{
+			MaxLength:    cfg.CommitTemplate.MaxLength,
+			Conventional: strings.EqualFold(cfg.CommitTemplate.Style, "conventional"),
+		}
+
+		report := eval.Report{Version: eval.ReportVersion, Dataset: dataset, Samples: samples}
+		for _, profile := range profiles {
+			cfg.SelectProfile(profile)
+			// Each profile is scored on its own answers
+			cfg.Fallback = nil
+
+			var results []eval.Result
+			for i, sample := range samples {
+				fmt.Fprintf(os.Stderr, "[%s %d/%d] %s\n", profile, i+1, len(samples), sample.ID)
+				result := evaluateSample(cfg, judge, sample, lint)
+				results = append(results, result)
+			}
+			report.Results = append(report.Results, results...)
+			report.Summaries = append(report.Summaries, eval.Summarize(profile, cfg.Active().Model, results))
+		}
+
+		out := os.Stdout
+		if evalOutput != "" {
+			out, err = os.Create(evalOutput)
+			if err != nil {
+				opts.fail(output.ExitError, "Error creating report: %v", err)
+			}
+			defer out.Close()
+		}
+		if outputFormat == output.FormatJSON {
+			encoder := json.NewEncoder(out)
+			encoder.SetIndent("", "  ")
+			err = encoder.Encode(report)
+		} else {
+			_, err = fmt.Fprint(out, report.Markdown())
+		}
+		if err != nil {
+			opts.fail(output.ExitError, "Error writing report: %v", err)
+		}
+	},
+}
+
+func init() {
+	evalCmd.Flags().IntVar(&evalHistory, "history", 20, "Number of commits to replay when no dataset is given")
+	evalCmd.Flags().StringVar(&evalRev, "rev", "HEAD", "Revision whose history is replayed")
+	evalCmd.Flags().StringSliceVar(&evalProfiles, "profiles", nil, "Profiles to compare, comma-separated (default: the active profile)")
+	evalCmd.Flags().StringVar(&evalJudge, "judge", "", "Profile that rates every message against the reference")
+	evalCmd.Flags().StringVarP(&evalOutput, "output", "o", "", "Write the report to a file instead of stdout")
+	evalCmd.Flags().StringVar(&evalSave, "save-dataset", "", "Save the samples as JSON Lines for later runs")
+	evalCmd.Flags().BoolVar(&evalNoCache, "no-cache", false, "Don't use cached responses")
+
+	evalCmd.RegisterFlagCompletionFunc("profiles", completeProfiles)
+	evalCmd.RegisterFlagCompletionFunc("judge", completeProfiles)
+	rootCmd.AddCommand(evalCmd)
+}
+
+// evaluateSample generates a message for a sample and scores it
+func evaluateSample(cfg, judge *config.Config, sample eval.Sample, lint output.LintOptions) eval.Result {
+	result := eval.Result{Sample: sample.ID, Profile: cfg.ActiveProfileName(), Model: cfg.Active().Model}
+
+	generated, err := llm.GenerateCommitMessage(cfg, sample.Diff, llm.Options{NoCache: evalNoCache})
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Model = generated.Model
+	result.LatencyMs = generated.Latency.Milliseconds()
+	result.CostUSD = generated.Cost
+	if generated.Usage != nil {
+		result.PromptTokens = generated.Usage.PromptTokens
+		result.CompletionTokens = generated.Usage.CompletionTokens
+	}
+	result.Message = output.ParseCommitMessage(generated.Content)
+
+	score := eval.Check(result.Message, sample.Reference, lint)
+	if judge != nil {
+		count := func(text string) int { return tokens.Count(text, judge.Active().Model) }
+		answer, err := llm.Complete(judge, eval.JudgeInstruction, eval.JudgePrompt(sample, result.Message, count))
+		if err == nil {
+			var rating float64
+			if rating, err = eval.ParseRating(answer.Content); err == nil {
+				score.Judge = &rating
+			}
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: judge failed for %s: %v\n", sample.ID, err)
+		}
+	}
+	result.Score = &score
+	return result
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitr/internal/explain"
+	"gitr/internal/git"
+	"gitr/internal/llm"
+	"gitr/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var explainDepth int
+
+var explainCmd = &cobra.Command{
+	Use:   "explain <rev|range>",
+	Short: "Explain what a commit or range of commits did in plain language",
+	Long: `Explain a commit, or a range such as main..feature, from its commit messages
+and diff. The explanation is streamed to the terminal in layers:
+
+  --depth 1  a one-sentence summary
+  --depth 2  and what changed in every file
+  --depth 3  and possible risks (default)
+
+A commit is compared with its first parent; a..b compares a with b and a...b
+the merge base of a and b with b.`,
+	Example: `  gitr explain HEAD
+  gitr explain v1.2.0..v1.3.0 --depth 1
+  gitr explain main...feature --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{profile: profileName, model: modelName, format: outputFormat, verbose: verbose}
+		if explainDepth < explain.DepthSummary || explainDepth > explain.DepthRisks {
+			opts.fail(output.ExitUsage, "Error: --depth must be between %d and %d", explain.DepthSummary, explain.DepthRisks)
+		}
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+
+		target, err := explain.Load(args[0])
+		if err != nil {
+			opts.fail(output.ExitUsage, "Error: %v", err)
+		}
+		if strings.TrimSpace(target.Diff) == "" {
+			opts.fail(output.ExitNoChanges, "Nothing to explain: %s changes no files", args[0])
+		}
+		cfg := loadConfig(opts)
+
+		instruction := explain.Instruction(explainDepth)
+		prompt := explain.Prompt(target)
+		if outputFormat == output.FormatJSON {
+			result, err := llm.CompleteDiff(cfg, instruction, prompt, target.Diff)
+			if err != nil {
+				opts.fail(output.ExitProvider, "Error explaining %s: %v", args[0], err)
+			}
+			opts.report(result)
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(struct {
+				Version     int              `json:"version"`
+				Revision    string           `json:"revision"`
+				From        string           `json:"from"`
+				To          string           `json:"to"`
+				Commits     []explain.Commit `json:"commits"`
+				Depth       int              `json:"depth"`
+				Explanation string           `json:"explanation"`
+				Profile     string           `json:"profile"`
+				Model       string           `json:"model"`
+			}{output.ReportVersion, target.Spec, target.From, target.To, target.Commits, explainDepth,
+				strings.TrimSpace(result.Content), result.Profile, result.Model})
+			return
+		}
+
+		result, err := llm.Stream(cfg, instruction, prompt, target.Diff, os.Stdout)
+		if err != nil {
+			fmt.Println()
+			opts.fail(output.ExitProvider, "Error explaining %s: %v", args[0], err)
+		}
+		if !strings.HasSuffix(result.Content, "\n") {
+			fmt.Println()
+		}
+		opts.report(result)
+	},
+}
+
+func init() {
+	explainCmd.Flags().IntVar(&explainDepth, "depth", explain.DepthRisks, "1: summary, 2: and changes per file, 3: and possible risks")
+	rootCmd.AddCommand(explainCmd)
+}
@@ -0,0 +1,837 @@
+package cmd
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"gitr/internal/apidiff"
+	"gitr/internal/cache"
+	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/heuristic"
+	"gitr/internal/history"
+	"gitr/internal/i18n"
+	"gitr/internal/llm"
+	"gitr/internal/merge"
+	"gitr/internal/output"
+	"gitr/internal/tokens"
+	"gitr/internal/trailers"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	noCache    bool
+	bypass     bool
+	commitFlag bool
+	coAuthors  string
+	allFlag    bool
+	pickFlag   bool
+	agentFlag  bool
+	offline    bool
+	reviewFlag bool
+	noReview   bool
+	langFlag   string
+
+	// git commit passthrough
+	cleanupMode string
+	signoff     bool
+	signKey     string
+	noSign      bool
+	noVerify    bool
+	author      string
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a commit message for the staged changes",
+	Long: `Generate a commit message for the staged changes and print it.
+This is also what 'gitr' does without a subcommand.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		generateCommitMessage(generationOptions())
+	},
+}
+
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Generate a commit message and commit the staged changes",
+	Long: `Generate a commit message for the staged changes, review it and commit.
+Use --bypass to commit without the review step. Same as 'gitr -c'.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		generateAndCommit(generationOptions())
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{rootCmd, generateCmd, commitCmd} {
+		c.Flags().BoolVar(&noCache, "no-cache", false, "Ignore cached responses and call the provider")
+		c.Flags().StringVar(&coAuthors, "with", "", "Add Co-authored-by trailers for team members (comma-separated aliases, names or emails)")
+		c.RegisterFlagCompletionFunc("with", completeCoAuthors)
+		c.Flags().BoolVarP(&allFlag, "all", "a", false, "Include unstaged changes to tracked files (like git commit --all)")
+		c.Flags().BoolVar(&pickFlag, "pick", false, "Pick modified and untracked files to stage before generating")
+		c.MarkFlagsMutuallyExclusive("all", "pick")
+		c.Flags().BoolVar(&agentFlag, "agent", false, "Let the model inspect the repository with read-only tools (agent mode)")
+		c.Flags().BoolVar(&offline, "offline", false, "Build the message from the changed files with rules instead of a model")
+		c.MarkFlagsMutuallyExclusive("agent", "offline")
+		c.Flags().StringVar(&langFlag, "lang", "", "Write the message in this language (e.g. de, ja), overriding the config")
+	}
+	for _, c := range []*cobra.Command{rootCmd, commitCmd, squashCmd} {
+		c.Flags().BoolVarP(&bypass, "bypass", "b", false, "Commit without confirmation (overrides config)")
+		c.Flags().BoolVar(&reviewFlag, "review", false, "Review the changes first and stop on serious findings")
+		c.Flags().BoolVar(&noReview, "no-review", false, "Don't review the changes, even if configured")
+		c.MarkFlagsMutuallyExclusive("review", "no-review")
+	}
+	rootCmd.Flags().BoolVarP(&commitFlag, "commit", "c", false, "Generate commit message and commit with confirmation")
+
+	for _, c := range []*cobra.Command{rootCmd, commitCmd, squashCmd} {
+		c.Flags().StringVar(&cleanupMode, "cleanup", "", "How git cleans up the message: "+strings.Join(git.CleanupModes, ", "))
+		c.Flags().BoolVarP(&signoff, "signoff", "s", false, "Add a Signed-off-by trailer")
+		c.Flags().StringVarP(&signKey, "gpg-sign", "S", "", "Sign the commit (GPG or SSH, as configured in git), optionally with the given key")
+		c.Flags().Lookup("gpg-sign").NoOptDefVal = signDefaultKey
+		c.Flags().BoolVar(&noSign, "no-gpg-sign", false, "Don't sign the commit, even if configured")
+		c.Flags().BoolVar(&noVerify, "no-verify", false, "Skip the pre-commit and commit-msg hooks")
+		c.Flags().StringVar(&author, "author", "", "Override the commit author (\"Name <email>\")")
+		c.MarkFlagsMutuallyExclusive("gpg-sign", "no-gpg-sign")
+		c.RegisterFlagCompletionFunc("cleanup", cobra.FixedCompletions(git.CleanupModes, cobra.ShellCompDirectiveNoFileComp))
+	}
+
+	rootCmd.AddCommand(generateCmd, commitCmd)
+}
+
+// signDefaultKey is the value of a bare --gpg-sign: sign with git's configured key
+const signDefaultKey = "default"
+
+// runRoot runs the generation command selected by the root flags
+func runRoot(cmd *cobra.Command, args []string) {
+	if commitFlag {
+		generateAndCommit(generationOptions())
+		return
+	}
+	generateCommitMessage(generationOptions())
+}
+
+// generationOptions collects the options for a generation run from the flags
+func generationOptions() options {
+	return options{
+		profile:  profileName,
+		model:    modelName,
+		noCache:  noCache,
+		bypass:   bypass,
+		format:   outputFormat,
+		verbose:  verbose,
+		with:     coAuthors,
+		all:      allFlag,
+		pick:     pickFlag,
+		agent:    agentFlag,
+		offline:  offline,
+		review:   reviewFlag,
+		noReview: noReview,
+		lang:     langFlag,
+	}
+}
+
+// options holds the command line options for a generation run
+type options struct {
+	profile  string // provider profile name
+	model    string // overrides the model of the selected profile
+	noCache  bool   // skip the response cache
+	bypass   bool   // commit without confirmation
+	format   string // output format: text, json or raw
+	verbose  bool   // report profile, model, usage and latency on stderr
+	with     string // co-authors to credit, comma-separated
+	all      bool   // include unstaged changes to tracked files
+	pick     bool   // pick files to stage first
+	agent    bool   // enable agent mode for this run
+	offline  bool   // use the heuristic generator instead of a model
+	review   bool   // review the changes before committing
+	noReview bool   // skip the review even if configured
+	notes    string // what the staged changes combine, set by gitr squash
+	lang     string // language of the message, overrides the config
+}
+
+// machine reports whether the output is meant for scripts rather than people
+func (o options) machine() bool {
+	return o.format != output.FormatText
+}
+
+// diag returns where progress messages and hints go: stdout for people,
+// stderr when stdout carries machine-readable output
+func (o options) diag() *os.File {
+	if o.machine() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// fail reports an error in the selected format and exits with the given code
+func (o options) fail(exitCode int, format string, args ...any) {
+	o.failWithHint(exitCode, "", format, args...)
+}
+
+// failWithHint is fail with a hint on how to fix the problem
+func (o options) failWithHint(exitCode int, hint string, format string, args ...any) {
+	out := os.Stdout
+	if o.format == output.FormatRaw {
+		out = os.Stderr
+	}
+	output.WriteError(out, o.format, exitCode, i18n.Sprintf(format, args...), i18n.T(hint))
+	exit(exitCode)
+}
+
+// interactive reports whether the user can be asked questions
+func (o options) interactive() bool {
+	return !o.machine() && isatty.IsTerminal(os.Stdout.Fd()) && isatty.IsTerminal(os.Stdin.Fd())
+}
+
+// report describes where a response came from when --verbose is set
+func (o options) report(result *llm.Result) {
+	if !o.verbose {
+		return
+	}
+	usage := i18n.T("usage not reported")
+	if result.Usage != nil {
+		usage = i18n.Sprintf("%d prompt + %d completion tokens", result.Usage.PromptTokens, result.Usage.CompletionTokens)
+	}
+	if result.Cost != nil {
+		usage += fmt.Sprintf(" ($%.4f)", *result.Cost)
+	}
+	if result.Cached {
+		usage = i18n.T("cached")
+	}
+	i18n.Fprintf(os.Stderr, "Profile %q, model %s, %s, %s\n",
+		result.Profile, result.Model, usage, result.Latency.Round(time.Millisecond))
+}
+
+// loadConfig finds and loads the configuration, running the first-time setup
+// when needed, and selects the provider profile for this run.
+// Profile priority: 1. --profile flag, 2. `git config gitr.profile`, 3. default_profile
+func loadConfig(opts options) *config.Config {
+	configPath, err := config.FindConfigFile()
+	if err != nil {
+		opts.fail(output.ExitConfig, "Error finding config file: %v", err)
+	}
+
+	profileName := opts.profile
+	if profileName == "" {
+		profileName = git.RepoProfile()
+	}
+
+	var cfg *config.Config
+	if _, err := os.Stat(configPath); os.IsNotExist(err) && opts.offline {
+		// No provider is needed to work offline
+		cfg = config.CreateDefaultConfig()
+	} else if os.IsNotExist(err) {
+		// Scripts can't answer the setup questions
+		if opts.machine() {
+			opts.fail(output.ExitConfig, "Configuration error: no configuration found. Run 'gitr config' to set up GitR.")
+		}
+
+		// First time setup - run guided configuration
+		cfg = config.CreateDefaultConfig()
+		fmt.Println(i18n.T("First time setup detected!"))
+		fmt.Println("")
+
+		err = config.RunFirstTimeSetup(cfg, configPath)
+		if err != nil {
+			i18n.Printf("Error during setup: %v\n", err)
+			exit(output.ExitConfig)
+		}
+	} else {
+		// Load existing config
+		cfg, err = config.Load(configPath)
+		if err != nil {
+			opts.fail(output.ExitConfig, "Error loading config: %v", err)
+		}
+	}
+
+	if err := cfg.SelectProfile(profileName); err != nil {
+		opts.failWithHint(output.ExitConfig, "Run 'gitr config' to manage your profiles.", "Configuration error: %v", err)
+	}
+
+	// Check if this is an incomplete configuration (first time setup)
+	if cfg.IsFirstTimeSetup() && !opts.offline {
+		if opts.machine() {
+			opts.fail(output.ExitConfig, "Configuration error: configuration incomplete. Run 'gitr config' to finish the setup.")
+		}
+
+		fmt.Println(i18n.T("Configuration incomplete. Running setup..."))
+		fmt.Println("")
+
+		err = config.RunFirstTimeSetup(cfg, configPath)
+		if err != nil {
+			i18n.Printf("Error during setup: %v\n", err)
+			exit(output.ExitConfig)
+		}
+	}
+
+	if opts.model != "" {
+		cfg.Active().Model = opts.model
+	}
+	if opts.agent {
+		cfg.Agent.Enabled = true
+	}
+
+	// Language priority: 1. --lang flag, 2. `git config gitr.language`, 3. commit_template.language
+	if lang := cmp.Or(opts.lang, git.RepoLanguage()); lang != "" {
+		code := i18n.Normalize(lang)
+		if code == "" {
+			opts.failWithHint(output.ExitUsage, i18n.Sprintf("Supported languages: %s", strings.Join(i18n.Codes(), ", ")), "Error: unknown language %q", lang)
+		}
+		cfg.CommitTemplate.Language = code
+	}
+
+	// Validate configuration before proceeding
+	if opts.offline {
+		return cfg
+	}
+	if err := cfg.Validate(); err != nil {
+		opts.failWithHint(output.ExitConfig, "Run 'gitr config' to fix your configuration.", "Configuration error: %v", err)
+	}
+
+	return cfg
+}
+
+func generateCommitMessage(opts options) {
+	// Check if we're in a git repository
+	if !git.IsRepository() {
+		opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+	}
+
+	// Find and load configuration
+	cfg := loadConfig(opts)
+
+	// Get staged changes
+	stagedChanges := getChanges(opts)
+	if strings.TrimSpace(stagedChanges) == "" {
+		if opts.machine() {
+			opts.fail(output.ExitNoChanges, "No staged changes found")
+		}
+		fmt.Println(i18n.T("No staged changes found"))
+		return
+	}
+
+	// Generate commit message using LLM
+	diff := promptDiff(cfg, opts, stagedChanges)
+	request := llm.Options{Breaking: breakingChanges(cfg, opts), Notes: changeNotes(opts)}
+	result := generateMessage(cfg, opts, diff, request)
+	opts.report(result)
+
+	// Parse and print the response
+	withTrailers := coAuthorTrailers(cfg, opts)
+	commitMessage := addTrailers(opts, markBreaking(output.ParseCommitMessage(result.Content), request.Breaking), withTrailers)
+	if result.Heuristic && !opts.machine() {
+		fmt.Println(i18n.T(heuristicNotice))
+	}
+	output.WriteReport(os.Stdout, opts.format, output.NewReport(commitMessage, result))
+
+	recordHistory(cfg, history.Entry{
+		DiffHash:    cache.Key(cache.NormalizeDiff(stagedChanges)),
+		RawResponse: result.Content,
+		Message:     commitMessage,
+		Action:      history.ActionDisplayed,
+	}, result)
+}
+
+// generateAndCommit generates a message for the changes, lets the user review
+// it and commits. It reports whether a commit was made.
+func generateAndCommit(opts options) bool {
+	if cleanupMode != "" && !slices.Contains(git.CleanupModes, cleanupMode) {
+		opts.fail(output.ExitUsage, "Error: unknown cleanup mode %q (expected %s)", cleanupMode, strings.Join(git.CleanupModes, ", "))
+	}
+
+	// Check if we're in a git repository
+	if !git.IsRepository() {
+		opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+	}
+
+	// Check if there are staged changes
+	stagedChanges := getChanges(opts)
+	if strings.TrimSpace(stagedChanges) == "" {
+		opts.failWithHint(output.ExitNoChanges, "Please stage some changes first with 'git add', or use --all or --pick", "No staged changes found")
+	}
+
+	// Find and load configuration
+	cfg := loadConfig(opts)
+	roster := loadRoster(cfg, opts)
+	withTrailers := coAuthorTrailers(cfg, opts)
+
+	if (opts.review || cfg.Review.BeforeCommit) && !opts.noReview {
+		reviewBeforeCommit(cfg, opts, stagedChanges)
+	}
+
+	// Generate commit message using LLM
+	diff := promptDiff(cfg, opts, stagedChanges)
+	request := llm.Options{Breaking: breakingChanges(cfg, opts), Notes: changeNotes(opts)}
+	result := generateMessage(cfg, opts, diff, request)
+	opts.report(result)
+
+	// Parse the response
+	commitMessage := output.ParseCommitMessage(result.Content)
+	if commitMessage == "" {
+		opts.fail(output.ExitProvider, "Failed to generate commit message")
+	}
+	commitMessage = addTrailers(opts, markBreaking(commitMessage, request.Breaking), withTrailers)
+
+	// Determine if we should bypass confirmation
+	shouldBypass := opts.bypass || cfg.CommitTemplate.CommitWithoutConfirmation
+
+	var finalMessage string
+	var shouldCommit bool
+	var err error
+
+	// Track what happens to the message for the local history
+	entry := history.Entry{
+		DiffHash: cache.Key(cache.NormalizeDiff(stagedChanges)),
+	}
+
+	if shouldBypass {
+		// Bypass confirmation - use the generated message directly
+		finalMessage = commitMessage
+		shouldCommit = true
+		entry.Action = history.ActionBypassed
+		fmt.Fprintln(opts.diag(), i18n.T("Bypassing confirmation (using generated message)..."))
+	} else {
+		// Show commit editor TUI
+		commitTUI := output.NewCommitTUI(commitMessage)
+		commitTUI.SetOutput(opts.diag())
+		commitTUI.SetDiff(stagedChanges)
+		commitTUI.SetMaxLength(cfg.CommitTemplate.MaxLength)
+		if result.Heuristic {
+			commitTUI.SetNotice(i18n.T(heuristicNotice))
+		}
+		if !opts.offline {
+			commitTUI.SetRegenerate(func(instruction string) (string, error) {
+				// Regenerating always asks the provider for a fresh answer
+				regenerate := request
+				regenerate.NoCache = true
+				regenerate.Instruction = instruction
+				regenerated, err := llm.GenerateCommitMessage(cfg, diff, regenerate)
+				if err != nil {
+					return "", err
+				}
+				result = regenerated
+				commitMessage = addTrailers(opts, markBreaking(output.ParseCommitMessage(regenerated.Content), request.Breaking), withTrailers)
+				return commitMessage, nil
+			})
+		}
+		commitTUI.SetCoAuthors(roster.Aliases(), func(message, refs string) (string, error) {
+			members, err := roster.Resolve(refs)
+			if err != nil {
+				return "", err
+			}
+			return trailers.Apply(message, trailers.CoAuthors(members))
+		})
+		finalMessage, shouldCommit, err = commitTUI.ShowCommitEditor()
+		if err != nil {
+			opts.fail(output.ExitError, "Error in commit editor: %v", err)
+		}
+
+		entry.Regenerations = commitTUI.Regenerations()
+		switch {
+		case !shouldCommit:
+			entry.Action = history.ActionRejected
+		case commitTUI.Edited():
+			entry.Action = history.ActionEdited
+		default:
+			entry.Action = history.ActionAccepted
+		}
+
+		if !shouldCommit {
+			entry.RawResponse = result.Content
+			entry.Message = commitMessage
+			recordHistory(cfg, entry, result)
+			fmt.Fprintln(opts.diag(), i18n.T("Commit cancelled"))
+			if opts.machine() {
+				writeCommitReport(opts, commitMessage, result, false, entry.Action)
+				exit(output.ExitCancelled)
+			}
+			return false
+		}
+	}
+
+	entry.RawResponse = result.Content
+	entry.Message = commitMessage
+	entry.FinalMessage = finalMessage
+
+	// Perform the commit; git and hook output is shown as it happens
+	fmt.Fprintln(opts.diag(), "")
+	fmt.Fprintln(opts.diag(), i18n.T("Committing changes..."))
+	err = git.Commit(finalMessage, commitOptions(cfg, opts))
+	if err != nil {
+		entry.Action = history.ActionFailed
+		recordHistory(cfg, entry, result)
+		opts.fail(output.ExitCommit, "Commit failed: %v", err)
+	}
+
+	recordHistory(cfg, entry, result)
+
+	if opts.machine() {
+		writeCommitReport(opts, finalMessage, result, true, entry.Action)
+		return true
+	}
+
+	fmt.Println(i18n.T("Commit successful!"))
+	i18n.Printf("Committed with message: %s\n", finalMessage)
+	return true
+}
+
+// generateMessage asks the model for a message. With --offline, or when every
+// profile failed and the offline fallback is enabled, the message is built
+// from the changed files instead.
+func generateMessage(cfg *config.Config, opts options, diff string, request llm.Options) *llm.Result {
+	if opts.offline {
+		return heuristicMessage(cfg, opts)
+	}
+
+	request.NoCache = opts.noCache
+	result, err := llm.GenerateCommitMessage(cfg, diff, request)
+	if err == nil {
+		return checkLanguage(cfg, diff, request, result)
+	}
+	if !cfg.Offline.IsFallbackEnabled() {
+		opts.fail(output.ExitProvider, "Error generating commit message: %v", err)
+	}
+	i18n.Fprintf(os.Stderr, "Error generating commit message: %v\n", err)
+	fmt.Fprintln(os.Stderr, i18n.T("Falling back to a heuristic message built from the changed files."))
+	return heuristicMessage(cfg, opts)
+}
+
+// checkLanguage asks once more when a message isn't in the configured
+// language, and warns when the second answer isn't either
+func checkLanguage(cfg *config.Config, diff string, request llm.Options, result *llm.Result) *llm.Result {
+	problem := languageProblem(cfg, result.Content)
+	if problem == "" {
+		return result
+	}
+	llm.Notify("The message %s; asking again.\n", problem)
+	request.NoCache = true
+	request.Instruction = strings.TrimSpace(request.Instruction + " Your previous answer " + problem + ".")
+	retried, err := llm.GenerateCommitMessage(cfg, diff, request)
+	if err != nil {
+		llm.Notify(i18n.T("Warning: %v\n"), err)
+		return result
+	}
+	if problem := languageProblem(cfg, retried.Content); problem != "" {
+		llm.Notify("Warning: the message %s.\n", problem)
+	}
+	return retried
+}
+
+// languageProblem tells how a message misses the configured language, e.g.
+// "isn't written in German"; it's empty when the message fits or no
+// language is configured. The check is heuristic, see i18n.Matches.
+func languageProblem(cfg *config.Config, content string) string {
+	code := i18n.Normalize(cfg.CommitTemplate.Language)
+	if code == "" {
+		return ""
+	}
+	message := output.ParseConventional(output.ParseCommitMessage(content))
+	if message.Type != "" && !slices.Contains(output.ConventionalTypes, strings.ToLower(message.Type)) {
+		return fmt.Sprintf("translated the Conventional Commit type (%q instead of feat, fix, ...)", message.Type)
+	}
+	if !i18n.Matches(message.Subject+"\n"+message.Body, code) {
+		return "isn't written in " + i18n.Name(code)
+	}
+	return ""
+}
+
+// heuristicNotice flags messages that no model has seen
+const heuristicNotice = "Heuristic message built from the changed files (no model was used)"
+
+// heuristicMessage builds a message from the changed files without a model
+func heuristicMessage(cfg *config.Config, opts options) *llm.Result {
+	start := time.Now()
+	changes, err := git.Changes(opts.all)
+	if err != nil {
+		opts.fail(output.ExitError, "Error reading changes: %v", err)
+	}
+	message := heuristic.Message(changes, heuristic.Options{
+		MaxLength:    cfg.CommitTemplate.MaxLength,
+		IncludeScope: cfg.CommitTemplate.IncludeScope,
+	})
+	return &llm.Result{
+		Content:   message,
+		Profile:   llm.HeuristicProfile,
+		Model:     llm.HeuristicModel,
+		Heuristic: true,
+		Latency:   time.Since(start),
+	}
+}
+
+// getChanges returns the diff to generate a message for: the staged changes,
+// or with --all every change to tracked files. With --pick, or when nothing
+// is staged and the user can be asked, the user picks files to stage first.
+func getChanges(opts options) string {
+	if opts.all {
+		changes, err := git.GetAllChanges()
+		if err != nil {
+			opts.fail(output.ExitError, "Error getting changes: %v", err)
+		}
+		return changes
+	}
+
+	changes, err := git.GetStagedChanges()
+	if err != nil {
+		opts.fail(output.ExitError, "Error getting staged changes: %v", err)
+	}
+	if opts.pick || (strings.TrimSpace(changes) == "" && !opts.bypass && opts.interactive()) {
+		if pickAndStage(opts) {
+			changes, err = git.GetStagedChanges()
+			if err != nil {
+				opts.fail(output.ExitError, "Error getting staged changes: %v", err)
+			}
+		}
+	}
+	return changes
+}
+
+// promptDiff returns the diff sent to the model. Unless disabled it is
+// enriched with file headers and the enclosing symbol of every hunk; extra
+// context is dropped first, then the enrichment, to stay within the budget.
+func promptDiff(cfg *config.Config, opts options, changes string) string {
+	if !cfg.Diff.IsEnrichEnabled() {
+		return changes
+	}
+
+	contexts := []int{cfg.Diff.ContextLines}
+	if cfg.Diff.ContextLines > 0 {
+		contexts = append(contexts, 0)
+	}
+	for _, context := range contexts {
+		enriched, err := git.EnrichedDiff(git.EnrichOptions{All: opts.all, Context: context})
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Warning: failed to enrich diff: %v\n", err)
+			return changes
+		}
+		if cfg.Diff.MaxTokens == 0 || tokens.Count(enriched, cfg.Active().Model) <= cfg.Diff.MaxTokens {
+			return enriched
+		}
+	}
+	return changes
+}
+
+// breakingChanges describes the changes to exported Go identifiers that
+// break callers, unless the check is disabled
+func breakingChanges(cfg *config.Config, opts options) []string {
+	if !cfg.Diff.IsAPICheckEnabled() {
+		return nil
+	}
+	report, err := apidiff.Compare(apidiff.Options{All: opts.all, Internal: cfg.Diff.APIInternal})
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: failed to compare Go APIs: %v\n", err)
+		return nil
+	}
+	for _, unknown := range report.Unknown {
+		i18n.Fprintf(os.Stderr, "Warning: the API of %s wasn't compared, nothing in it is marked as breaking: %s\n", unknown.Package, unknown.Error)
+	}
+	var breaking []string
+	for _, change := range report.Breaking() {
+		breaking = append(breaking, change.String())
+	}
+	return breaking
+}
+
+// changeNotes describes what the staged changes combine: the squash set up
+// by gitr squash, or the merge in progress
+func changeNotes(opts options) string {
+	if opts.notes != "" {
+		return opts.notes
+	}
+	state, err := git.Merging()
+	if err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: failed to read the merge state: %v\n", err)
+		return ""
+	}
+	if state == nil {
+		return ""
+	}
+	if len(state.Unmerged) > 0 {
+		opts.failWithHint(output.ExitError, "Resolve the conflicts and stage the files first",
+			"The merge has unresolved conflicts in %s", strings.Join(state.Unmerged, ", "))
+	}
+
+	var commits []merge.Commit
+	for _, head := range state.Heads {
+		merged, err := merge.Commits("HEAD.." + head)
+		if err != nil {
+			i18n.Fprintf(os.Stderr, "Warning: failed to list the merged commits: %v\n", err)
+		}
+		commits = append(commits, merged...)
+	}
+	return merge.MergeNotes(state, commits)
+}
+
+// markBreaking makes sure a message for a breaking change says so, even when
+// the model or the heuristic didn't
+func markBreaking(message string, breaking []string) string {
+	if len(breaking) == 0 || message == "" {
+		return message
+	}
+	description := breaking[0]
+	if len(breaking) > 1 {
+		description = fmt.Sprintf("%s (and %d more API changes)", description, len(breaking)-1)
+	}
+	return output.MarkBreaking(message, description)
+}
+
+// pickAndStage lets the user stage modified and untracked files and reports
+// whether anything was staged
+func pickAndStage(opts options) bool {
+	files, err := git.Status()
+	if err != nil {
+		opts.fail(output.ExitError, "Error listing changes: %v", err)
+	}
+	var unstaged []git.FileStatus
+	for _, f := range files {
+		if f.HasUnstaged() {
+			unstaged = append(unstaged, f)
+		}
+	}
+	if len(unstaged) == 0 {
+		fmt.Fprintln(opts.diag(), i18n.T("No unstaged changes to pick from"))
+		return false
+	}
+
+	paths, err := output.PickFiles(unstaged, opts.diag())
+	if err != nil {
+		opts.fail(output.ExitError, "Error picking files: %v", err)
+	}
+	if len(paths) == 0 {
+		return false
+	}
+
+	if err := git.Stage(paths); err != nil {
+		opts.fail(output.ExitError, "Error staging files: %v", err)
+	}
+	i18n.Fprintf(opts.diag(), "Staged %d file(s)\n", len(paths))
+	return true
+}
+
+// loadRoster loads the team roster used to resolve co-authors
+func loadRoster(cfg *config.Config, opts options) *trailers.Roster {
+	roster, err := trailers.LoadRoster(cfg.Commit.Roster)
+	if err != nil {
+		opts.failWithHint(output.ExitConfig, "Fix the roster or change commit.roster with 'gitr config set'.", "Configuration error: %v", err)
+	}
+	return roster
+}
+
+// coAuthorTrailers resolves --with against the team roster
+func coAuthorTrailers(cfg *config.Config, opts options) []trailers.Trailer {
+	if opts.with == "" {
+		return nil
+	}
+	members, err := loadRoster(cfg, opts).Resolve(opts.with)
+	if err != nil {
+		opts.fail(output.ExitUsage, "Error: %v", err)
+	}
+	return trailers.CoAuthors(members)
+}
+
+// addTrailers merges trailers into a generated message
+func addTrailers(opts options, message string, list []trailers.Trailer) string {
+	if message == "" {
+		return message
+	}
+	withTrailers, err := trailers.Apply(message, list)
+	if err != nil {
+		opts.fail(output.ExitError, "Error adding trailers: %v", err)
+	}
+	return withTrailers
+}
+
+// completeCoAuthors completes --with from the team roster
+func completeCoAuthors(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _ := loadConfigFile()
+	roster, err := trailers.LoadRoster(cfg.Commit.Roster)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	// Complete the last entry of a comma-separated list
+	prefix := ""
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix = toComplete[:i+1]
+	}
+	var completions []string
+	for _, alias := range roster.Aliases() {
+		completions = append(completions, prefix+alias)
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// commitOptions combines the commit flags with the commit settings from the configuration
+func commitOptions(cfg *config.Config, opts options) git.CommitOptions {
+	commit := git.CommitOptions{
+		Cleanup:    cfg.Commit.Cleanup,
+		Signoff:    cfg.Commit.Signoff || signoff,
+		SigningKey: cfg.Commit.SigningKey,
+		NoVerify:   cfg.Commit.NoVerify || noVerify,
+		Author:     author,
+		All:        opts.all,
+		Stdout:     opts.diag(),
+		Stderr:     os.Stderr,
+	}
+	if cleanupMode != "" {
+		commit.Cleanup = cleanupMode
+	}
+
+	// Unset gpg_sign leaves the decision to git's commit.gpgsign
+	if cfg.Commit.GPGSign != nil {
+		commit.Sign = *cfg.Commit.GPGSign
+		commit.NoSign = !*cfg.Commit.GPGSign
+	}
+	switch {
+	case noSign:
+		commit.Sign, commit.NoSign = false, true
+	case signKey != "":
+		commit.Sign, commit.NoSign = true, false
+		if signKey != signDefaultKey {
+			commit.SigningKey = signKey
+		}
+	}
+	if !commit.Sign {
+		commit.SigningKey = ""
+	}
+	if commit.Cleanup == "default" {
+		commit.Cleanup = ""
+	}
+
+	return commit
+}
+
+// writeCommitReport prints the outcome of a commit run in a machine-readable format
+func writeCommitReport(opts options, message string, result *llm.Result, committed bool, action string) {
+	report := output.NewReport(message, result)
+	report.Committed = &committed
+	report.Action = action
+
+	// Raw output is the committed message, so there's nothing to print for a rejected one
+	if opts.format == output.FormatRaw && !committed {
+		return
+	}
+	output.WriteReport(os.Stdout, opts.format, report)
+}
+
+// recordHistory stores a generation in the local history, unless disabled
+func recordHistory(cfg *config.Config, entry history.Entry, result *llm.Result) {
+	if !cfg.History.IsEnabled() {
+		return
+	}
+
+	entry.Repo, _ = git.RepoRoot()
+	entry.Profile = result.Profile
+	entry.Model = result.Model
+	entry.Cached = result.Cached
+
+	if err := history.Record(entry); err != nil {
+		i18n.Fprintf(os.Stderr, "Warning: failed to record history: %v\n", err)
+	}
+}
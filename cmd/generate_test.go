@@ -0,0 +1,251 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"gitr/internal/fakeopenai"
+	"gitr/internal/gitfixture"
+	"gitr/internal/i18n"
+	"gitr/internal/output"
+)
+
+// exitCode is what the exit hook panics with during tests
+type exitCode int
+
+// setup starts a fake provider with the replies and enters a repository with
+// one commit and a staged change that uses it
+func setup(t *testing.T, replies ...fakeopenai.Reply) (*fakeopenai.Server, *gitfixture.Repo) {
+	t.Helper()
+	server := fakeopenai.New(replies...)
+	t.Cleanup(server.Close)
+
+	repo := gitfixture.New(t)
+	repo.Commit("chore: initial commit", map[string]string{"README.md": "# demo\n"})
+	repo.Enter()
+	repo.WriteConfig(gitfixture.ProviderConfig(server.URL, "gpt-4o-mini"))
+	repo.Write("api/hello.go", "package api\n\nfunc Hello() string { return \"hello\" }\n")
+	repo.Stage("api")
+	return server, repo
+}
+
+// run calls fn with the answers as stdin and returns what it printed on
+// stdout and the exit code it ended with, -1 when it returned normally
+func run(t *testing.T, answers string, fn func()) (stdout string, code int) {
+	t.Helper()
+	output.SetInput(strings.NewReader(answers))
+	t.Cleanup(func() { output.SetInput(os.Stdin) })
+
+	previousExit := exit
+	exit = func(code int) { panic(exitCode(code)) }
+	defer func() { exit = previousExit }()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	previousStdout := os.Stdout
+	os.Stdout = writer
+	printed := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		printed <- string(data)
+	}()
+
+	code = -1
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				c, ok := r.(exitCode)
+				if !ok {
+					panic(r)
+				}
+				code = int(c)
+			}
+		}()
+		fn()
+	}()
+
+	os.Stdout = previousStdout
+	writer.Close()
+	return <-printed, code
+}
+
+func TestGenerateAndCommit(t *testing.T) {
+	tests := []struct {
+		name        string
+		replies     []fakeopenai.Reply
+		opts        options
+		answers     string
+		wantMessage string
+		wantCalls   int
+	}{
+		{
+			name:        "accept",
+			replies:     []fakeopenai.Reply{{Content: "feat(api): add greeting"}},
+			answers:     "a\n",
+			wantMessage: "feat(api): add greeting",
+			wantCalls:   1,
+		},
+		{
+			name:        "accept with enter",
+			replies:     []fakeopenai.Reply{{Content: "feat(api): add greeting"}},
+			answers:     "\n",
+			wantMessage: "feat(api): add greeting",
+			wantCalls:   1,
+		},
+		{
+			name:        "edit",
+			replies:     []fakeopenai.Reply{{Content: "feat(api): add greeting"}},
+			answers:     "e\nfeat(api): say hello\na\n",
+			wantMessage: "feat(api): say hello",
+			wantCalls:   1,
+		},
+		{
+			name:        "regenerate",
+			replies:     []fakeopenai.Reply{{Content: "chore: update files"}, {Content: "feat(api): add Hello"}},
+			answers:     "g\na\n",
+			wantMessage: "feat(api): add Hello",
+			wantCalls:   2,
+		},
+		{
+			name:        "bypass",
+			replies:     []fakeopenai.Reply{{Content: "feat(api): add greeting"}},
+			opts:        options{bypass: true},
+			wantMessage: "feat(api): add greeting",
+			wantCalls:   1,
+		},
+		{
+			name:        "code fences are stripped",
+			replies:     []fakeopenai.Reply{{Content: "```\nfeat(api): add greeting\n```"}},
+			answers:     "a\n",
+			wantMessage: "feat(api): add greeting",
+			wantCalls:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, repo := setup(t, tt.replies...)
+			tt.opts.format = output.FormatText
+
+			stdout, code := run(t, tt.answers, func() { generateAndCommit(tt.opts) })
+			if code != -1 {
+				t.Fatalf("exit code %d\n%s", code, stdout)
+			}
+			if got := repo.HeadMessage(); got != tt.wantMessage {
+				t.Errorf("committed %q, want %q", got, tt.wantMessage)
+			}
+			if got := len(server.Requests()); got != tt.wantCalls {
+				t.Errorf("provider calls = %d, want %d", got, tt.wantCalls)
+			}
+			if !strings.Contains(stdout, "Commit successful!") {
+				t.Errorf("output doesn't report the commit:\n%s", stdout)
+			}
+		})
+	}
+}
+
+func TestRegenerateSendsInstruction(t *testing.T) {
+	server, repo := setup(t, fakeopenai.Reply{Content: "chore: update"}, fakeopenai.Reply{Content: "feat(api): add Hello"})
+
+	_, code := run(t, "i\nmention the API\na\n", func() { generateAndCommit(options{format: output.FormatText}) })
+	if code != -1 {
+		t.Fatalf("exit code %d", code)
+	}
+	if got := repo.HeadMessage(); got != "feat(api): add Hello" {
+		t.Errorf("committed %q", got)
+	}
+	last, _ := server.LastRequest()
+	if !strings.Contains(last.Messages[0].Content, "mention the API") {
+		t.Errorf("the instruction isn't in the prompt:\n%s", last.Messages[0].Content)
+	}
+}
+
+func TestRejectWithJSON(t *testing.T) {
+	_, repo := setup(t, fakeopenai.Reply{Content: "feat(api): add greeting"})
+	head := repo.Git("rev-parse", "HEAD")
+
+	stdout, code := run(t, "r\n", func() { generateAndCommit(options{format: output.FormatJSON}) })
+	if code != output.ExitCancelled {
+		t.Fatalf("exit code %d, want %d", code, output.ExitCancelled)
+	}
+	if got := repo.Git("rev-parse", "HEAD"); got != head {
+		t.Error("a rejected message was committed")
+	}
+
+	var report output.Report
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("stdout isn't a JSON report: %v\n%s", err, stdout)
+	}
+	if report.Committed == nil || *report.Committed || report.Action != "rejected" || report.Message != "feat(api): add greeting" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestNoStagedChanges(t *testing.T) {
+	server, repo := setup(t, fakeopenai.Reply{Content: "feat: unused"})
+	repo.Git("reset", "--quiet")
+
+	t.Run("commit", func(t *testing.T) {
+		stdout, code := run(t, "", func() { generateAndCommit(options{format: output.FormatJSON}) })
+		if code != output.ExitNoChanges {
+			t.Fatalf("exit code %d, want %d\n%s", code, output.ExitNoChanges, stdout)
+		}
+		if !strings.Contains(stdout, `"no_staged_changes"`) {
+			t.Errorf("error report = %s", stdout)
+		}
+	})
+	t.Run("generate", func(t *testing.T) {
+		stdout, code := run(t, "", func() { generateCommitMessage(options{format: output.FormatText}) })
+		if code != -1 || !strings.Contains(stdout, "No staged changes found") {
+			t.Errorf("exit code %d, output:\n%s", code, stdout)
+		}
+	})
+	if got := len(server.Requests()); got != 0 {
+		t.Errorf("provider calls = %d, want none", got)
+	}
+}
+
+func TestGenerateOnly(t *testing.T) {
+	server, repo := setup(t, fakeopenai.Reply{Content: "feat(api): add greeting"})
+	head := repo.Git("rev-parse", "HEAD")
+
+	stdout, code := run(t, "", func() { generateCommitMessage(options{format: output.FormatRaw}) })
+	if code != -1 {
+		t.Fatalf("exit code %d", code)
+	}
+	if strings.TrimSpace(stdout) != "feat(api): add greeting" {
+		t.Errorf("raw output = %q", stdout)
+	}
+	if got := repo.Git("rev-parse", "HEAD"); got != head {
+		t.Error("generating committed")
+	}
+	request, _ := server.LastRequest()
+	if !strings.Contains(request.Messages[1].Content, "api/hello.go") {
+		t.Errorf("the staged diff isn't in the prompt:\n%s", request.Messages[1].Content)
+	}
+}
+
+func TestTranslatedOutput(t *testing.T) {
+	_, repo := setup(t, fakeopenai.Reply{Content: "feat(api): add greeting"})
+	i18n.SetLanguage("de")
+	defer i18n.SetLanguage("")
+
+	stdout, code := run(t, "a\n", func() { generateAndCommit(options{format: output.FormatText}) })
+	if code != -1 {
+		t.Fatalf("exit code %d\n%s", code, stdout)
+	}
+	if !strings.Contains(stdout, "Commit erfolgreich!") || !strings.Contains(stdout, "Committet mit der Nachricht: feat(api): add greeting") {
+		t.Errorf("output isn't in German:\n%s", stdout)
+	}
+
+	repo.Git("reset", "--quiet")
+	stdout, code = run(t, "", func() { generateAndCommit(options{format: output.FormatJSON}) })
+	if code != output.ExitNoChanges || !strings.Contains(stdout, `"no_staged_changes"`) || !strings.Contains(stdout, "Keine vorgemerkten Änderungen gefunden") {
+		t.Errorf("exit code %d, error report:\n%s", code, stdout)
+	}
+}
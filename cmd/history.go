@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"gitr/internal/git"
+	"gitr/internal/history"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	historySearch   string
+	historyModel    string
+	historyLimit    int
+	historyThisRepo bool
+	historyDetails  bool
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List generated and committed messages",
+	Long: `List the local history of generated commit messages, newest first,
+including what happened to each message (accepted, edited, rejected, ...).`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := history.List(historyFilter(historyLimit))
+		if err != nil {
+			fmt.Printf("Error reading history: %v\n", err)
+			exit(1)
+		}
+
+		if len(entries) == 0 {
+			fmt.Println("No history entries found")
+			return
+		}
+
+		for _, entry := range entries {
+			fmt.Printf("%s  %-9s  %-20s  %s\n",
+				entry.Timestamp.Local().Format("2006-01-02 15:04"), entry.Action, entry.Model, firstLine(entry.Message))
+			if entry.FinalMessage != "" && entry.FinalMessage != entry.Message {
+				fmt.Printf("%s  %-9s  %-20s  %s\n", strings.Repeat(" ", 16), "", "-> committed", firstLine(entry.FinalMessage))
+			}
+			if historyDetails {
+				fmt.Printf("    id: %s  repo: %s  profile: %s  diff: %.12s  cached: %t\n",
+					entry.ID, entry.Repo, entry.Profile, entry.DiffHash, entry.Cached)
+			}
+		}
+	},
+}
+
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Report acceptance and edit rates per model",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stats, err := history.Stats(historyFilter(0))
+		if err != nil {
+			fmt.Printf("Error reading history: %v\n", err)
+			exit(1)
+		}
+
+		if len(stats) == 0 {
+			fmt.Println("No history entries found")
+			return
+		}
+
+		fmt.Printf("%-24s %7s %9s %9s %9s %9s %9s\n", "MODEL", "TOTAL", "REVIEWED", "ACCEPTED", "EDITED", "REJECTED", "BYPASSED")
+		for _, s := range stats {
+			fmt.Printf("%-24s %7d %9d %8.0f%% %8.0f%% %8.0f%% %9d\n",
+				s.Model, s.Total, s.Reviewed(),
+				s.AcceptanceRate()*100, s.EditRate()*100, s.RejectionRate()*100,
+				s.Actions[history.ActionBypassed])
+		}
+		fmt.Println("")
+		fmt.Println("Rates are relative to messages reviewed in the commit dialog.")
+	},
+}
+
+func init() {
+	historyCmd.Flags().StringVarP(&historySearch, "search", "s", "", "Only show messages containing this text")
+	historyCmd.Flags().IntVarP(&historyLimit, "limit", "n", 20, "Maximum number of entries to show (0 for all)")
+	historyCmd.Flags().BoolVar(&historyDetails, "details", false, "Show entry details")
+
+	for _, c := range []*cobra.Command{historyCmd, statsCmd} {
+		c.Flags().StringVar(&historyModel, "filter-model", "", "Only include entries for this model")
+		c.Flags().BoolVar(&historyThisRepo, "repo", false, "Only include entries for the current repository")
+	}
+
+	rootCmd.AddCommand(historyCmd, statsCmd)
+}
+
+// historyFilter builds a history filter from the command line flags
+func historyFilter(limit int) history.Filter {
+	filter := history.Filter{
+		Model:  historyModel,
+		Search: historySearch,
+		Limit:  limit,
+	}
+
+	if historyThisRepo {
+		repo, err := git.RepoRoot()
+		if err != nil {
+			fmt.Println("Error: Not in a git repository")
+			exit(1)
+		}
+		filter.Repo = repo
+	}
+
+	return filter
+}
+
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return line
+}
@@ -0,0 +1,161 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/llm"
+	"gitr/internal/output"
+	"gitr/internal/review"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	reviewAll    bool
+	reviewSARIF  string
+	reviewFailOn string
+)
+
+var reviewCmd = &cobra.Command{
+	Use:   "review",
+	Short: "Review the staged changes for bugs and risks",
+	Long: `Send the staged changes to the model with a review prompt and list its
+findings: file, line, severity (high, medium or low), what is wrong and how to
+fix it. Use --format json for the findings as JSON, or --sarif to save them in
+the SARIF format that code scanning tools import.
+
+To review before every commit, pass --review to 'gitr -c' or set
+review.before_commit; findings at or above review.block_on stop the commit.`,
+	Example: `  gitr review
+  gitr review --all --sarif review.sarif
+  gitr review --fail-on medium --format json`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{profile: profileName, model: modelName, format: outputFormat, verbose: verbose, all: reviewAll}
+		if reviewFailOn != "" && !slices.Contains(config.BlockSeverities, reviewFailOn) {
+			opts.fail(output.ExitUsage, "Error: unknown severity %q (expected %s)", reviewFailOn, strings.Join(config.BlockSeverities, ", "))
+		}
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+
+		changes := getChanges(opts)
+		if strings.TrimSpace(changes) == "" {
+			opts.failWithHint(output.ExitNoChanges, "Stage some changes first with 'git add', or use --all", "No staged changes found")
+		}
+		cfg := loadConfig(opts)
+
+		fmt.Fprintln(os.Stderr, "Reviewing changes...")
+		findings, result, err := reviewChanges(cfg, opts, changes)
+		if err != nil {
+			opts.fail(output.ExitProvider, "Error reviewing changes: %v", err)
+		}
+		opts.report(result)
+
+		if reviewSARIF != "" {
+			file, err := os.Create(reviewSARIF)
+			if err == nil {
+				err = review.WriteSARIF(file, findings)
+				if closeErr := file.Close(); err == nil {
+					err = closeErr
+				}
+			}
+			if err != nil {
+				opts.fail(output.ExitError, "Error writing SARIF report: %v", err)
+			}
+		}
+
+		if outputFormat == output.FormatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(struct {
+				Version  int              `json:"version"`
+				Findings []review.Finding `json:"findings"`
+			}{output.ReportVersion, findings})
+		} else {
+			printFindings(os.Stdout, findings)
+		}
+
+		if reviewFailOn != "" && len(review.AtLeast(findings, reviewFailOn)) > 0 {
+			exit(output.ExitReview)
+		}
+	},
+}
+
+func init() {
+	reviewCmd.Flags().BoolVarP(&reviewAll, "all", "a", false, "Review unstaged changes to tracked files too")
+	reviewCmd.Flags().StringVar(&reviewSARIF, "sarif", "", "Also write the findings to a SARIF file")
+	reviewCmd.Flags().StringVar(&reviewFailOn, "fail-on", "", "Exit with 9 when there are findings of this severity or worse: "+strings.Join(config.BlockSeverities, ", "))
+	reviewCmd.RegisterFlagCompletionFunc("fail-on", cobra.FixedCompletions(config.BlockSeverities, cobra.ShellCompDirectiveNoFileComp))
+	rootCmd.AddCommand(reviewCmd)
+}
+
+// reviewChanges asks the model to review the changes and parses its findings
+func reviewChanges(cfg *config.Config, opts options, changes string) ([]review.Finding, *llm.Result, error) {
+	diff := review.NumberLines(promptDiff(cfg, opts, changes))
+	result, err := llm.CompleteDiff(cfg, review.Instruction, review.Prompt(diff), diff)
+	if err != nil {
+		return nil, nil, err
+	}
+	findings, err := review.Parse(result.Content)
+	if err != nil {
+		return nil, nil, err
+	}
+	return findings, result, nil
+}
+
+// reviewBeforeCommit reviews the changes and stops the commit when a finding
+// is as serious as review.block_on or worse. A review that fails only warns.
+func reviewBeforeCommit(cfg *config.Config, opts options, changes string) {
+	if opts.offline {
+		fmt.Fprintln(os.Stderr, "Skipping the review: no model is used with --offline")
+		return
+	}
+	fmt.Fprintln(opts.diag(), "Reviewing changes...")
+	findings, result, err := reviewChanges(cfg, opts, changes)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: review failed, committing without it: %v\n", err)
+		return
+	}
+	opts.report(result)
+	printFindings(opts.diag(), findings)
+
+	severity := cfg.Review.BlockSeverity()
+	if blocking := review.AtLeast(findings, severity); len(blocking) > 0 {
+		opts.failWithHint(output.ExitReview, "Fix the findings, or commit anyway with --no-review",
+			"Commit blocked: %d findings of severity %s or worse", len(blocking), severity)
+	}
+	fmt.Fprintln(opts.diag(), "")
+}
+
+// printFindings lists the findings, most serious first
+func printFindings(w io.Writer, findings []review.Finding) {
+	if len(findings) == 0 {
+		fmt.Fprintln(w, "No problems found")
+		return
+	}
+	counts := map[string]int{}
+	for _, finding := range findings {
+		counts[finding.Severity]++
+		fmt.Fprintf(w, "%-6s  %s\n", strings.ToUpper(finding.Severity), finding.Location())
+		fmt.Fprintf(w, "        %s\n", finding.Message)
+		if finding.Suggestion != "" {
+			fmt.Fprintf(w, "        Suggestion: %s\n", finding.Suggestion)
+		}
+		fmt.Fprintln(w)
+	}
+	var summary []string
+	for _, severity := range []string{config.SeverityHigh, config.SeverityMedium, config.SeverityLow} {
+		if counts[severity] > 0 {
+			summary = append(summary, fmt.Sprintf("%d %s", counts[severity], severity))
+		}
+	}
+	fmt.Fprintf(w, "%d findings: %s\n", len(findings), strings.Join(summary, ", "))
+}
@@ -5,31 +5,62 @@ import (
 	"os"
 
 	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/llm"
+	"gitr/internal/output"
 
 	"github.com/spf13/cobra"
 )
 
+// exit ends the process with a status code. It can be replaced to run the
+// commands in-process, e.g. with a function that panics with the code.
+var exit = os.Exit
+
 var rootCmd = &cobra.Command{
 	Use:   "gitr",
 	Short: "AI-powered Git commit message generator",
 	Long: `GitR is a tool that uses LLMs to generate conventional commit messages based on your staged changes.
 
-Usage:
-  gitr                    Generate and display commit message
-  gitr --commit, -c       Generate commit message and commit with confirmation
-  gitr -c --bypass, -b    Generate message and commit without confirmation
-  gitr --help, -h         Show help information
-  gitr config             Open configuration editor
-
-Examples:
-  gitr                    # Just generate and show the commit message
-  gitr -c                 # Generate message and commit with TUI confirmation
-  gitr -c -b              # Generate message and commit without confirmation
-  gitr --commit --bypass  # Same as -c -b
-  gitr --help             # Show help information
-  gitr config             # Edit configuration settings`,
+Without a subcommand GitR generates a message for the staged changes and prints it,
+or commits with it when --commit is given.
+
+Configuration is read from .gitr_config in the current directory, ~/.gitr_config
+or ~/.config/gitr/config. Run 'gitr config' to set it up.`,
+	Example: `  gitr                    # Just generate and show the commit message
+  gitr -c                 # Generate message and commit with confirmation
+  gitr -cb                # Generate message and commit without confirmation
+  gitr -p release -c      # Commit using the 'release' profile
+  gitr --format json      # Print the message as JSON for scripts
+  gitr config             # Edit configuration settings
+  gitr config profile use local   # Make 'local' the default profile`,
+	Args: cobra.NoArgs,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		if !output.ValidFormat(outputFormat) {
+			format := outputFormat
+			outputFormat = output.FormatText
+			options{format: outputFormat}.fail(output.ExitUsage, "Error: unknown format %q (expected text, json or raw)", format)
+		}
+		if quiet {
+			llm.Notify = func(string, ...any) {}
+		}
+		if verbose {
+			llm.Trace = func(format string, args ...any) {
+				fmt.Fprintf(os.Stderr, format, args...)
+			}
+		}
+	},
+	Run:           runRoot,
+	SilenceErrors: true,
 }
 
+var (
+	profileName  string
+	modelName    string
+	outputFormat string
+	verbose      bool
+	quiet        bool
+)
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Configure GitR settings",
@@ -39,23 +70,138 @@ var configCmd = &cobra.Command{
 	},
 }
 
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage named provider profiles",
+	Long: `Manage named provider profiles (provider, endpoint, model and parameters).
+
+The profile used for a run is chosen in this order:
+  1. --profile flag
+  2. git config gitr.profile (per-repository pin)
+  3. default_profile from the configuration file`,
+}
+
+var profileListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List profiles",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg, _ := loadConfigFile()
+		if profileName == "" {
+			profileName = git.RepoProfile()
+		}
+		if err := cfg.SelectProfile(profileName); err != nil {
+			fmt.Printf("Warning: %v\n", err)
+		}
+		for _, name := range cfg.ProfileNames() {
+			settings, _ := cfg.Profile(name)
+			marker := " "
+			if name == cfg.ActiveProfileName() {
+				marker = "*"
+			}
+			provider := settings.Provider
+			if provider == "" {
+				provider = config.ProviderOpenAI
+			}
+			fmt.Printf("%s %-16s %-8s %-24s %s\n", marker, name, provider, settings.Model, settings.BaseURL)
+		}
+	},
+}
+
+var profileAddCmd = &cobra.Command{
+	Use:   "add <name>",
+	Short: "Add a profile with default settings",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfig(func(cfg *config.Config) error {
+			return cfg.AddProfile(args[0], config.CreateDefaultConfig().OpenAI)
+		})
+		fmt.Printf("Profile %s added. Edit it with 'gitr config --profile %s'.\n", args[0], args[0])
+	},
+}
+
+var profileCloneCmd = &cobra.Command{
+	Use:   "clone <source> <name>",
+	Short: "Copy an existing profile",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfig(func(cfg *config.Config) error {
+			return cfg.CloneProfile(args[0], args[1])
+		})
+		fmt.Printf("Profile %s cloned to %s.\n", args[0], args[1])
+	},
+}
+
+var profileDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a profile",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfig(func(cfg *config.Config) error {
+			return cfg.DeleteProfile(args[0])
+		})
+		fmt.Printf("Profile %s deleted.\n", args[0])
+	},
+}
+
+var profileUseCmd = &cobra.Command{
+	Use:   "use <name>",
+	Short: "Make a profile the default",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		updateConfig(func(cfg *config.Config) error {
+			return cfg.SetDefaultProfile(args[0])
+		})
+		fmt.Printf("Default profile set to %s.\n", args[0])
+	},
+}
+
 func init() {
+	rootCmd.PersistentFlags().StringVarP(&profileName, "profile", "p", "", "Provider profile to use (overrides default and repo pin)")
+	rootCmd.PersistentFlags().StringVarP(&modelName, "model", "m", "", "Model to use instead of the profile's model")
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "format", output.FormatText, "Output format: text, json or raw")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Report profile, model, token usage and latency on stderr")
+	rootCmd.PersistentFlags().BoolVarP(&quiet, "quiet", "q", false, "Hide retry, fallback and cache notices")
+	rootCmd.MarkFlagsMutuallyExclusive("verbose", "quiet")
+
+	rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles)
+	rootCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(
+		[]string{output.FormatText, output.FormatJSON, output.FormatRaw}, cobra.ShellCompDirectiveNoFileComp))
+
+	profileCmd.AddCommand(profileListCmd, profileAddCmd, profileCloneCmd, profileDeleteCmd, profileUseCmd)
+	configCmd.AddCommand(profileCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
+// Execute runs the command selected by the command line
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		// Flag and argument errors are usage errors
+		format := outputFormat
+		if !output.ValidFormat(format) {
+			format = output.FormatText
+		}
+		out := os.Stderr
+		if format == output.FormatJSON {
+			out = os.Stdout
+		}
+		output.WriteError(out, format, output.ExitUsage, "Error: "+err.Error(), "Run 'gitr --help' for usage.")
+		exit(output.ExitUsage)
 	}
 }
 
+// completeProfiles completes profile names from the configuration file
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, _ := loadConfigFile()
+	return cfg.ProfileNames(), cobra.ShellCompDirectiveNoFileComp
+}
+
 func runConfigEditor() {
 	// Find or create config file
 	configPath, err := config.FindConfigFile()
 	if err != nil {
 		fmt.Printf("Error finding config file: %v\n", err)
-		os.Exit(1)
+		exit(1)
 	}
 
 	// Load existing config or create default
@@ -69,7 +215,7 @@ func runConfigEditor() {
 		err = config.RunFirstTimeSetup(cfg, configPath)
 		if err != nil {
 			fmt.Printf("Error during setup: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 		return
 	} else {
@@ -77,14 +223,58 @@ func runConfigEditor() {
 		cfg, err = config.Load(configPath)
 		if err != nil {
 			fmt.Printf("Error loading config: %v\n", err)
-			os.Exit(1)
+			exit(1)
 		}
 	}
 
+	// Select the profile to edit
+	if profileName == "" {
+		profileName = git.RepoProfile()
+	}
+	if err := cfg.SelectProfile(profileName); err != nil {
+		fmt.Printf("Error selecting profile: %v\n", err)
+		exit(1)
+	}
+
 	// Run the configuration editor
 	err = config.RunConfigEditor(cfg, configPath)
 	if err != nil {
 		fmt.Printf("Error in configuration editor: %v\n", err)
-		os.Exit(1)
+		exit(1)
+	}
+}
+
+// loadConfigFile loads the configuration file, falling back to defaults when it doesn't exist yet
+func loadConfigFile() (*config.Config, string) {
+	configPath, err := config.FindConfigFile()
+	if err != nil {
+		fmt.Printf("Error finding config file: %v\n", err)
+		exit(1)
+	}
+
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		return config.CreateDefaultConfig(), configPath
+	}
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		exit(1)
+	}
+	return cfg, configPath
+}
+
+// updateConfig applies a change to the configuration file and saves it
+func updateConfig(change func(cfg *config.Config) error) {
+	cfg, configPath := loadConfigFile()
+
+	if err := change(cfg); err != nil {
+		fmt.Printf("Error: %v\n", err)
+		exit(1)
+	}
+
+	if err := cfg.Save(configPath); err != nil {
+		fmt.Printf("Error saving config: %v\n", err)
+		exit(1)
 	}
 }
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"gitr/internal/git"
+	"gitr/internal/merge"
+	"gitr/internal/output"
+
+	"github.com/spf13/cobra"
+)
+
+var squashCmd = &cobra.Command{
+	Use:   "squash <base>",
+	Short: "Squash the commits since base into one with a generated message",
+	Long: `Squash the commits of the current branch since it left <base> into one commit.
+The branch is soft-reset to the merge base of <base> and HEAD, one message is
+generated from the combined diff and the original commit messages, and the
+result is committed after you confirm it.
+
+If you cancel, interrupt it with Ctrl-C or anything fails, the branch is put
+back where it was. After a successful squash, 'git reset --soft ORIG_HEAD' brings the original commits back.`,
+	Example: `  gitr squash main
+  gitr squash HEAD~5 --bypass`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := generationOptions()
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+		if state, _ := git.Merging(); state != nil {
+			opts.fail(output.ExitUsage, "Error: a merge is in progress; finish it with 'gitr -c' first")
+		}
+		if staged, err := git.HasStagedChanges(); err != nil || staged {
+			opts.failWithHint(output.ExitUsage, "Commit or unstage them first", "Error: there are staged changes")
+		}
+
+		base, err := git.MergeBase(args[0], "HEAD")
+		if err != nil {
+			opts.fail(output.ExitUsage, "Error: no common ancestor of %s and HEAD", args[0])
+		}
+		head, err := git.HeadCommit()
+		if err != nil {
+			opts.fail(output.ExitError, "Error: %v", err)
+		}
+		total, err := git.CountCommits(base + "..HEAD")
+		if err != nil {
+			opts.fail(output.ExitError, "Error counting commits: %v", err)
+		}
+		if total == 0 {
+			opts.fail(output.ExitNoChanges, "Nothing to squash: HEAD has no commits since %s", args[0])
+		}
+		commits, err := merge.Commits(base + "..HEAD")
+		if err != nil {
+			opts.fail(output.ExitError, "Error reading commits: %v", err)
+		}
+		opts.notes = merge.SquashNotes(commits, total)
+
+		if err := git.ResetSoft(base); err != nil {
+			opts.fail(output.ExitError, "Error resetting to %s: %v", base, err)
+		}
+		fmt.Fprintf(opts.diag(), "Squashing %d commits onto %s\n", total, base[:12])
+
+		// Whatever ends the run before the commit puts the branch back. Once
+		// HEAD has moved on from base the squash was committed and stays.
+		var once sync.Once
+		restore := func() {
+			once.Do(func() {
+				if current, err := git.HeadCommit(); err == nil && current != base {
+					return
+				}
+				if err := git.ResetSoft(head); err != nil {
+					fmt.Fprintf(os.Stderr, "Error restoring the branch: %v (run 'git reset --soft %s')\n", err, head)
+					return
+				}
+				fmt.Fprintf(os.Stderr, "Squash cancelled; the branch is back at %s\n", head[:12])
+			})
+		}
+		previous := exit
+		exit = func(code int) {
+			restore()
+			previous(code)
+		}
+		defer func() { exit = previous }()
+
+		// Ctrl-C or a kill during generation would otherwise leave the branch reset
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		defer signal.Stop(signals)
+		go func() {
+			if _, ok := <-signals; ok {
+				restore()
+				os.Exit(output.ExitCancelled)
+			}
+		}()
+
+		if !generateAndCommit(opts) {
+			restore()
+		}
+	},
+}
+
+func init() {
+	squashCmd.Flags().BoolVar(&noCache, "no-cache", false, "Ignore cached responses and call the provider")
+	squashCmd.Flags().StringVar(&coAuthors, "with", "", "Add Co-authored-by trailers for team members (comma-separated aliases, names or emails)")
+	squashCmd.RegisterFlagCompletionFunc("with", completeCoAuthors)
+	squashCmd.Flags().BoolVar(&offline, "offline", false, "Build the message from the changed files with rules instead of a model")
+	squashCmd.Flags().StringVar(&langFlag, "lang", "", "Write the message in this language (e.g. de, ja), overriding the config")
+	rootCmd.AddCommand(squashCmd)
+}
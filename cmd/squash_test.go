@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gitr/internal/fakeopenai"
+	"gitr/internal/gitfixture"
+	"gitr/internal/output"
+)
+
+// TestSquashInterruptHelper runs gitr squash in the child process started by
+// TestSquashInterrupt
+func TestSquashInterruptHelper(t *testing.T) {
+	dir := os.Getenv("GITR_TEST_SQUASH_DIR")
+	if dir == "" {
+		t.Skip("only runs as the child of TestSquashInterrupt")
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	outputFormat = output.FormatText
+	bypass = true
+	squashCmd.Run(squashCmd, []string{"main"})
+}
+
+func TestSquashInterrupt(t *testing.T) {
+	server := fakeopenai.New(fakeopenai.Reply{Content: "feat: add everything", Delay: time.Minute})
+	t.Cleanup(server.Close)
+
+	repo := gitfixture.New(t)
+	repo.Commit("chore: initial commit", map[string]string{
+		".gitr_config": gitfixture.ProviderConfig(server.URL, "gpt-4o-mini"),
+	})
+	repo.Git("checkout", "--quiet", "-b", "feature")
+	repo.Commit("feat: add a", map[string]string{"a.txt": "a\n"})
+	head := repo.Commit("feat: add b", map[string]string{"b.txt": "b\n"})
+
+	home := t.TempDir()
+	child := exec.Command(os.Args[0], "-test.run=^TestSquashInterruptHelper$")
+	child.Env = append(os.Environ(), repo.Env()...)
+	child.Env = append(child.Env, "GITR_TEST_SQUASH_DIR="+repo.Dir, "HOME="+home,
+		"XDG_CONFIG_HOME="+home+"/config", "XDG_CACHE_HOME="+home+"/cache", "XDG_DATA_HOME="+home+"/data")
+	var stderr bytes.Buffer
+	child.Stderr = &stderr
+	if err := child.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Interrupt once the branch is reset and the provider has been asked
+	deadline := time.Now().Add(30 * time.Second)
+	for len(server.Requests()) == 0 {
+		if time.Now().After(deadline) {
+			child.Process.Kill()
+			t.Fatalf("the provider was never called\n%s", stderr.String())
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	child.Process.Signal(syscall.SIGINT)
+
+	var exitErr *exec.ExitError
+	if err := child.Wait(); !errors.As(err, &exitErr) || exitErr.ExitCode() != output.ExitCancelled {
+		t.Errorf("child ended with %v, want exit code %d\n%s", err, output.ExitCancelled, stderr.String())
+	}
+	if got := repo.Git("rev-parse", "HEAD"); got != head {
+		t.Errorf("HEAD = %s, want the branch back at %s", got, head)
+	}
+	if !strings.Contains(stderr.String(), "Squash cancelled") {
+		t.Errorf("stderr doesn't report the cancellation:\n%s", stderr.String())
+	}
+}
@@ -0,0 +1,211 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/heuristic"
+	"gitr/internal/i18n"
+	"gitr/internal/llm"
+	"gitr/internal/output"
+	"gitr/internal/stash"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	stashUntracked bool
+	stashKeepIndex bool
+	stashOffline   bool
+	stashDescribe  bool
+)
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Stash the local changes with a generated message",
+	Long: `Stash the local changes under a message that describes them, instead of
+git's "WIP on <branch>". The message is generated from the staged and unstaged
+changes to tracked files, and from the names of untracked files with
+--include-untracked. Same as 'gitr stash push'.
+
+Use 'gitr stash list --describe' to have unnamed stashes summarized.`,
+	Example: `  gitr stash
+  gitr stash push --include-untracked
+  gitr stash list --describe`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stashPush()
+	},
+}
+
+var stashPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Stash the local changes with a generated message",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stashPush()
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the stash entries",
+	Long: `List the stash entries like 'git stash list'. With --describe, entries saved
+without a message are summarized from their changes; the entries themselves
+are left as they are.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{profile: profileName, model: modelName, format: outputFormat, verbose: verbose, lang: langFlag}
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+		stashes, err := git.Stashes()
+		if err != nil {
+			opts.fail(output.ExitError, "Error listing stashes: %v", err)
+		}
+
+		type entry struct {
+			git.Stash
+			Description string `json:"description,omitempty"`
+		}
+		entries := make([]entry, len(stashes))
+		var cfg *config.Config
+		for i, s := range stashes {
+			entries[i].Stash = s
+			if !stashDescribe || !s.Unnamed() {
+				continue
+			}
+			if cfg == nil {
+				cfg = loadConfig(opts)
+			}
+			diff, err := git.StashDiff(s)
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Warning: can't read %s: %v\n", s.Ref, err)
+				continue
+			}
+			result, err := llm.CompleteDiff(cfg, stash.Instruction(cfg.CommitTemplate.MaxLength)+llm.LanguageInstruction(cfg), stash.Prompt(diff, nil), diff)
+			if err != nil {
+				i18n.Fprintf(os.Stderr, "Warning: can't describe %s: %v\n", s.Ref, err)
+				continue
+			}
+			opts.report(result)
+			entries[i].Description = stash.Clean(result.Content)
+		}
+
+		if outputFormat == output.FormatJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(struct {
+				Version int     `json:"version"`
+				Stashes []entry `json:"stashes"`
+			}{output.ReportVersion, entries})
+			return
+		}
+		for _, e := range entries {
+			fmt.Printf("%s: %s\n", e.Ref, e.Subject)
+			if e.Description != "" {
+				fmt.Printf("    %s\n", e.Description)
+			}
+		}
+	},
+}
+
+// stashPush generates a message for the local changes and stashes them
+func stashPush() {
+	opts := options{profile: profileName, model: modelName, format: outputFormat, verbose: verbose, offline: stashOffline, lang: langFlag}
+	if !git.IsRepository() {
+		opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+	}
+
+	diff, err := git.EnrichedDiff(git.EnrichOptions{All: true})
+	if err != nil {
+		opts.fail(output.ExitError, "Error reading changes: %v", err)
+	}
+	var untracked []string
+	if stashUntracked {
+		files, err := git.Status()
+		if err != nil {
+			opts.fail(output.ExitError, "Error reading status: %v", err)
+		}
+		for _, f := range files {
+			if f.Untracked {
+				untracked = append(untracked, f.Path)
+			}
+		}
+	}
+	if strings.TrimSpace(diff) == "" && len(untracked) == 0 {
+		opts.fail(output.ExitNoChanges, "No local changes to save")
+	}
+	cfg := loadConfig(opts)
+
+	message, result := stashMessage(cfg, opts, diff, untracked)
+	if result != nil {
+		opts.report(result)
+	}
+	if err := git.StashPush(message, git.StashOptions{IncludeUntracked: stashUntracked, KeepIndex: stashKeepIndex}); err != nil {
+		opts.fail(output.ExitError, "Error: %v", err)
+	}
+
+	switch outputFormat {
+	case output.FormatJSON:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(struct {
+			Version int    `json:"version"`
+			Message string `json:"message"`
+		}{output.ReportVersion, message})
+	case output.FormatRaw:
+		fmt.Println(message)
+	default:
+		i18n.Printf("Saved stash: %s\n", message)
+	}
+}
+
+// stashMessage asks the model for a stash message. With --offline, or when
+// every profile failed and the offline fallback is enabled, the header of a
+// heuristic commit message is used instead; the result is nil then.
+func stashMessage(cfg *config.Config, opts options, diff string, untracked []string) (string, *llm.Result) {
+	if !opts.offline {
+		result, err := llm.CompleteDiff(cfg, stash.Instruction(cfg.CommitTemplate.MaxLength)+llm.LanguageInstruction(cfg), stash.Prompt(diff, untracked), diff)
+		if err == nil {
+			if message := stash.Clean(result.Content); message != "" {
+				return message, result
+			}
+			err = fmt.Errorf("empty answer")
+		}
+		if !cfg.Offline.IsFallbackEnabled() {
+			opts.fail(output.ExitProvider, "Error generating stash message: %v", err)
+		}
+		i18n.Fprintf(os.Stderr, "Error generating stash message: %v\n", err)
+		fmt.Fprintln(os.Stderr, i18n.T("Falling back to a message built from the changed files."))
+	}
+
+	changes, err := git.Changes(true)
+	if err != nil {
+		opts.fail(output.ExitError, "Error reading changes: %v", err)
+	}
+	header, _, _ := strings.Cut(heuristic.Message(changes, heuristic.Options{MaxLength: cfg.CommitTemplate.MaxLength}), "\n")
+	if header == "" {
+		// Only untracked files changed
+		header = fmt.Sprintf("Add %d untracked files", len(untracked))
+	}
+	return header, nil
+}
+
+func init() {
+	for _, c := range []*cobra.Command{stashCmd, stashPushCmd} {
+		c.Flags().BoolVarP(&stashUntracked, "include-untracked", "u", false, "Also stash untracked files")
+		c.Flags().BoolVarP(&stashKeepIndex, "keep-index", "k", false, "Leave the staged changes in place")
+		c.Flags().BoolVar(&stashOffline, "offline", false, "Build the message from the changed files with rules instead of a model")
+	}
+	for _, c := range []*cobra.Command{stashCmd, stashPushCmd, stashListCmd} {
+		c.Flags().StringVar(&langFlag, "lang", "", "Write the message in this language (e.g. de, ja), overriding the config")
+	}
+	stashListCmd.Flags().BoolVar(&stashDescribe, "describe", false, "Summarize the changes of stashes saved without a message")
+	stashCmd.AddCommand(stashPushCmd, stashListCmd)
+	rootCmd.AddCommand(stashCmd)
+}
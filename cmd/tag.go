@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/llm"
+	"gitr/internal/output"
+	"gitr/internal/release"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	tagSign    bool
+	tagKey     string
+	tagNoSign  bool
+	tagForce   bool
+	tagBypass  bool
+	tagOffline bool
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag <name> [rev]",
+	Short: "Create an annotated tag with a generated message",
+	Long: `Create an annotated tag on rev (HEAD by default) whose message sums up the
+commits since the previous tag: a headline, then the highlights grouped into
+breaking changes, features, fixes, performance, documentation and other
+changes, following the Conventional Commit types of the messages.
+
+The message is shown for review first, as for commits; use --bypass to tag
+without confirmation. Use --sign or --local-user to sign the tag.`,
+	Example: `  gitr tag v1.4.0
+  gitr tag v1.4.0 release/1.4 --sign
+  gitr tag v1.4.0 --offline --bypass`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		opts := options{profile: profileName, model: modelName, format: outputFormat, verbose: verbose, bypass: tagBypass, offline: tagOffline, lang: langFlag}
+		name, rev := args[0], "HEAD"
+		if len(args) == 2 {
+			rev = args[1]
+		}
+		if !git.IsRepository() {
+			opts.fail(output.ExitNotRepository, "Error: Not in a git repository")
+		}
+		if err := git.CheckTagName(name); err != nil {
+			opts.fail(output.ExitUsage, "Error: %v", err)
+		}
+		if git.TagExists(name) && !tagForce {
+			opts.failWithHint(output.ExitUsage, "Use --force to replace it", "Error: tag %q already exists", name)
+		}
+
+		r, err := release.Load(name, rev)
+		if err != nil {
+			opts.fail(output.ExitUsage, "Error: %v", err)
+		}
+		if len(r.Groups) == 0 {
+			opts.fail(output.ExitNoChanges, "Nothing to tag: no commits since %s", r.Previous)
+		}
+		cfg := loadConfig(opts)
+		if r.Previous != "" {
+			fmt.Fprintf(opts.diag(), "Summarizing %d commits since %s...\n", r.Total, r.Previous)
+		} else {
+			fmt.Fprintf(opts.diag(), "Summarizing %d commits...\n", r.Total)
+		}
+
+		result := tagMessage(cfg, opts, r)
+		opts.report(result)
+		message := release.Clean(result.Content)
+		if message == "" {
+			opts.fail(output.ExitProvider, "Failed to generate tag message")
+		}
+
+		if !opts.bypass {
+			tagTUI := output.NewCommitTUI(message)
+			tagTUI.SetKind("tag")
+			tagTUI.SetOutput(opts.diag())
+			tagTUI.SetDiff(r.Diff)
+			tagTUI.SetMaxLength(cfg.CommitTemplate.MaxLength)
+			if result.Heuristic {
+				tagTUI.SetNotice(tagHeuristicNotice)
+			}
+			if !opts.offline {
+				tagTUI.SetRegenerate(func(instruction string) (string, error) {
+					system := release.Instruction(cfg.CommitTemplate.MaxLength) + llm.LanguageInstruction(cfg)
+					if instruction != "" {
+						system += "\nAdditional instruction from the user: " + instruction
+					}
+					regenerated, err := llm.Complete(cfg, system, release.Prompt(r))
+					if err != nil {
+						return "", err
+					}
+					result = regenerated
+					return release.Clean(regenerated.Content), nil
+				})
+			}
+			var ok bool
+			message, ok, err = tagTUI.ShowCommitEditor()
+			if err != nil {
+				opts.fail(output.ExitError, "Error in tag editor: %v", err)
+			}
+			if !ok {
+				fmt.Fprintln(opts.diag(), "Tag cancelled")
+				if opts.machine() {
+					exit(output.ExitCancelled)
+				}
+				return
+			}
+			fmt.Fprintln(opts.diag())
+		}
+
+		err = git.CreateTag(name, r.Rev, message, git.TagOptions{
+			Sign:       tagSign || tagKey != "",
+			SigningKey: tagKey,
+			NoSign:     tagNoSign,
+			Force:      tagForce,
+		})
+		if err != nil {
+			opts.fail(output.ExitError, "Error creating tag: %v", err)
+		}
+
+		switch outputFormat {
+		case output.FormatJSON:
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.Encode(struct {
+				Version  int             `json:"version"`
+				Tag      string          `json:"tag"`
+				Revision string          `json:"revision"`
+				Previous string          `json:"previous,omitempty"`
+				Commits  int             `json:"commits"`
+				Groups   []release.Group `json:"groups"`
+				Message  string          `json:"message"`
+				Profile  string          `json:"profile"`
+				Model    string          `json:"model"`
+			}{output.ReportVersion, name, r.Rev, r.Previous, r.Total, r.Groups, message, result.Profile, result.Model})
+		case output.FormatRaw:
+			fmt.Println(message)
+		default:
+			fmt.Printf("Created tag %s on %s\n", name, r.Rev[:12])
+		}
+	},
+}
+
+// tagHeuristicNotice flags tag messages that no model has seen
+const tagHeuristicNotice = "Tag message built from the commit subjects (no model was used)"
+
+// tagMessage asks the model for a tag message. With --offline, or when every
+// profile failed and the offline fallback is enabled, the commit subjects are
+// listed under their headings instead.
+func tagMessage(cfg *config.Config, opts options, r *release.Release) *llm.Result {
+	if !opts.offline {
+		result, err := llm.Complete(cfg, release.Instruction(cfg.CommitTemplate.MaxLength)+llm.LanguageInstruction(cfg), release.Prompt(r))
+		if err == nil {
+			return result
+		}
+		if !cfg.Offline.IsFallbackEnabled() {
+			opts.fail(output.ExitProvider, "Error generating tag message: %v", err)
+		}
+		fmt.Fprintf(os.Stderr, "Error generating tag message: %v\n", err)
+		fmt.Fprintln(os.Stderr, "Falling back to a message built from the commit subjects.")
+	}
+	return &llm.Result{
+		Content:   release.Message(r),
+		Profile:   llm.HeuristicProfile,
+		Model:     llm.HeuristicModel,
+		Heuristic: true,
+	}
+}
+
+func init() {
+	tagCmd.Flags().BoolVarP(&tagSign, "sign", "s", false, "Sign the tag (GPG or SSH, as configured in git)")
+	tagCmd.Flags().StringVarP(&tagKey, "local-user", "u", "", "Sign the tag with the given key")
+	tagCmd.Flags().BoolVar(&tagNoSign, "no-sign", false, "Don't sign the tag, even if tag.gpgSign is set")
+	tagCmd.Flags().BoolVarP(&tagForce, "force", "f", false, "Replace an existing tag of the same name")
+	tagCmd.Flags().BoolVarP(&tagBypass, "bypass", "b", false, "Create the tag without confirmation")
+	tagCmd.Flags().BoolVar(&tagOffline, "offline", false, "List the commit subjects instead of asking a model")
+	tagCmd.Flags().StringVar(&langFlag, "lang", "", "Write the release notes in this language (e.g. de, ja), overriding the config")
+	tagCmd.MarkFlagsMutuallyExclusive("sign", "no-sign")
+	tagCmd.MarkFlagsMutuallyExclusive("local-user", "no-sign")
+	rootCmd.AddCommand(tagCmd)
+}
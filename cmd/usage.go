@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+
+	"gitr/internal/ledger"
+
+	"github.com/spf13/cobra"
+)
+
+var usageMonth string
+
+var usageCmd = &cobra.Command{
+	Use:   "usage",
+	Short: "Show token usage and cost per month",
+	Long: `Show the requests, tokens and cost of every model per month. Token counts
+are the ones reported by the provider, not GitR's own estimates.
+
+Costs use the <pricing> table of the configuration, falling back to built-in
+list prices. Requests to models without a known price are counted but not priced.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		months, err := ledger.Months()
+		if err != nil {
+			fmt.Printf("Error reading usage: %v\n", err)
+			exit(1)
+		}
+
+		fmt.Printf("%-8s %-24s %8s %12s %12s %10s\n", "MONTH", "MODEL", "REQUESTS", "PROMPT", "COMPLETION", "COST")
+		shown, partial := 0, false
+		for _, totals := range months {
+			if usageMonth != "" && totals.Month != usageMonth {
+				continue
+			}
+			cost := fmt.Sprintf("$%.4f", totals.CostUSD)
+			if totals.Unpriced == totals.Requests {
+				cost = "-"
+			} else if totals.Unpriced > 0 {
+				cost += "*"
+				partial = true
+			}
+			fmt.Printf("%-8s %-24s %8d %12d %12d %10s\n",
+				totals.Month, totals.Model, totals.Requests, totals.PromptTokens, totals.CompletionTokens, cost)
+			shown++
+		}
+		if shown == 0 {
+			fmt.Println("No usage recorded yet")
+		}
+		if partial {
+			fmt.Println("* some requests used a model without a known price")
+		}
+	},
+}
+
+func init() {
+	usageCmd.Flags().StringVar(&usageMonth, "month", "", "Only show this month (YYYY-MM)")
+	rootCmd.AddCommand(usageCmd)
+}
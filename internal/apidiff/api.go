@@ -0,0 +1,245 @@
+package apidiff
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"slices"
+	"strings"
+)
+
+// object names an exported object, e.g. func New, method T.Get or field T.Name
+type object struct {
+	kind string
+	name string
+}
+
+func (o object) String() string {
+	return o.kind + " " + o.name
+}
+
+// owner returns the type a method, field or interface member belongs to
+func (o object) owner() (object, bool) {
+	switch o.kind {
+	case kindMethod, kindField, kindInterface, kindEmbedded:
+		typeName, _, _ := strings.Cut(o.name, ".")
+		return object{kindType, typeName}, true
+	}
+	return object{}, false
+}
+
+// api maps every exported object of a package to a description that
+// changes whenever the object changes incompatibly
+type api map[object]string
+
+// Object kinds
+const (
+	kindFunc      = "func"
+	kindMethod    = "method"
+	kindType      = "type"
+	kindField     = "field"
+	kindInterface = "interface method"
+	kindEmbedded  = "embedded interface"
+	kindConst     = "const"
+	kindVar       = "var"
+)
+
+// parsePackage collects the exported API of the files of one package and
+// returns it with the package name. A file that doesn't parse makes the API
+// incomplete, so the error of the first one is returned along with the name
+// found in the others.
+func parsePackage(files map[string]string) (api, string, error) {
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+
+	fset := token.NewFileSet()
+	objects := api{}
+	name := ""
+	var parseErr error
+	for _, path := range paths {
+		file, err := parser.ParseFile(fset, path, files[path], parser.SkipObjectResolution)
+		if err != nil {
+			if parseErr == nil {
+				parseErr = err
+			}
+			continue
+		}
+		// Files of an external test package, or stray mains, aren't part of it
+		if name != "" && file.Name.Name != name {
+			continue
+		}
+		name = file.Name.Name
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				addFunc(fset, objects, decl)
+			case *ast.GenDecl:
+				addGenDecl(fset, objects, decl)
+			}
+		}
+	}
+	return objects, name, parseErr
+}
+
+func addFunc(fset *token.FileSet, objects api, decl *ast.FuncDecl) {
+	if !decl.Name.IsExported() {
+		return
+	}
+	if decl.Recv == nil {
+		objects[object{kindFunc, decl.Name.Name}] = signature(fset, decl.Type)
+		return
+	}
+	receiver := decl.Recv.List[0].Type
+	pointer := ""
+	if star, ok := receiver.(*ast.StarExpr); ok {
+		pointer = "*"
+		receiver = star.X
+	}
+	typeName := baseTypeName(receiver)
+	if !ast.IsExported(typeName) {
+		return
+	}
+	objects[object{kindMethod, typeName + "." + decl.Name.Name}] = "(" + pointer + typeName + ") " + signature(fset, decl.Type)
+}
+
+func addGenDecl(fset *token.FileSet, objects api, decl *ast.GenDecl) {
+	for _, spec := range decl.Specs {
+		switch spec := spec.(type) {
+		case *ast.TypeSpec:
+			if spec.Name.IsExported() {
+				addType(fset, objects, spec)
+			}
+		case *ast.ValueSpec:
+			kind := kindVar
+			if decl.Tok == token.CONST {
+				kind = kindConst
+			}
+			description := kind
+			if spec.Type != nil {
+				description += " " + expr(fset, spec.Type)
+			}
+			for _, name := range spec.Names {
+				if name.IsExported() {
+					objects[object{kind, name.Name}] = description
+				}
+			}
+		}
+	}
+}
+
+func addType(fset *token.FileSet, objects api, spec *ast.TypeSpec) {
+	name := spec.Name.Name
+	params := ""
+	if spec.TypeParams != nil {
+		params = "[" + fieldTypes(fset, spec.TypeParams, true) + "]"
+	}
+	if spec.Assign.IsValid() {
+		objects[object{kindType, name}] = params + "= " + expr(fset, spec.Type)
+		return
+	}
+
+	switch t := spec.Type.(type) {
+	case *ast.StructType:
+		objects[object{kindType, name}] = params + "struct"
+		for _, field := range t.Fields.List {
+			fieldType := expr(fset, field.Type)
+			if len(field.Names) == 0 {
+				// An embedded field is named after its type
+				if embedded := baseTypeName(field.Type); ast.IsExported(embedded) {
+					objects[object{kindField, name + "." + embedded}] = fieldType
+				}
+				continue
+			}
+			for _, fieldName := range field.Names {
+				if fieldName.IsExported() {
+					objects[object{kindField, name + "." + fieldName.Name}] = fieldType
+				}
+			}
+		}
+	case *ast.InterfaceType:
+		objects[object{kindType, name}] = params + "interface"
+		for _, method := range t.Methods.List {
+			if len(method.Names) == 0 {
+				objects[object{kindEmbedded, name + "." + expr(fset, method.Type)}] = "embedded"
+				continue
+			}
+			for _, methodName := range method.Names {
+				// Unexported methods still have to be implemented
+				objects[object{kindInterface, name + "." + methodName.Name}] = signature(fset, method.Type.(*ast.FuncType))
+			}
+		}
+	default:
+		objects[object{kindType, name}] = params + expr(fset, spec.Type)
+	}
+}
+
+// signature describes a function type without parameter names, so renaming
+// a parameter isn't a change
+func signature(fset *token.FileSet, fn *ast.FuncType) string {
+	var b strings.Builder
+	if fn.TypeParams != nil {
+		b.WriteString("[" + fieldTypes(fset, fn.TypeParams, true) + "]")
+	}
+	b.WriteString("func(" + fieldTypes(fset, fn.Params, false) + ")")
+	if fn.Results != nil && len(fn.Results.List) > 0 {
+		results := fieldTypes(fset, fn.Results, false)
+		if len(fn.Results.List) > 1 || len(fn.Results.List[0].Names) > 1 {
+			results = "(" + results + ")"
+		}
+		b.WriteString(" " + results)
+	}
+	return b.String()
+}
+
+// fieldTypes lists the types of a field list, once per name. Type
+// parameters keep their names since constraints refer to them.
+func fieldTypes(fset *token.FileSet, fields *ast.FieldList, keepNames bool) string {
+	if fields == nil {
+		return ""
+	}
+	var types []string
+	for _, field := range fields.List {
+		fieldType := expr(fset, field.Type)
+		if keepNames {
+			for _, name := range field.Names {
+				types = append(types, name.Name+" "+fieldType)
+			}
+			continue
+		}
+		count := max(len(field.Names), 1)
+		for range count {
+			types = append(types, fieldType)
+		}
+	}
+	return strings.Join(types, ", ")
+}
+
+// expr prints an expression on one line
+func expr(fset *token.FileSet, node ast.Expr) string {
+	var buf bytes.Buffer
+	printer.Fprint(&buf, fset, node)
+	return strings.Join(strings.Fields(buf.String()), " ")
+}
+
+// baseTypeName returns the name of a receiver or embedded type such as
+// "*pkg.T[K]" without pointer, package or type arguments
+func baseTypeName(node ast.Expr) string {
+	switch t := node.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		return baseTypeName(t.X)
+	case *ast.SelectorExpr:
+		return t.Sel.Name
+	case *ast.IndexExpr:
+		return baseTypeName(t.X)
+	case *ast.IndexListExpr:
+		return baseTypeName(t.X)
+	}
+	return ""
+}
@@ -0,0 +1,253 @@
+// Package apidiff compares the exported API of the Go packages touched by a
+// change before and after it, to find changes that break their callers.
+package apidiff
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Kinds of change
+const (
+	Removed = "removed"
+	Renamed = "renamed"
+	Changed = "changed"
+	Added   = "added"
+)
+
+// Change is one difference in the exported API of a package
+type Change struct {
+	Package  string `json:"package"` // directory relative to the repository root
+	Kind     string `json:"kind"`
+	Object   string `json:"object"` // e.g. "func New" or "field Config.Name"
+	Before   string `json:"before,omitempty"`
+	After    string `json:"after,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Renamed:
+		return fmt.Sprintf("%s: renamed %s to %s", c.Package, c.Object, c.After)
+	case Changed:
+		return fmt.Sprintf("%s: changed %s from %s to %s", c.Package, c.Object, c.Before, c.After)
+	}
+	return fmt.Sprintf("%s: %s %s", c.Package, c.Kind, c.Object)
+}
+
+// Unknown is a package that wasn't compared because a file doesn't parse
+// before or after the change, so its API can't be known
+type Unknown struct {
+	Package string `json:"package"`
+	Error   string `json:"error"`
+}
+
+func (u Unknown) String() string {
+	return fmt.Sprintf("%s: not compared, %s", u.Package, u.Error)
+}
+
+// Report lists the API changes of every package, breaking ones first.
+// Packages that couldn't be compared are listed apart and have no changes.
+type Report struct {
+	Packages []string  `json:"packages"`
+	Changes  []Change  `json:"changes"`
+	Unknown  []Unknown `json:"unknown"`
+}
+
+// Breaking returns the changes that break callers or implementers
+func (r Report) Breaking() []Change {
+	var breaking []Change
+	for _, change := range r.Changes {
+		if change.Breaking {
+			breaking = append(breaking, change)
+		}
+	}
+	return breaking
+}
+
+// Options controls which changes and packages are compared
+type Options struct {
+	All      bool // compare HEAD with the working tree instead of the index
+	Internal bool // include main packages and packages under internal/
+}
+
+// Compare reports the API changes of the Go packages with staged changes,
+// or with all of those with changes to tracked files
+func Compare(opts Options) (Report, error) {
+	changes, err := git.Changes(opts.All)
+	if err != nil {
+		return Report{}, err
+	}
+	var dirs []string
+	for _, change := range changes {
+		for _, file := range []string{change.Path, change.OldPath} {
+			if file == "" || !isSource(path.Base(file)) {
+				continue
+			}
+			if dir := directory(file); !slices.Contains(dirs, dir) && (opts.Internal || !isInternal(dir)) {
+				dirs = append(dirs, dir)
+			}
+		}
+	}
+	slices.Sort(dirs)
+
+	after := git.RevisionIndex
+	if opts.All {
+		after = git.RevisionWorktree
+	}
+	report := Report{Packages: []string{}, Changes: []Change{}, Unknown: []Unknown{}}
+	for _, dir := range dirs {
+		oldFiles, err := git.ReadDirectory(git.RevisionHead, dir, isSource)
+		if err != nil {
+			return Report{}, err
+		}
+		newFiles, err := git.ReadDirectory(after, dir, isSource)
+		if err != nil {
+			return Report{}, err
+		}
+		before, oldName, oldErr := parsePackage(oldFiles)
+		current, newName, newErr := parsePackage(newFiles)
+		if !opts.Internal && (oldName == "main" || newName == "main") {
+			continue
+		}
+		name := dir
+		if name == "" {
+			name = "."
+		}
+		// Identifiers of a file that doesn't parse would look removed or added
+		if oldErr != nil || newErr != nil {
+			side, parseErr := "before", oldErr
+			if oldErr == nil {
+				side, parseErr = "after", newErr
+			}
+			report.Unknown = append(report.Unknown, Unknown{Package: name, Error: fmt.Sprintf("%s the change: %v", side, parseErr)})
+			continue
+		}
+		report.Packages = append(report.Packages, name)
+		report.Changes = append(report.Changes, compare(name, before, current)...)
+	}
+	slices.SortStableFunc(report.Changes, func(a, b Change) int {
+		if a.Breaking != b.Breaking {
+			if a.Breaking {
+				return -1
+			}
+			return 1
+		}
+		return 0
+	})
+	return report, nil
+}
+
+// compare lists the differences between two versions of a package's API.
+// Members of added or removed types are covered by the type itself.
+func compare(pkg string, before, after api) []Change {
+	var removed, added []object
+	var changes []Change
+	for _, obj := range sortedObjects(before) {
+		description, ok := after[obj]
+		switch {
+		case !ok:
+			if owner, ok := obj.owner(); ok && !changedIn(owner, before, after) {
+				continue
+			}
+			removed = append(removed, obj)
+		case description != before[obj]:
+			// Moving a method from the pointer to the value receiver keeps it in both method sets
+			breaking := !(obj.kind == kindMethod && "(*"+strings.TrimPrefix(description, "(") == before[obj])
+			changes = append(changes, Change{Package: pkg, Kind: Changed, Object: obj.String(), Before: before[obj], After: description, Breaking: breaking})
+		}
+	}
+	for _, obj := range sortedObjects(after) {
+		if _, ok := before[obj]; ok {
+			continue
+		}
+		if owner, ok := obj.owner(); ok && !changedIn(owner, before, after) {
+			continue
+		}
+		added = append(added, obj)
+	}
+
+	// A removed function or method with the twin of its signature added is
+	// most likely a rename
+	for _, old := range removed {
+		i := slices.IndexFunc(added, func(obj object) bool { return isRename(old, obj, before, after) })
+		if i < 0 {
+			changes = append(changes, Change{Package: pkg, Kind: Removed, Object: old.String(), Before: before[old], Breaking: true})
+			continue
+		}
+		newName := added[i].name
+		if _, method, ok := strings.Cut(newName, "."); ok {
+			newName = method
+		}
+		changes = append(changes, Change{Package: pkg, Kind: Renamed, Object: old.String(), Before: before[old], After: newName, Breaking: true})
+		added = slices.Delete(added, i, i+1)
+	}
+
+	for _, obj := range added {
+		// Implementations of an interface lack its new methods
+		breaking := obj.kind == kindInterface || obj.kind == kindEmbedded
+		changes = append(changes, Change{Package: pkg, Kind: Added, Object: obj.String(), After: after[obj], Breaking: breaking})
+	}
+	return changes
+}
+
+// changedIn reports whether a type exists on both sides with the same kind,
+// so that changes to its members are worth listing
+func changedIn(owner object, before, after api) bool {
+	old, inBefore := before[owner]
+	current, inAfter := after[owner]
+	return inBefore && inAfter && old == current
+}
+
+func isRename(old, current object, before, after api) bool {
+	if old.kind != current.kind || before[old] != after[current] {
+		return false
+	}
+	switch old.kind {
+	case kindFunc:
+		return true
+	case kindMethod:
+		oldType, _, _ := strings.Cut(old.name, ".")
+		newType, _, _ := strings.Cut(current.name, ".")
+		return oldType == newType
+	}
+	return false
+}
+
+func sortedObjects(objects api) []object {
+	sorted := make([]object, 0, len(objects))
+	for obj := range objects {
+		sorted = append(sorted, obj)
+	}
+	slices.SortFunc(sorted, func(a, b object) int {
+		return strings.Compare(a.String(), b.String())
+	})
+	return sorted
+}
+
+// isSource reports whether a file name is Go code of the package itself
+func isSource(name string) bool {
+	return strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go")
+}
+
+func directory(file string) string {
+	dir := path.Dir(file)
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// isInternal reports whether other modules can't import a directory
+func isInternal(dir string) bool {
+	for _, part := range strings.Split(dir, "/") {
+		if part == "internal" || part == "testdata" || part == "vendor" {
+			return true
+		}
+	}
+	return false
+}
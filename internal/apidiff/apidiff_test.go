@@ -0,0 +1,149 @@
+package apidiff
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/gitfixture"
+)
+
+// diff compares two versions of a single-file package
+func diff(t *testing.T, before, after string) []string {
+	t.Helper()
+	old, _, err := parsePackage(map[string]string{"a.go": before})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current, _, err := parsePackage(map[string]string{"a.go": after})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []string
+	for _, change := range compare("a", old, current) {
+		mark := " "
+		if change.Breaking {
+			mark = "!"
+		}
+		changes = append(changes, mark+" "+change.String())
+	}
+	return changes
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name   string
+		before string
+		after  string
+		want   []string
+	}{
+		{
+			name:   "unexported changes",
+			before: "package a\nfunc helper() {}\n",
+			after:  "package a\nfunc helper(x int) {}\nvar cache = map[string]int{}\n",
+		},
+		{
+			name:   "parameter renamed",
+			before: "package a\nfunc Open(name string) error { return nil }\n",
+			after:  "package a\nfunc Open(path string) error { return nil }\n",
+		},
+		{
+			name:   "signature changed",
+			before: "package a\nfunc Open(name string) error { return nil }\n",
+			after:  "package a\nfunc Open(name string, mode int) error { return nil }\n",
+			want:   []string{"! a: changed func Open from func(string) error to func(string, int) error"},
+		},
+		{
+			name:   "function renamed",
+			before: "package a\nfunc New() int { return 0 }\n",
+			after:  "package a\nfunc Create() int { return 0 }\n",
+			want:   []string{"! a: renamed func New to Create"},
+		},
+		{
+			name:   "removed and added",
+			before: "package a\nconst Limit = 1\n",
+			after:  "package a\nvar Max int\n",
+			want:   []string{"! a: removed const Limit", "  a: added var Max"},
+		},
+		{
+			name:   "struct field removed",
+			before: "package a\ntype T struct { Name string; Age int }\n",
+			after:  "package a\ntype T struct { Name string }\n",
+			want:   []string{"! a: removed field T.Age"},
+		},
+		{
+			name:   "interface method added",
+			before: "package a\ntype R interface { Read() }\n",
+			after:  "package a\ntype R interface { Read(); Close() error }\n",
+			want:   []string{"! a: added interface method R.Close"},
+		},
+		{
+			name:   "method moved to the value receiver",
+			before: "package a\ntype T struct{}\nfunc (t *T) Get() int { return 0 }\n",
+			after:  "package a\ntype T struct{}\nfunc (t T) Get() int { return 0 }\n",
+			want:   []string{"  a: changed method T.Get from (*T) func() int to (T) func() int"},
+		},
+		{
+			name:   "members of a removed type",
+			before: "package a\ntype T struct { Name string }\nfunc (T) Get() {}\n",
+			after:  "package a\n",
+			want:   []string{"! a: removed type T"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diff(t, tt.before, tt.after)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("changes:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestParsePackageError(t *testing.T) {
+	objects, name, err := parsePackage(map[string]string{
+		"a.go": "package a\nfunc Good() {}\n",
+		"b.go": "package a\nfunc Broken( {}\n",
+	})
+	if err == nil || !strings.HasPrefix(err.Error(), "b.go:") {
+		t.Errorf("err = %v, want the parse error of b.go", err)
+	}
+	if name != "a" {
+		t.Errorf("name = %q, want the name from the files that parse", name)
+	}
+	if _, ok := objects[object{kindFunc, "Good"}]; !ok {
+		t.Errorf("objects = %v", objects)
+	}
+}
+
+func TestCompareSkipsPackagesThatDontParse(t *testing.T) {
+	repo := gitfixture.New(t)
+	repo.Commit("feat: add store and api", map[string]string{
+		"store/store.go": "package store\n\nfunc Open() {}\n\nfunc Close() {}\n",
+		"api/api.go":     "package api\n\nfunc Hello() string { return \"hello\" }\n",
+	})
+	repo.Enter()
+
+	// A half-written file hides Close from the parser; the other package still counts
+	repo.Write("store/store.go", "package store\n\nfunc Open() {\n\nfunc Close() {}\n")
+	repo.Write("api/api.go", "package api\n\nfunc Greet() {}\n")
+	repo.Stage("store", "api")
+
+	report, err := Compare(Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Unknown) != 1 || report.Unknown[0].Package != "store" || !strings.Contains(report.Unknown[0].Error, "after the change: store/store.go:") {
+		t.Errorf("unknown = %+v, want store with its parse error", report.Unknown)
+	}
+	if strings.Join(report.Packages, ",") != "api" {
+		t.Errorf("packages = %v, want only api", report.Packages)
+	}
+	for _, change := range report.Changes {
+		if change.Package == "store" {
+			t.Errorf("store was compared: %s", change)
+		}
+	}
+	if breaking := report.Breaking(); len(breaking) != 1 || breaking[0].Object != "func Hello" {
+		t.Errorf("breaking = %v, want only the removed Hello", breaking)
+	}
+}
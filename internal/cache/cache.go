@@ -0,0 +1,280 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gitr/internal/config"
+)
+
+// Default limits used when the configuration leaves a value unset
+const (
+	defaultTTL        = 24 * time.Hour
+	defaultMaxEntries = 500
+	defaultMaxBytes   = 10 << 20
+)
+
+// Cache is an on-disk cache of model responses
+type Cache struct {
+	dir        string
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int64
+}
+
+// Entry represents a cached model response
+type Entry struct {
+	Key       string    `json:"key"`
+	Content   string    `json:"content"`
+	Profile   string    `json:"profile"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Stats describes the cache contents
+type Stats struct {
+	Dir     string
+	Entries int
+	Expired int
+	Bytes   int64
+	Oldest  time.Time
+	Newest  time.Time
+}
+
+// Dir returns the cache directory ($XDG_CACHE_HOME/gitr/responses or the platform equivalent)
+func Dir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "gitr", "responses"), nil
+}
+
+// New creates a cache using the limits from the configuration
+func New(cfg config.CacheConfig) (*Cache, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Cache{
+		dir:        dir,
+		ttl:        time.Duration(cfg.TTLMinutes) * time.Minute,
+		maxEntries: cfg.MaxEntries,
+		maxBytes:   int64(cfg.MaxSizeKB) << 10,
+	}
+
+	// Fill in defaults for unset values
+	if c.ttl <= 0 {
+		c.ttl = defaultTTL
+	}
+	if c.maxEntries <= 0 {
+		c.maxEntries = defaultMaxEntries
+	}
+	if c.maxBytes <= 0 {
+		c.maxBytes = defaultMaxBytes
+	}
+
+	return c, nil
+}
+
+// Key hashes the given parts into a cache key
+func Key(parts ...string) string {
+	hash := sha256.New()
+	for _, part := range parts {
+		hash.Write([]byte(part))
+		// Separate parts so ("ab", "c") and ("a", "bc") differ
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// NormalizeDiff removes differences that don't change the meaning of a diff:
+// line endings, trailing whitespace and surrounding blank lines
+func NormalizeDiff(diff string) string {
+	diff = strings.ReplaceAll(diff, "\r\n", "\n")
+	lines := strings.Split(diff, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Get returns the entry stored under key if it exists and hasn't expired
+func (c *Cache) Get(key string) (*Entry, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+
+	var entry Entry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, false
+	}
+
+	if c.expired(entry.CreatedAt) {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+
+	return &entry, true
+}
+
+// Put stores an entry and prunes the cache to its limits
+func (c *Cache) Put(entry Entry) error {
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return err
+	}
+
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// Write atomically so concurrent runs never read a partial entry
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(entry.Key)); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return c.prune()
+}
+
+// Stats returns statistics about the cache contents
+func (c *Cache) Stats() (Stats, error) {
+	stats := Stats{Dir: c.dir}
+
+	files, err := c.files()
+	if err != nil {
+		return stats, err
+	}
+
+	for _, f := range files {
+		stats.Entries++
+		stats.Bytes += f.size
+		if c.expired(f.modTime) {
+			stats.Expired++
+		}
+		if stats.Oldest.IsZero() || f.modTime.Before(stats.Oldest) {
+			stats.Oldest = f.modTime
+		}
+		if f.modTime.After(stats.Newest) {
+			stats.Newest = f.modTime
+		}
+	}
+
+	return stats, nil
+}
+
+// Clear removes all entries and returns how many were removed
+func (c *Cache) Clear() (int, error) {
+	files, err := c.files()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, f := range files {
+		if err := os.Remove(f.path); err == nil {
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+type cacheFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// files lists the entry files, oldest first
+func (c *Cache) files() ([]cacheFile, error) {
+	dirEntries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var files []cacheFile
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), ".json") {
+			continue
+		}
+		info, err := de.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, cacheFile{
+			path:    filepath.Join(c.dir, de.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].modTime.Before(files[j].modTime)
+	})
+
+	return files, nil
+}
+
+// prune removes expired entries, then the oldest ones until the cache fits its limits
+func (c *Cache) prune() error {
+	files, err := c.files()
+	if err != nil {
+		return err
+	}
+
+	var kept []cacheFile
+	var total int64
+	for _, f := range files {
+		if c.expired(f.modTime) {
+			os.Remove(f.path)
+			continue
+		}
+		kept = append(kept, f)
+		total += f.size
+	}
+
+	for len(kept) > 0 && (len(kept) > c.maxEntries || total > c.maxBytes) {
+		os.Remove(kept[0].path)
+		total -= kept[0].size
+		kept = kept[1:]
+	}
+
+	return nil
+}
+
+func (c *Cache) expired(createdAt time.Time) bool {
+	return time.Since(createdAt) > c.ttl
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
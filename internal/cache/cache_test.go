@@ -0,0 +1,98 @@
+package cache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"gitr/internal/config"
+)
+
+func TestNormalizeDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+		want string
+	}{
+		{"unchanged", "diff --git a/x b/x\n+line", "diff --git a/x b/x\n+line"},
+		{"CRLF line endings", "diff --git a/x b/x\r\n+line\r\n", "diff --git a/x b/x\n+line"},
+		{"trailing whitespace", "+line  \t\n-old ", "+line\n-old"},
+		{"surrounding blank lines", "\n\n+line\n\n", "+line"},
+		{"leading whitespace is kept", "+  indented", "+  indented"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeDiff(tt.diff); got != tt.want {
+			t.Errorf("%s: NormalizeDiff(%q) = %q, want %q", tt.name, tt.diff, got, tt.want)
+		}
+	}
+}
+
+func TestKey(t *testing.T) {
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Error("Key doesn't separate its parts")
+	}
+	if Key("a", "b") != Key("a", "b") {
+		t.Error("Key isn't deterministic")
+	}
+}
+
+// newCache returns a cache in a temporary directory
+func newCache(t *testing.T, cfg config.CacheConfig) *Cache {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	c, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestPutGet(t *testing.T) {
+	c := newCache(t, config.CacheConfig{})
+	if _, ok := c.Get("missing"); ok {
+		t.Fatal("Get found a missing entry")
+	}
+
+	if err := c.Put(Entry{Key: "k", Content: "feat: add x", Profile: "default", Model: "m"}); err != nil {
+		t.Fatal(err)
+	}
+	entry, ok := c.Get("k")
+	if !ok || entry.Content != "feat: add x" || entry.Profile != "default" || entry.Model != "m" {
+		t.Fatalf("Get = %+v, %v", entry, ok)
+	}
+
+	if err := c.Put(Entry{Key: "old", Content: "x", CreatedAt: time.Now().Add(-2 * defaultTTL)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := c.Get("old"); ok {
+		t.Error("Get returned an expired entry")
+	}
+}
+
+func TestPrune(t *testing.T) {
+	c := newCache(t, config.CacheConfig{MaxEntries: 2})
+	for i, key := range []string{"a", "b", "c"} {
+		if err := c.Put(Entry{Key: key, Content: key}); err != nil {
+			t.Fatal(err)
+		}
+		// Entries are pruned oldest first by modification time
+		stamp := time.Now().Add(time.Duration(i-3) * time.Minute)
+		os.Chtimes(c.path(key), stamp, stamp)
+	}
+
+	stats, err := c.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Entries != 2 {
+		t.Errorf("entries = %d, want 2", stats.Entries)
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("the oldest entry wasn't pruned")
+	}
+
+	removed, err := c.Clear()
+	if err != nil || removed != 2 {
+		t.Errorf("Clear = %d, %v; want 2", removed, err)
+	}
+}
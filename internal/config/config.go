@@ -5,21 +5,60 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"gitr/internal/i18n"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
 )
 
+// DefaultProfileName is the name of the profile stored in the top-level <openai> element
+const DefaultProfileName = "default"
+
+// Supported providers
+const (
+	ProviderOpenAI = "openai"
+	ProviderAzure  = "azure"
+	ProviderLocal  = "local" // OpenAI-compatible local server (Ollama, LM Studio, llama.cpp, ...)
+)
+
 // Config represents the configuration structure
 type Config struct {
 	XMLName        xml.Name             `xml:"config"`
+	DefaultProfile string               `xml:"default_profile,omitempty"`
 	OpenAI         OpenAIConfig         `xml:"openai"`
+	Profiles       []Profile            `xml:"profiles>profile"`
+	Fallback       []string             `xml:"fallback>profile"`
+	Retry          RetryConfig          `xml:"retry"`
+	Cache          CacheConfig          `xml:"cache"`
+	History        HistoryConfig        `xml:"history"`
 	CommitTemplate CommitTemplateConfig `xml:"commit_template"`
+	Commit         CommitConfig         `xml:"commit"`
+	Diff           DiffConfig           `xml:"diff"`
+	Agent          AgentConfig          `xml:"agent"`
+	Tokens         TokensConfig         `xml:"tokens"`
+	Pricing        PricingConfig        `xml:"pricing"`
+	Offline        OfflineConfig        `xml:"offline"`
+	Review         ReviewConfig         `xml:"review"`
+
+	// activeProfile is the profile selected for the current run (not persisted)
+	activeProfile string
+}
+
+// Profile represents a named provider profile
+type Profile struct {
+	Name   string       `xml:"name,attr"`
+	OpenAI OpenAIConfig `xml:"openai"`
 }
 
 // OpenAIConfig represents OpenAI configuration
 type OpenAIConfig struct {
 	XMLName xml.Name `xml:"openai"`
 
+	// Provider selects the API flavour: openai (default), azure or local
+	Provider string `xml:"provider,omitempty"`
+
 	// Azure OpenAI Service configuration (optional)
 	ByAzure    bool   `xml:"by_azure"`
 	BaseURL    string `xml:"base_url"`
@@ -32,6 +71,7 @@ type OpenAIConfig struct {
 	// Model parameters
 	Model            string   `xml:"model"`
 	MaxTokens        *int     `xml:"max_tokens"`
+	ContextWindow    int      `xml:"context_window,omitempty"` // tokens the model accepts; 0 looks the model up
 	Temperature      *float32 `xml:"temperature"`
 	TopP             *float32 `xml:"top_p"`
 	Stop             []string `xml:"stop"`
@@ -45,6 +85,39 @@ type OpenAIConfig struct {
 	User           *string                              `xml:"user"`
 }
 
+// RetryConfig represents retry behaviour for failed provider requests
+type RetryConfig struct {
+	XMLName          xml.Name `xml:"retry"`
+	MaxAttempts      int      `xml:"max_attempts"`       // attempts per profile, including the first
+	InitialBackoffMs int      `xml:"initial_backoff_ms"` // first backoff delay, doubled on each retry
+	MaxBackoffMs     int      `xml:"max_backoff_ms"`     // upper bound for a single backoff delay
+}
+
+// CacheConfig represents the on-disk response cache configuration
+type CacheConfig struct {
+	XMLName    xml.Name `xml:"cache"`
+	Enabled    *bool    `xml:"enabled"` // defaults to true when unset
+	TTLMinutes int      `xml:"ttl_minutes"`
+	MaxEntries int      `xml:"max_entries"`
+	MaxSizeKB  int      `xml:"max_size_kb"`
+}
+
+// IsEnabled reports whether responses should be cached
+func (c CacheConfig) IsEnabled() bool {
+	return c.Enabled == nil || *c.Enabled
+}
+
+// HistoryConfig represents the local generation history configuration
+type HistoryConfig struct {
+	XMLName xml.Name `xml:"history"`
+	Enabled *bool    `xml:"enabled"` // defaults to true when unset
+}
+
+// IsEnabled reports whether generations should be recorded
+func (h HistoryConfig) IsEnabled() bool {
+	return h.Enabled == nil || *h.Enabled
+}
+
 // CommitTemplateConfig represents commit template configuration
 type CommitTemplateConfig struct {
 	XMLName                   xml.Name `xml:"commit_template"`
@@ -52,6 +125,144 @@ type CommitTemplateConfig struct {
 	MaxLength                 int      `xml:"max_length"`
 	IncludeScope              bool     `xml:"include_scope"`
 	CommitWithoutConfirmation bool     `xml:"commit_without_confirmation"`
+	Language                  string   `xml:"language,omitempty"` // language code of the message; empty is English
+}
+
+// CommitConfig represents how git commit is run
+type CommitConfig struct {
+	XMLName    xml.Name `xml:"commit"`
+	Cleanup    string   `xml:"cleanup,omitempty"`     // git commit --cleanup mode; empty uses git's default
+	Signoff    bool     `xml:"signoff"`               // add a Signed-off-by trailer
+	GPGSign    *bool    `xml:"gpg_sign"`              // sign commits; unset follows git's commit.gpgsign
+	SigningKey string   `xml:"signing_key,omitempty"` // GPG key ID or SSH key to sign with
+	NoVerify   bool     `xml:"no_verify"`             // skip the pre-commit and commit-msg hooks
+	Roster     string   `xml:"roster,omitempty"`      // team roster for --with; empty searches .gitr_team and ~/.config/gitr/team
+}
+
+// DiffConfig controls the diff sent to the model
+type DiffConfig struct {
+	XMLName      xml.Name `xml:"diff"`
+	Enrich       *bool    `xml:"enrich"`        // add file headers and enclosing symbols; defaults to true when unset
+	ContextLines int      `xml:"context_lines"` // lines of context around changes; 0 uses git's default of 3
+	MaxTokens    int      `xml:"max_tokens"`    // budget for the diff in the prompt; 0 means no limit
+	APICheck     *bool    `xml:"api_check"`     // look for breaking changes to exported Go APIs; defaults to true when unset
+	APIInternal  bool     `xml:"api_internal"`  // also check main packages and packages under internal/
+}
+
+// IsEnrichEnabled reports whether the diff should be enriched
+func (d DiffConfig) IsEnrichEnabled() bool {
+	return d.Enrich == nil || *d.Enrich
+}
+
+// IsAPICheckEnabled reports whether Go API changes should be looked for
+func (d DiffConfig) IsAPICheckEnabled() bool {
+	return d.APICheck == nil || *d.APICheck
+}
+
+// AgentConfig controls agent mode, where the model may inspect the repository
+// with read-only tools before answering
+type AgentConfig struct {
+	XMLName        xml.Name `xml:"agent"`
+	Enabled        bool     `xml:"enabled"`
+	MaxToolCalls   int      `xml:"max_tool_calls"`  // 0 uses the default
+	TimeoutSeconds int      `xml:"timeout_seconds"` // time allowed for the tool phase; 0 uses the default
+}
+
+// Agent mode defaults
+const (
+	DefaultAgentMaxToolCalls   = 8
+	DefaultAgentTimeoutSeconds = 60
+)
+
+// ToolCallLimit returns the maximum number of tool calls per generation
+func (a AgentConfig) ToolCallLimit() int {
+	if a.MaxToolCalls <= 0 {
+		return DefaultAgentMaxToolCalls
+	}
+	return a.MaxToolCalls
+}
+
+// Timeout returns the time allowed for tool calls
+func (a AgentConfig) Timeout() time.Duration {
+	if a.TimeoutSeconds <= 0 {
+		return DefaultAgentTimeoutSeconds * time.Second
+	}
+	return time.Duration(a.TimeoutSeconds) * time.Second
+}
+
+// What to do when a prompt doesn't fit the model's context window
+const (
+	OverflowSummarize = "summarize" // shorten the diff until it fits (default)
+	OverflowWarn      = "warn"      // send it anyway and let the provider decide
+	OverflowFail      = "fail"      // stop before calling the provider
+)
+
+// OverflowModes lists the accepted values of tokens.on_overflow
+var OverflowModes = []string{OverflowSummarize, OverflowWarn, OverflowFail}
+
+// TokensConfig controls the pre-flight check of the prompt size
+type TokensConfig struct {
+	XMLName    xml.Name `xml:"tokens"`
+	OnOverflow string   `xml:"on_overflow,omitempty"` // summarize (default), warn or fail
+}
+
+// Overflow returns what to do with a prompt that is too large
+func (t TokensConfig) Overflow() string {
+	if t.OnOverflow == "" {
+		return OverflowSummarize
+	}
+	return t.OnOverflow
+}
+
+// PricingConfig is the price table used for cost reports. Entries override
+// the built-in prices of models with the same name prefix.
+type PricingConfig struct {
+	XMLName xml.Name     `xml:"pricing"`
+	Models  []ModelPrice `xml:"model"`
+}
+
+// ModelPrice is the price of a model in USD per million tokens
+type ModelPrice struct {
+	Name       string  `xml:"name,attr"` // model name or prefix, e.g. gpt-4o-mini
+	Prompt     float64 `xml:"prompt,attr"`
+	Completion float64 `xml:"completion,attr"`
+}
+
+// OfflineConfig controls the rule-based message generator used without a model
+type OfflineConfig struct {
+	XMLName  xml.Name `xml:"offline"`
+	Fallback *bool    `xml:"fallback"` // use it when every profile fails; defaults to true when unset
+}
+
+// IsFallbackEnabled reports whether a heuristic message replaces a failed generation
+func (o OfflineConfig) IsFallbackEnabled() bool {
+	return o.Fallback == nil || *o.Fallback
+}
+
+// Review severities, most serious first
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+	SeverityNone   = "none" // never block
+)
+
+// BlockSeverities lists the accepted values of review.block_on
+var BlockSeverities = []string{SeverityHigh, SeverityMedium, SeverityLow, SeverityNone}
+
+// ReviewConfig controls gitr review and the review before a commit
+type ReviewConfig struct {
+	XMLName      xml.Name `xml:"review"`
+	BeforeCommit bool     `xml:"before_commit"`      // review the changes before gitr -c commits them
+	BlockOn      string   `xml:"block_on,omitempty"` // findings of this severity or worse block the commit; high (default), medium, low or none
+}
+
+// BlockSeverity returns the least serious severity that blocks a commit
+func (r ReviewConfig) BlockSeverity() string {
+	if r.BlockOn == "" {
+		return SeverityHigh
+	}
+	return r.BlockOn
 }
 
 // Load loads configuration from XML file
@@ -120,17 +331,54 @@ func FindConfigFile() (string, error) {
 	return localConfig, nil
 }
 
+// GlobalConfigFile returns the user-wide config file: ~/.gitr_config if it exists, else ~/.config/gitr/config
+func GlobalConfigFile() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	homeConfig := filepath.Join(homeDir, ".gitr_config")
+	if _, err := os.Stat(homeConfig); err == nil {
+		return homeConfig, nil
+	}
+	return filepath.Join(homeDir, ".config", "gitr", "config"), nil
+}
+
+// LocalConfigFile returns the config file in the current directory
+func LocalConfigFile() (string, error) {
+	pwd, err := os.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(pwd, ".gitr_config"), nil
+}
+
+// DataDir returns the directory for GitR's local data ($XDG_DATA_HOME/gitr or ~/.local/share/gitr)
+func DataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "gitr"), nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".local", "share", "gitr"), nil
+}
+
 // CreateDefaultConfig creates a default configuration
 func CreateDefaultConfig() *Config {
 	return &Config{
 		OpenAI: OpenAIConfig{
+			Provider:         ProviderOpenAI,
 			ByAzure:          false,
 			BaseURL:          "https://api.openai.com/v1",
 			APIVersion:       "2023-05-15",
 			APIKey:           "",
 			Timeout:          30,
 			Model:            "gpt-3.5-turbo",
-			MaxTokens:        intPtr(150),
+			MaxTokens:        intPtr(500),
 			Temperature:      float32Ptr(0.7),
 			TopP:             float32Ptr(1.0),
 			Stop:             []string{},
@@ -141,32 +389,284 @@ func CreateDefaultConfig() *Config {
 			LogitBias:        map[string]int{},
 			User:             nil,
 		},
+		Retry: RetryConfig{
+			MaxAttempts:      3,
+			InitialBackoffMs: 500,
+			MaxBackoffMs:     8000,
+		},
+		Cache: CacheConfig{
+			Enabled:    boolPtr(true),
+			TTLMinutes: 1440,
+			MaxEntries: 500,
+			MaxSizeKB:  10240,
+		},
+		History: HistoryConfig{
+			Enabled: boolPtr(true),
+		},
 		CommitTemplate: CommitTemplateConfig{
 			Style:                     "conventional",
 			MaxLength:                 72,
 			IncludeScope:              true,
 			CommitWithoutConfirmation: false,
 		},
+		Diff: DiffConfig{
+			Enrich:    boolPtr(true),
+			MaxTokens: 8000,
+		},
+		Agent: AgentConfig{
+			MaxToolCalls:   DefaultAgentMaxToolCalls,
+			TimeoutSeconds: DefaultAgentTimeoutSeconds,
+		},
 	}
 }
 
 // Validate checks if the configuration has all required fields
 func (c *Config) Validate() error {
-	if c.OpenAI.BaseURL == "" {
+	if _, err := c.Profile(c.ActiveProfileName()); err != nil {
+		return err
+	}
+	if err := c.Active().Validate(); err != nil {
+		return fmt.Errorf("profile %q: %v", c.ActiveProfileName(), err)
+	}
+	for _, name := range c.Fallback {
+		settings, err := c.Profile(name)
+		if err != nil {
+			return fmt.Errorf("fallback: %v", err)
+		}
+		if err := settings.Validate(); err != nil {
+			return fmt.Errorf("fallback profile %q: %v", name, err)
+		}
+	}
+	if c.CommitTemplate.Language != "" && i18n.Normalize(c.CommitTemplate.Language) == "" {
+		return fmt.Errorf("commit_template.language: unknown language %q (expected %s)", c.CommitTemplate.Language, strings.Join(i18n.Codes(), ", "))
+	}
+	if c.Review.BlockOn != "" && !containsString(BlockSeverities, c.Review.BlockOn) {
+		return fmt.Errorf("review.block_on: unknown severity %q (expected %s)", c.Review.BlockOn, strings.Join(BlockSeverities, ", "))
+	}
+	return nil
+}
+
+// ProfileChain returns the active profile followed by the fallback profiles, without duplicates
+func (c *Config) ProfileChain() []string {
+	chain := []string{c.ActiveProfileName()}
+	seen := map[string]bool{chain[0]: true}
+	for _, name := range c.Fallback {
+		if name == "" {
+			name = DefaultProfileName
+		}
+		if !seen[name] {
+			seen[name] = true
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
+// Validate checks if the provider settings have all required fields
+func (o *OpenAIConfig) Validate() error {
+	switch o.Provider {
+	case "", ProviderOpenAI, ProviderAzure, ProviderLocal:
+	default:
+		return fmt.Errorf("unknown provider %q (expected %s, %s or %s)", o.Provider, ProviderOpenAI, ProviderAzure, ProviderLocal)
+	}
+	if o.BaseURL == "" {
 		return fmt.Errorf("base_url is required")
 	}
-	if c.OpenAI.APIKey == "" {
+	if o.APIKey == "" && !o.IsLocal() {
 		return fmt.Errorf("api_key is required")
 	}
-	if c.OpenAI.Model == "" {
+	if o.Model == "" {
 		return fmt.Errorf("model is required")
 	}
 	return nil
 }
 
+// IsAzure reports whether the settings target Azure OpenAI Service
+func (o *OpenAIConfig) IsAzure() bool {
+	return o.ByAzure || o.Provider == ProviderAzure
+}
+
+// IsLocal reports whether the settings target a local server that needs no API key
+func (o *OpenAIConfig) IsLocal() bool {
+	return o.Provider == ProviderLocal
+}
+
 // IsFirstTimeSetup checks if this is a first-time setup (missing required fields)
 func (c *Config) IsFirstTimeSetup() bool {
-	return c.OpenAI.BaseURL == "" || c.OpenAI.APIKey == "" || c.OpenAI.Model == ""
+	active := c.Active()
+	return active.BaseURL == "" || (active.APIKey == "" && !active.IsLocal()) || active.Model == ""
+}
+
+// ProfileNames returns the names of all profiles, starting with the default one
+func (c *Config) ProfileNames() []string {
+	names := []string{DefaultProfileName}
+	for _, p := range c.Profiles {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Profile returns the settings of the named profile
+func (c *Config) Profile(name string) (*OpenAIConfig, error) {
+	if name == "" || name == DefaultProfileName {
+		return &c.OpenAI, nil
+	}
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return &c.Profiles[i].OpenAI, nil
+		}
+	}
+	return nil, fmt.Errorf("profile %q not found", name)
+}
+
+// HasProfile checks if a profile with the given name exists
+func (c *Config) HasProfile(name string) bool {
+	_, err := c.Profile(name)
+	return err == nil
+}
+
+// SelectProfile selects the profile used for the current run.
+// An empty name selects the configured default profile.
+func (c *Config) SelectProfile(name string) error {
+	if name != "" && !c.HasProfile(name) {
+		return fmt.Errorf("profile %q not found (available: %s)", name, strings.Join(c.ProfileNames(), ", "))
+	}
+	c.activeProfile = name
+	return nil
+}
+
+// ActiveProfileName returns the name of the profile used for the current run
+func (c *Config) ActiveProfileName() string {
+	if c.activeProfile != "" {
+		return c.activeProfile
+	}
+	if c.DefaultProfile != "" {
+		return c.DefaultProfile
+	}
+	return DefaultProfileName
+}
+
+// Active returns the settings of the profile used for the current run
+func (c *Config) Active() *OpenAIConfig {
+	settings, err := c.Profile(c.ActiveProfileName())
+	if err != nil {
+		return &c.OpenAI
+	}
+	return settings
+}
+
+// AddProfile adds a new named profile
+func (c *Config) AddProfile(name string, settings OpenAIConfig) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if c.HasProfile(name) {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+	c.Profiles = append(c.Profiles, Profile{Name: name, OpenAI: settings.clone()})
+	return nil
+}
+
+// CloneProfile copies the settings of an existing profile into a new one
+func (c *Config) CloneProfile(source, name string) error {
+	settings, err := c.Profile(source)
+	if err != nil {
+		return err
+	}
+	return c.AddProfile(name, *settings)
+}
+
+// DeleteProfile removes a named profile. The default profile cannot be deleted.
+func (c *Config) DeleteProfile(name string) error {
+	if name == "" || name == DefaultProfileName {
+		return fmt.Errorf("the %q profile cannot be deleted", DefaultProfileName)
+	}
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			c.Profiles = append(c.Profiles[:i], c.Profiles[i+1:]...)
+			if c.DefaultProfile == name {
+				c.DefaultProfile = ""
+			}
+			c.Fallback = removeString(c.Fallback, name)
+			if c.activeProfile == name {
+				c.activeProfile = ""
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("profile %q not found", name)
+}
+
+// SetDefaultProfile makes the named profile the default for all commands
+func (c *Config) SetDefaultProfile(name string) error {
+	if !c.HasProfile(name) {
+		return fmt.Errorf("profile %q not found", name)
+	}
+	if name == DefaultProfileName {
+		name = ""
+	}
+	c.DefaultProfile = name
+	return nil
+}
+
+func validateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name is required")
+	}
+	if name == DefaultProfileName {
+		return fmt.Errorf("%q is reserved for the top-level profile", DefaultProfileName)
+	}
+	if strings.ContainsAny(name, " \t,./") {
+		return fmt.Errorf("profile name %q must not contain spaces, commas, dots or slashes", name)
+	}
+	return nil
+}
+
+func removeString(values []string, value string) []string {
+	result := values[:0]
+	for _, v := range values {
+		if v != value {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// clone returns a deep copy of the settings so profiles never share pointers
+func (o OpenAIConfig) clone() OpenAIConfig {
+	cp := o
+	if o.MaxTokens != nil {
+		cp.MaxTokens = intPtr(*o.MaxTokens)
+	}
+	if o.Temperature != nil {
+		cp.Temperature = float32Ptr(*o.Temperature)
+	}
+	if o.TopP != nil {
+		cp.TopP = float32Ptr(*o.TopP)
+	}
+	if o.PresencePenalty != nil {
+		cp.PresencePenalty = float32Ptr(*o.PresencePenalty)
+	}
+	if o.FrequencyPenalty != nil {
+		cp.FrequencyPenalty = float32Ptr(*o.FrequencyPenalty)
+	}
+	if o.Seed != nil {
+		cp.Seed = intPtr(*o.Seed)
+	}
+	if o.User != nil {
+		user := *o.User
+		cp.User = &user
+	}
+	if o.ResponseFormat != nil {
+		format := *o.ResponseFormat
+		cp.ResponseFormat = &format
+	}
+	cp.Stop = append([]string(nil), o.Stop...)
+	cp.LogitBias = make(map[string]int, len(o.LogitBias))
+	for k, v := range o.LogitBias {
+		cp.LogitBias[k] = v
+	}
+	return cp
 }
 
 // Helper functions for pointer creation
@@ -177,3 +677,7 @@ func intPtr(i int) *int {
 func float32Ptr(f float32) *float32 {
 	return &f
 }
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,584 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+
+	"gitr/internal/git"
+	"gitr/internal/i18n"
+)
+
+// fieldKind describes how a field is displayed and edited
+type fieldKind int
+
+const (
+	kindText fieldKind = iota
+	kindSecret
+	kindInt
+	kindFloat
+	kindBool
+	kindChoice
+	kindList
+)
+
+// field is an editable configuration setting
+type field struct {
+	key         string // XML element name, as used by 'gitr config get/set'
+	name        string
+	description string
+	category    string
+	kind        fieldKind
+	choices     []string // allowed values for kindChoice
+	optional    bool     // an empty value unsets the field
+
+	get func() string
+	set func(string) error
+}
+
+// display returns the value as shown in the editor
+func (f field) display() string {
+	value := f.get()
+	switch {
+	case f.kind == kindSecret && value != "":
+		return MaskAPIKey(value)
+	case value == "" && f.optional:
+		return "(unset)"
+	}
+	return value
+}
+
+// next returns the value after the current one for toggles and choices
+func (f field) next(step int) string {
+	switch f.kind {
+	case kindBool:
+		value, _ := strconv.ParseBool(f.get())
+		return strconv.FormatBool(!value)
+	case kindChoice:
+		current := f.get()
+		for i, choice := range f.choices {
+			if choice == current {
+				return f.choices[(i+step+len(f.choices))%len(f.choices)]
+			}
+		}
+		return f.choices[0]
+	}
+	return f.get()
+}
+
+// configFields returns the editable fields for the active profile and the commit template
+func configFields(config *Config) []field {
+	// Fields always edit the profile that is active when they are built
+	fields := append(profileFields(config.Active()), templateFields(&config.CommitTemplate)...)
+	fields = append(fields, commitFields(&config.Commit)...)
+	fields = append(fields, diffFields(&config.Diff)...)
+	fields = append(fields, agentFields(&config.Agent)...)
+	fields = append(fields, tokensFields(&config.Tokens)...)
+	fields = append(fields, offlineFields(&config.Offline)...)
+	return append(fields, reviewFields(&config.Review)...)
+}
+
+// profileFields returns the editable fields of a provider profile
+func profileFields(profile *OpenAIConfig) []field {
+	return []field{
+		// Provider
+		{
+			key: "provider", name: "Provider", category: "Provider", kind: kindChoice,
+			description: "API flavour: openai, azure or local (OpenAI-compatible server, no key needed)",
+			choices:     []string{ProviderOpenAI, ProviderAzure, ProviderLocal},
+			get:         func() string { return providerLabel(profile.Provider) },
+			set:         func(v string) error { profile.Provider = v; return nil },
+		},
+		{
+			key: "api_key", name: "API Key", category: "Provider", kind: kindSecret,
+			description: "Your API key (falls back to $OPENAI_API_KEY when empty)",
+			get:         func() string { return profile.APIKey },
+			set:         func(v string) error { profile.APIKey = v; return nil },
+		},
+		{
+			key: "base_url", name: "Base URL", category: "Provider", kind: kindText,
+			description: "API base URL, e.g. https://api.openai.com/v1",
+			get:         func() string { return profile.BaseURL },
+			set: func(v string) error {
+				if err := validateURL(v); err != nil {
+					return err
+				}
+				profile.BaseURL = v
+				return nil
+			},
+		},
+		{
+			key: "timeout", name: "Timeout", category: "Provider", kind: kindInt,
+			description: "Request timeout in seconds (per attempt)",
+			get:         func() string { return strconv.Itoa(profile.Timeout) },
+			set:         setInt(&profile.Timeout, 1, 3600),
+		},
+
+		// Azure
+		{
+			key: "by_azure", name: "By Azure", category: "Azure", kind: kindBool,
+			description: "Use Azure OpenAI Service (same as provider azure)",
+			get:         func() string { return strconv.FormatBool(profile.ByAzure) },
+			set:         setBool(&profile.ByAzure),
+		},
+		{
+			key: "api_version", name: "API Version", category: "Azure", kind: kindText,
+			description: "Azure OpenAI API version, e.g. 2023-05-15",
+			get:         func() string { return profile.APIVersion },
+			set:         func(v string) error { profile.APIVersion = v; return nil },
+		},
+
+		// Model parameters
+		{
+			key: "model", name: "Model", category: "Model", kind: kindText,
+			description: "Model to use for generating commit messages",
+			get:         func() string { return profile.Model },
+			set: func(v string) error {
+				if v == "" {
+					return fmt.Errorf("model is required")
+				}
+				profile.Model = v
+				return nil
+			},
+		},
+		{
+			key: "max_tokens", name: "Max Tokens", category: "Model", kind: kindInt, optional: true,
+			description: "Maximum tokens for the response",
+			get:         func() string { return formatIntPtr(profile.MaxTokens) },
+			set:         setIntPtr(&profile.MaxTokens, 1, 1<<20),
+		},
+		{
+			key: "context_window", name: "Context Window", category: "Model", kind: kindInt,
+			description: "Tokens the model accepts for prompt and answer (0 looks the model up)",
+			get:         func() string { return strconv.Itoa(profile.ContextWindow) },
+			set:         setInt(&profile.ContextWindow, 0, 1<<24),
+		},
+		{
+			key: "temperature", name: "Temperature", category: "Model", kind: kindFloat, optional: true,
+			description: "Sampling temperature (0.0-2.0)",
+			get:         func() string { return formatFloatPtr(profile.Temperature) },
+			set:         setFloatPtr(&profile.Temperature, 0, 2),
+		},
+		{
+			key: "top_p", name: "Top P", category: "Model", kind: kindFloat, optional: true,
+			description: "Nucleus sampling probability mass (0.0-1.0)",
+			get:         func() string { return formatFloatPtr(profile.TopP) },
+			set:         setFloatPtr(&profile.TopP, 0, 1),
+		},
+		{
+			key: "presence_penalty", name: "Presence Penalty", category: "Model", kind: kindFloat, optional: true,
+			description: "Penalty for tokens already present (-2.0-2.0)",
+			get:         func() string { return formatFloatPtr(profile.PresencePenalty) },
+			set:         setFloatPtr(&profile.PresencePenalty, -2, 2),
+		},
+		{
+			key: "frequency_penalty", name: "Frequency Penalty", category: "Model", kind: kindFloat, optional: true,
+			description: "Penalty for frequent tokens (-2.0-2.0)",
+			get:         func() string { return formatFloatPtr(profile.FrequencyPenalty) },
+			set:         setFloatPtr(&profile.FrequencyPenalty, -2, 2),
+		},
+		{
+			key: "stop", name: "Stop Sequences", category: "Model", kind: kindList, optional: true,
+			description: "Comma-separated sequences where generation stops (up to 4)",
+			get:         func() string { return strings.Join(profile.Stop, ", ") },
+			set: func(v string) error {
+				stop := splitList(v)
+				if len(stop) > 4 {
+					return fmt.Errorf("at most 4 stop sequences are allowed")
+				}
+				profile.Stop = stop
+				return nil
+			},
+		},
+
+		// Advanced parameters
+		{
+			key: "seed", name: "Seed", category: "Advanced", kind: kindInt, optional: true,
+			description: "Seed for deterministic sampling, if the provider supports it",
+			get:         func() string { return formatIntPtr(profile.Seed) },
+			set:         setIntPtr(&profile.Seed, -1<<31, 1<<31-1),
+		},
+		{
+			key: "response_format", name: "Response Format", category: "Advanced", kind: kindChoice,
+			description: "Response format: default, text or json_object",
+			choices:     []string{"default", string(openai.ChatCompletionResponseFormatTypeText), string(openai.ChatCompletionResponseFormatTypeJSONObject)},
+			get: func() string {
+				if profile.ResponseFormat == nil || profile.ResponseFormat.Type == "" {
+					return "default"
+				}
+				return string(profile.ResponseFormat.Type)
+			},
+			set: func(v string) error {
+				switch v {
+				case "default", "":
+					profile.ResponseFormat = nil
+				case string(openai.ChatCompletionResponseFormatTypeText):
+					profile.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeText}
+				case string(openai.ChatCompletionResponseFormatTypeJSONObject):
+					profile.ResponseFormat = &openai.ChatCompletionResponseFormat{Type: openai.ChatCompletionResponseFormatTypeJSONObject}
+				default:
+					return fmt.Errorf("unknown response format %q", v)
+				}
+				return nil
+			},
+		},
+		{
+			key: "user", name: "User", category: "Advanced", kind: kindText, optional: true,
+			description: "End-user identifier sent to the provider",
+			get: func() string {
+				if profile.User == nil {
+					return ""
+				}
+				return *profile.User
+			},
+			set: func(v string) error {
+				if v == "" {
+					profile.User = nil
+					return nil
+				}
+				profile.User = &v
+				return nil
+			},
+		},
+	}
+}
+
+// templateFields returns the editable fields of the commit template
+func templateFields(template *CommitTemplateConfig) []field {
+	return []field{
+		{
+			key: "style", name: "Style", category: "Commit Template", kind: kindText,
+			description: "Commit message style (conventional, simple, etc.)",
+			get:         func() string { return template.Style },
+			set: func(v string) error {
+				if v == "" {
+					return fmt.Errorf("style is required")
+				}
+				template.Style = v
+				return nil
+			},
+		},
+		{
+			key: "max_length", name: "Max Length", category: "Commit Template", kind: kindInt,
+			description: "Maximum commit header length",
+			get:         func() string { return strconv.Itoa(template.MaxLength) },
+			set:         setInt(&template.MaxLength, 10, 200),
+		},
+		{
+			key: "include_scope", name: "Include Scope", category: "Commit Template", kind: kindBool,
+			description: "Include scope in commit messages",
+			get:         func() string { return strconv.FormatBool(template.IncludeScope) },
+			set:         setBool(&template.IncludeScope),
+		},
+		{
+			key: "commit_without_confirmation", name: "Commit Without Confirmation", category: "Commit Template", kind: kindBool,
+			description: "Skip the review step when committing",
+			get:         func() string { return strconv.FormatBool(template.CommitWithoutConfirmation) },
+			set:         setBool(&template.CommitWithoutConfirmation),
+		},
+		{
+			key: "language", name: "Language", category: "Commit Template", kind: kindText, optional: true,
+			description: "Language of the messages (" + strings.Join(i18n.Codes(), ", ") + "); empty is English",
+			get:         func() string { return template.Language },
+			set: func(v string) error {
+				code := i18n.Normalize(v)
+				if v != "" && code == "" {
+					return fmt.Errorf("unknown language %q (expected %s)", v, strings.Join(i18n.Codes(), ", "))
+				}
+				template.Language = code
+				return nil
+			},
+		},
+	}
+}
+
+// commitFields returns the editable fields of the git commit settings
+func commitFields(commit *CommitConfig) []field {
+	return []field{
+		{
+			key: "cleanup", name: "Cleanup", category: "Commit", kind: kindChoice,
+			description: "How git cleans up the message (git commit --cleanup)",
+			choices:     git.CleanupModes,
+			get: func() string {
+				if commit.Cleanup == "" {
+					return "default"
+				}
+				return commit.Cleanup
+			},
+			set: func(v string) error {
+				if v == "default" {
+					v = ""
+				}
+				commit.Cleanup = v
+				return nil
+			},
+		},
+		{
+			key: "signoff", name: "Sign Off", category: "Commit", kind: kindBool,
+			description: "Add a Signed-off-by trailer (git commit --signoff)",
+			get:         func() string { return strconv.FormatBool(commit.Signoff) },
+			set:         setBool(&commit.Signoff),
+		},
+		{
+			key: "gpg_sign", name: "Sign Commits", category: "Commit", kind: kindChoice,
+			description: "Sign commits with GPG or SSH; default follows git's commit.gpgsign",
+			choices:     []string{"default", "true", "false"},
+			get: func() string {
+				if commit.GPGSign == nil {
+					return "default"
+				}
+				return strconv.FormatBool(*commit.GPGSign)
+			},
+			set: func(v string) error {
+				if v == "default" || v == "" {
+					commit.GPGSign = nil
+					return nil
+				}
+				return setBoolPtr(&commit.GPGSign)(v)
+			},
+		},
+		{
+			key: "signing_key", name: "Signing Key", category: "Commit", kind: kindText, optional: true,
+			description: "GPG key ID or SSH key to sign with; empty uses git's user.signingkey",
+			get:         func() string { return commit.SigningKey },
+			set:         func(v string) error { commit.SigningKey = v; return nil },
+		},
+		{
+			key: "no_verify", name: "Skip Hooks", category: "Commit", kind: kindBool,
+			description: "Skip the pre-commit and commit-msg hooks (git commit --no-verify)",
+			get:         func() string { return strconv.FormatBool(commit.NoVerify) },
+			set:         setBool(&commit.NoVerify),
+		},
+		{
+			key: "roster", name: "Team Roster", category: "Commit", kind: kindText, optional: true,
+			description: "Roster file for co-author aliases; empty uses .gitr_team or ~/.config/gitr/team",
+			get:         func() string { return commit.Roster },
+			set:         func(v string) error { commit.Roster = v; return nil },
+		},
+	}
+}
+
+// diffFields returns the editable fields of the diff sent to the model
+func diffFields(diff *DiffConfig) []field {
+	return []field{
+		{
+			key: "enrich", name: "Enrich Diff", category: "Diff", kind: kindBool,
+			description: "Add file headers and the enclosing function or type of each change",
+			get:         func() string { return strconv.FormatBool(diff.IsEnrichEnabled()) },
+			set:         setBoolPtr(&diff.Enrich),
+		},
+		{
+			key: "context_lines", name: "Context Lines", category: "Diff", kind: kindInt,
+			description: "Lines of context around each change (0 uses git's default of 3)",
+			get:         func() string { return strconv.Itoa(diff.ContextLines) },
+			set:         setInt(&diff.ContextLines, 0, 100),
+		},
+		{
+			key: "max_tokens", name: "Diff Token Budget", category: "Diff", kind: kindInt,
+			description: "Largest enriched diff to send, in tokens; larger diffs are sent plain (0 means no limit)",
+			get:         func() string { return strconv.Itoa(diff.MaxTokens) },
+			set:         setInt(&diff.MaxTokens, 0, 1<<20),
+		},
+		{
+			key: "api_check", name: "Go API Check", category: "Diff", kind: kindBool,
+			description: "Tell the model about breaking changes to exported Go identifiers",
+			get:         func() string { return strconv.FormatBool(diff.IsAPICheckEnabled()) },
+			set:         setBoolPtr(&diff.APICheck),
+		},
+		{
+			key: "api_internal", name: "Check Internal APIs", category: "Diff", kind: kindBool,
+			description: "Also check main packages and packages under internal/",
+			get:         func() string { return strconv.FormatBool(diff.APIInternal) },
+			set:         setBool(&diff.APIInternal),
+		},
+	}
+}
+
+// agentFields returns the editable fields of agent mode
+func agentFields(agent *AgentConfig) []field {
+	return []field{
+		{
+			key: "enabled", name: "Agent Mode", category: "Agent", kind: kindBool,
+			description: "Let the model read files, history and search the repository before answering",
+			get:         func() string { return strconv.FormatBool(agent.Enabled) },
+			set:         setBool(&agent.Enabled),
+		},
+		{
+			key: "max_tool_calls", name: "Max Tool Calls", category: "Agent", kind: kindInt,
+			description: fmt.Sprintf("Tool calls allowed per message (0 uses %d)", DefaultAgentMaxToolCalls),
+			get:         func() string { return strconv.Itoa(agent.MaxToolCalls) },
+			set:         setInt(&agent.MaxToolCalls, 0, 100),
+		},
+		{
+			key: "timeout_seconds", name: "Tool Time Limit", category: "Agent", kind: kindInt,
+			description: fmt.Sprintf("Seconds allowed for tool calls before the model must answer (0 uses %d)", DefaultAgentTimeoutSeconds),
+			get:         func() string { return strconv.Itoa(agent.TimeoutSeconds) },
+			set:         setInt(&agent.TimeoutSeconds, 0, 3600),
+		},
+	}
+}
+
+// tokensFields returns the editable fields of the prompt size check
+func tokensFields(tokens *TokensConfig) []field {
+	return []field{
+		{
+			key: "on_overflow", name: "Prompt Too Large", category: "Tokens", kind: kindChoice,
+			description: "When the prompt exceeds the context window: summarize the diff, warn, or fail",
+			choices:     OverflowModes,
+			get:         func() string { return tokens.Overflow() },
+			set: func(v string) error {
+				tokens.OnOverflow = v
+				return nil
+			},
+		},
+	}
+}
+
+// Typed setters with validation
+
+func setInt(target *int, min, max int) func(string) error {
+	return func(v string) error {
+		val, err := parseInt(v, min, max)
+		if err != nil {
+			return err
+		}
+		*target = val
+		return nil
+	}
+}
+
+func setIntPtr(target **int, min, max int) func(string) error {
+	return func(v string) error {
+		if v == "" {
+			*target = nil
+			return nil
+		}
+		val, err := parseInt(v, min, max)
+		if err != nil {
+			return err
+		}
+		*target = &val
+		return nil
+	}
+}
+
+func setFloatPtr(target **float32, min, max float64) func(string) error {
+	return func(v string) error {
+		if v == "" {
+			*target = nil
+			return nil
+		}
+		val, err := strconv.ParseFloat(v, 32)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", v)
+		}
+		if val < min || val > max {
+			return fmt.Errorf("must be between %g and %g", min, max)
+		}
+		fval := float32(val)
+		*target = &fval
+		return nil
+	}
+}
+
+func setBool(target *bool) func(string) error {
+	return func(v string) error {
+		val, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", v)
+		}
+		*target = val
+		return nil
+	}
+}
+
+func setBoolPtr(target **bool) func(string) error {
+	return func(v string) error {
+		val, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", v)
+		}
+		*target = &val
+		return nil
+	}
+}
+
+func parseInt(v string, min, max int) (int, error) {
+	val, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a whole number", v)
+	}
+	if val < min || val > max {
+		return 0, fmt.Errorf("must be between %d and %d", min, max)
+	}
+	return val, nil
+}
+
+func validateURL(v string) error {
+	u, err := url.Parse(v)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an http(s) URL", v)
+	}
+	return nil
+}
+
+func formatIntPtr(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func formatFloatPtr(v *float32) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(float64(*v), 'f', -1, 32)
+}
+
+func splitList(v string) []string {
+	var values []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+// offlineFields returns the editable fields of the offline generator
+func offlineFields(offline *OfflineConfig) []field {
+	return []field{
+		{
+			key: "fallback", name: "Offline Fallback", category: "Offline", kind: kindBool,
+			description: "Build a heuristic message from the changed files when every profile fails",
+			get:         func() string { return strconv.FormatBool(offline.IsFallbackEnabled()) },
+			set:         setBoolPtr(&offline.Fallback),
+		},
+	}
+}
+
+// reviewFields returns the editable fields of the code review
+func reviewFields(review *ReviewConfig) []field {
+	return []field{
+		{
+			key: "before_commit", name: "Review Before Commit", category: "Review", kind: kindBool,
+			description: "Review the staged changes before gitr -c commits them",
+			get:         func() string { return strconv.FormatBool(review.BeforeCommit) },
+			set:         setBool(&review.BeforeCommit),
+		},
+		{
+			key: "block_on", name: "Block Commit On", category: "Review", kind: kindChoice,
+			description: "Findings of this severity or worse block the commit (none never blocks)",
+			choices:     BlockSeverities,
+			get:         func() string { return review.BlockSeverity() },
+			set: func(v string) error {
+				review.BlockOn = v
+				return nil
+			},
+		},
+	}
+}
@@ -0,0 +1,406 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino-ext/components/model/openai"
+)
+
+// Setting is a configuration value addressed by its dotted key
+type Setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Secret bool   `json:"-"`
+	// Typed is the value as a bool, number, string or list of strings, for JSON output
+	Typed any `json:"-"`
+}
+
+// profilesKey is the section holding named profiles: profiles.<name>.openai.<field>
+const profilesKey = "profiles"
+
+var responseFormatType = reflect.TypeOf((*openai.ChatCompletionResponseFormat)(nil))
+
+// Get returns the value of a dotted key such as openai.model or commit_template.max_length.
+// The boolean is false when the key exists but isn't set.
+func (c *Config) Get(key string) (string, bool, error) {
+	value, err := c.lookup(key, false)
+	if err != nil {
+		return "", false, err
+	}
+	if isUnset(value) {
+		return "", false, nil
+	}
+	return formatValue(value), true, nil
+}
+
+// Set converts value to the type of the dotted key, validates it and stores it.
+// Setting a key of a profile that doesn't exist yet creates the profile.
+func (c *Config) Set(key, value string) error {
+	target, err := c.lookup(key, true)
+	if err != nil {
+		return err
+	}
+
+	// Fields known to the editor carry their own validation
+	if f, ok := c.editorField(key); ok {
+		if f.kind == kindChoice && !containsString(f.choices, value) {
+			return fmt.Errorf("%s: must be one of %s", key, strings.Join(f.choices, ", "))
+		}
+		if err := f.set(value); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+		return nil
+	}
+
+	if err := setValue(target, value); err != nil {
+		return fmt.Errorf("%s: %v", key, err)
+	}
+
+	// Profile references must name existing profiles
+	switch key {
+	case "default_profile":
+		if value != "" && !c.HasProfile(value) {
+			return fmt.Errorf("%s: profile %q not found", key, value)
+		}
+	case "fallback":
+		for _, name := range c.Fallback {
+			if !c.HasProfile(name) {
+				return fmt.Errorf("%s: profile %q not found", key, name)
+			}
+		}
+	}
+	return nil
+}
+
+// Unset clears a dotted key. Unsetting profiles.<name> deletes the profile.
+// Fields known to the editor are validated as for Set, so required settings
+// can't be cleared.
+func (c *Config) Unset(key string) error {
+	parts := strings.Split(key, ".")
+	if len(parts) == 2 && parts[0] == profilesKey {
+		return c.DeleteProfile(parts[1])
+	}
+
+	target, err := c.lookup(key, false)
+	if err != nil {
+		return err
+	}
+	previous := reflect.New(target.Type()).Elem()
+	previous.Set(target)
+	target.Set(reflect.Zero(target.Type()))
+
+	// Storing the cleared value through the field checks that it's allowed
+	if f, ok := c.editorField(key); ok {
+		if err := f.set(f.get()); err != nil {
+			target.Set(previous)
+			return fmt.Errorf("%s: can't be unset: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// Settings returns every key that has a value, in file order
+func (c *Config) Settings() []Setting {
+	var settings []Setting
+	collectSettings(reflect.ValueOf(c).Elem(), "", &settings)
+	return settings
+}
+
+// Keys returns every settable dotted key, with profiles shown as profiles.<name>
+func Keys() []string {
+	var keys []string
+	collectKeys(reflect.TypeOf(Config{}), "", &keys)
+	sort.Strings(keys)
+	return keys
+}
+
+// editorField returns the editor field for a key shown in the configuration editor
+func (c *Config) editorField(key string) (field, bool) {
+	parts := strings.Split(key, ".")
+	var fields []field
+	switch {
+	case len(parts) == 2 && parts[0] == "openai":
+		fields = profileFields(&c.OpenAI)
+	case len(parts) == 4 && parts[0] == profilesKey && parts[2] == "openai":
+		settings, err := c.Profile(parts[1])
+		if err != nil {
+			return field{}, false
+		}
+		fields = profileFields(settings)
+	case len(parts) == 2 && parts[0] == "commit_template":
+		fields = templateFields(&c.CommitTemplate)
+	case len(parts) == 2 && parts[0] == "commit":
+		fields = commitFields(&c.Commit)
+	case len(parts) == 2 && parts[0] == "diff":
+		fields = diffFields(&c.Diff)
+	case len(parts) == 2 && parts[0] == "agent":
+		fields = agentFields(&c.Agent)
+	case len(parts) == 2 && parts[0] == "tokens":
+		fields = tokensFields(&c.Tokens)
+	case len(parts) == 2 && parts[0] == "offline":
+		fields = offlineFields(&c.Offline)
+	case len(parts) == 2 && parts[0] == "review":
+		fields = reviewFields(&c.Review)
+	}
+
+	for _, f := range fields {
+		if f.key == parts[len(parts)-1] {
+			return f, true
+		}
+	}
+	return field{}, false
+}
+
+// lookup resolves a dotted key to the addressable value it names
+func (c *Config) lookup(key string, create bool) (reflect.Value, error) {
+	if key == "" {
+		return reflect.Value{}, fmt.Errorf("key is required")
+	}
+
+	current := reflect.ValueOf(c).Elem()
+	parts := strings.Split(key, ".")
+	for i := 0; i < len(parts); i++ {
+		if current.Kind() != reflect.Struct || current.Type() == responseFormatType.Elem() {
+			return reflect.Value{}, fmt.Errorf("unknown key %q", key)
+		}
+
+		index, ok := fieldByTag(current.Type(), parts[i])
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("unknown key %q", key)
+		}
+		current = current.Field(index)
+
+		// profiles.<name> selects a named profile
+		if parts[i] == profilesKey && current.Type() == reflect.TypeOf([]Profile{}) {
+			if i+1 >= len(parts) {
+				return reflect.Value{}, fmt.Errorf("%q is a section, not a key", key)
+			}
+			i++
+			profile, err := c.profileValue(parts[i], create)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			current = profile
+		}
+	}
+
+	if current.Kind() == reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%q is a section, not a key", key)
+	}
+	return current, nil
+}
+
+// profileValue returns the addressable Profile with the given name
+func (c *Config) profileValue(name string, create bool) (reflect.Value, error) {
+	if name == DefaultProfileName {
+		return reflect.Value{}, fmt.Errorf("the %q profile is stored under openai.*", DefaultProfileName)
+	}
+	if !c.HasProfile(name) {
+		if !create {
+			return reflect.Value{}, fmt.Errorf("profile %q not found", name)
+		}
+		if err := c.AddProfile(name, CreateDefaultConfig().OpenAI); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+
+	for i := range c.Profiles {
+		if c.Profiles[i].Name == name {
+			return reflect.ValueOf(&c.Profiles[i]).Elem(), nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("profile %q not found", name)
+}
+
+// fieldByTag finds the struct field whose XML element name matches name
+func fieldByTag(t reflect.Type, name string) (int, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		if tag, ok := xmlName(t.Field(i)); ok && tag == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// xmlName returns the element name of a struct field, or false if it isn't persisted
+func xmlName(f reflect.StructField) (string, bool) {
+	if !f.IsExported() || f.Name == "XMLName" {
+		return "", false
+	}
+	tag := f.Tag.Get("xml")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if strings.Contains(tag, ",attr") {
+		return "", false
+	}
+	// profiles>profile and fallback>profile are addressed by their outer element
+	name, _, _ = strings.Cut(name, ">")
+	if name == "" {
+		name = f.Name
+	}
+	return name, true
+}
+
+// setValue converts a string to the type of target and stores it
+func setValue(target reflect.Value, value string) error {
+	if target.Type() == responseFormatType {
+		return fmt.Errorf("must be set through a profile's openai.response_format")
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		if value == "" {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		elem := reflect.New(target.Type().Elem())
+		if err := setValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		target.Set(elem)
+	case reflect.String:
+		target.SetString(value)
+	case reflect.Int:
+		val, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a whole number", value)
+		}
+		if val < 0 {
+			return fmt.Errorf("must not be negative")
+		}
+		target.SetInt(int64(val))
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		target.SetFloat(val)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+		target.SetBool(val)
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", target.Type())
+		}
+		target.Set(reflect.ValueOf(splitList(value)))
+	default:
+		return fmt.Errorf("unsupported type %s", target.Type())
+	}
+	return nil
+}
+
+// formatValue converts a value to the string shown by get and list
+func formatValue(value reflect.Value) string {
+	if value.Type() == responseFormatType {
+		return string(value.Interface().(*openai.ChatCompletionResponseFormat).Type)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		return formatValue(value.Elem())
+	case reflect.Float32:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Sprintf("(%d entries)", value.Len())
+		}
+		return strings.Join(value.Interface().([]string), ",")
+	}
+	return fmt.Sprint(value.Interface())
+}
+
+// typedValue converts a value to the bool, number, string or []string it
+// holds, so JSON output keeps its type
+func typedValue(value reflect.Value) any {
+	if value.Type() == responseFormatType {
+		return formatValue(value)
+	}
+
+	switch value.Kind() {
+	case reflect.Ptr:
+		return typedValue(value.Elem())
+	case reflect.Bool:
+		return value.Bool()
+	case reflect.Int:
+		return value.Int()
+	case reflect.Float32, reflect.Float64:
+		// Go through the shortest representation so 0.7 isn't 0.699999988079071
+		number, _ := strconv.ParseFloat(formatValue(value), 64)
+		return number
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.String {
+			return append([]string{}, value.Interface().([]string)...)
+		}
+	}
+	return formatValue(value)
+}
+
+// isUnset reports whether a value would be omitted from the configuration file
+func isUnset(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Ptr:
+		return value.IsNil()
+	case reflect.Slice, reflect.String:
+		return value.Len() == 0
+	}
+	return false
+}
+
+// collectSettings appends every set leaf below value
+func collectSettings(value reflect.Value, prefix string, settings *[]Setting) {
+	t := value.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := xmlName(t.Field(i))
+		if !ok {
+			continue
+		}
+		key := prefix + name
+		field := value.Field(i)
+
+		switch {
+		case field.Type() == reflect.TypeOf([]Profile{}):
+			for _, profile := range field.Interface().([]Profile) {
+				profile := profile
+				collectSettings(reflect.ValueOf(&profile).Elem(), key+"."+profile.Name+".", settings)
+			}
+		case field.Kind() == reflect.Struct:
+			collectSettings(field, key+".", settings)
+		case field.Kind() == reflect.Slice && field.Type().Elem().Kind() != reflect.String:
+			// Tables such as pricing are edited in the file
+		case !isUnset(field):
+			*settings = append(*settings, Setting{Key: key, Value: formatValue(field), Secret: name == "api_key", Typed: typedValue(field)})
+		}
+	}
+}
+
+// collectKeys appends every settable key below t
+func collectKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		name, ok := xmlName(t.Field(i))
+		if !ok {
+			continue
+		}
+		key := prefix + name
+		fieldType := t.Field(i).Type
+
+		switch {
+		case fieldType == reflect.TypeOf([]Profile{}):
+			collectKeys(reflect.TypeOf(Profile{}), key+".<name>.", keys)
+		case fieldType.Kind() == reflect.Struct:
+			collectKeys(fieldType, key+".", keys)
+		case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() != reflect.String:
+			// Tables such as pricing are edited in the file
+		default:
+			*keys = append(*keys, key)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSettingsTyped(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	for key, value := range map[string]string{
+		"openai.temperature":         "0.7",
+		"commit_template.max_length": "50",
+		"agent.enabled":              "true",
+		"fallback":                   "backup,local",
+		"openai.model":               "gpt-4o-mini",
+	} {
+		if key == "fallback" {
+			for _, name := range []string{"backup", "local"} {
+				if err := cfg.Set("profiles."+name+".openai.model", "m"); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		if err := cfg.Set(key, value); err != nil {
+			t.Fatalf("Set(%s): %v", key, err)
+		}
+	}
+
+	typed := map[string]any{}
+	for _, setting := range cfg.Settings() {
+		typed[setting.Key] = setting.Typed
+	}
+	want := map[string]any{
+		"openai.temperature":         0.7,
+		"commit_template.max_length": int64(50),
+		"agent.enabled":              true,
+		"fallback":                   []string{"backup", "local"},
+		"openai.model":               "gpt-4o-mini",
+	}
+	for key, w := range want {
+		if !reflect.DeepEqual(typed[key], w) {
+			t.Errorf("%s = %#v, want %#v", key, typed[key], w)
+		}
+	}
+}
+
+func TestUnset(t *testing.T) {
+	cfg := CreateDefaultConfig()
+
+	for _, key := range []string{"openai.max_tokens", "openai.temperature", "commit_template.language"} {
+		if err := cfg.Unset(key); err != nil {
+			t.Errorf("Unset(%s): %v", key, err)
+		}
+	}
+	if cfg.OpenAI.MaxTokens != nil || cfg.OpenAI.Temperature != nil {
+		t.Errorf("optional settings weren't cleared: %v, %v", cfg.OpenAI.MaxTokens, cfg.OpenAI.Temperature)
+	}
+
+	for _, key := range []string{"commit_template.max_length", "commit_template.style"} {
+		if err := cfg.Unset(key); err == nil {
+			t.Errorf("Unset(%s) succeeded, want an error", key)
+		}
+	}
+	if cfg.CommitTemplate.MaxLength != 72 || cfg.CommitTemplate.Style == "" {
+		t.Errorf("a rejected unset changed the settings: %+v", cfg.CommitTemplate)
+	}
+}
+
+func TestFirstTimeSetupWithUnsetSettings(t *testing.T) {
+	cfg := CreateDefaultConfig()
+	cfg.OpenAI.MaxTokens = nil
+	cfg.OpenAI.Temperature = nil
+
+	input, err := os.CreateTemp(t.TempDir(), "input")
+	if err != nil {
+		t.Fatal(err)
+	}
+	input.WriteString("sk-test\n\n\n\n\n\n\n\n\n")
+	input.Seek(0, 0)
+	previous := os.Stdin
+	os.Stdin = input
+	defer func() { os.Stdin = previous }()
+
+	if err := RunFirstTimeSetup(cfg, filepath.Join(t.TempDir(), "config")); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.OpenAI.APIKey != "sk-test" || cfg.OpenAI.MaxTokens != nil || cfg.OpenAI.Temperature != nil {
+		t.Errorf("settings after setup: %+v", cfg.OpenAI)
+	}
+}
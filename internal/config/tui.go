@@ -1,12 +1,37 @@
 package config
 
 import (
+	"bufio"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
+	"gitr/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-isatty"
+)
+
+// Editor modes
+type editorMode int
+
+const (
+	modeBrowse editorMode = iota
+	modeEdit
+	modeProfileName
+	modeConfirmDelete
+	modeConfirmQuit
+)
+
+// profileAction is the profile operation waiting for a name
+type profileAction int
+
+const (
+	profileAdd profileAction = iota
+	profileClone
 )
 
 // TUI state
@@ -14,17 +39,21 @@ type configTUI struct {
 	config     *Config
 	configPath string
 	cursor     int
+	offset     int
 	fields     []field
 	width      int
 	height     int
-}
 
-type field struct {
-	name        string
-	value       string
-	description string
-	category    string
-	editFunc    func(string) error
+	mode          editorMode
+	input         textinput.Model
+	profileAction profileAction
+	inputErr      string
+	status        string
+
+	// dirty is set once anything changed since the last save
+	dirty   bool
+	saved   bool
+	saveErr error
 }
 
 // Styles
@@ -45,6 +74,12 @@ var (
 
 	helpStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#626262"))
+
+	errorStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FF5F87"))
+
+	categoryStyle = lipgloss.NewStyle().
+			Bold(true)
 )
 
 // NewConfigTUI creates a new configuration TUI
@@ -52,120 +87,19 @@ func NewConfigTUI(config *Config, configPath string) *configTUI {
 	tui := &configTUI{
 		config:     config,
 		configPath: configPath,
-		fields:     make([]field, 0),
+		input:      textinput.New(),
 	}
 
 	tui.setupFields()
 	return tui
 }
 
+// setupFields (re)builds the fields for the active profile
 func (t *configTUI) setupFields() {
-	// OpenAI Configuration
-	t.fields = append(t.fields, []field{
-		{
-			name:        "API Key",
-			value:       t.config.OpenAI.APIKey,
-			description: "Your OpenAI API key",
-			category:    "OpenAI",
-			editFunc:    func(v string) error { t.config.OpenAI.APIKey = v; return nil },
-		},
-		{
-			name:        "Base URL",
-			value:       t.config.OpenAI.BaseURL,
-			description: "OpenAI API base URL",
-			category:    "OpenAI",
-			editFunc:    func(v string) error { t.config.OpenAI.BaseURL = v; return nil },
-		},
-		{
-			name:        "Model",
-			value:       t.config.OpenAI.Model,
-			description: "Model to use for generating commit messages",
-			category:    "OpenAI",
-			editFunc:    func(v string) error { t.config.OpenAI.Model = v; return nil },
-		},
-		{
-			name:        "Max Tokens",
-			value:       fmt.Sprintf("%d", *t.config.OpenAI.MaxTokens),
-			description: "Maximum tokens for response",
-			category:    "OpenAI",
-			editFunc: func(v string) error {
-				val, err := strconv.Atoi(v)
-				if err != nil {
-					return err
-				}
-				t.config.OpenAI.MaxTokens = &val
-				return nil
-			},
-		},
-		{
-			name:        "Temperature",
-			value:       fmt.Sprintf("%.2f", *t.config.OpenAI.Temperature),
-			description: "Temperature for response generation (0.0-2.0)",
-			category:    "OpenAI",
-			editFunc: func(v string) error {
-				val, err := strconv.ParseFloat(v, 32)
-				if err != nil {
-					return err
-				}
-				fval := float32(val)
-				t.config.OpenAI.Temperature = &fval
-				return nil
-			},
-		},
-		{
-			name:        "Timeout",
-			value:       fmt.Sprintf("%d", t.config.OpenAI.Timeout),
-			description: "Request timeout in seconds",
-			category:    "OpenAI",
-			editFunc: func(v string) error {
-				val, err := strconv.Atoi(v)
-				if err != nil {
-					return err
-				}
-				t.config.OpenAI.Timeout = val
-				return nil
-			},
-		},
-	}...)
-
-	// Commit Template Configuration
-	t.fields = append(t.fields, []field{
-		{
-			name:        "Style",
-			value:       t.config.CommitTemplate.Style,
-			description: "Commit message style (conventional, simple, etc.)",
-			category:    "Commit Template",
-			editFunc:    func(v string) error { t.config.CommitTemplate.Style = v; return nil },
-		},
-		{
-			name:        "Max Length",
-			value:       fmt.Sprintf("%d", t.config.CommitTemplate.MaxLength),
-			description: "Maximum commit message length",
-			category:    "Commit Template",
-			editFunc: func(v string) error {
-				val, err := strconv.Atoi(v)
-				if err != nil {
-					return err
-				}
-				t.config.CommitTemplate.MaxLength = val
-				return nil
-			},
-		},
-		{
-			name:        "Include Scope",
-			value:       fmt.Sprintf("%t", t.config.CommitTemplate.IncludeScope),
-			description: "Include scope in commit messages",
-			category:    "Commit Template",
-			editFunc: func(v string) error {
-				val, err := strconv.ParseBool(v)
-				if err != nil {
-					return err
-				}
-				t.config.CommitTemplate.IncludeScope = val
-				return nil
-			},
-		},
-	}...)
+	t.fields = configFields(t.config)
+	if t.cursor >= len(t.fields) {
+		t.cursor = len(t.fields) - 1
+	}
 }
 
 func (t configTUI) Init() tea.Cmd {
@@ -177,311 +111,662 @@ func (t configTUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		t.width = msg.Width
 		t.height = msg.Height
+		t.input.Width = t.width - 30
 		return t, nil
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "ctrl+c", "q":
-			return t, tea.Quit
+		switch t.mode {
+		case modeEdit:
+			return t.updateEdit(msg)
+		case modeProfileName:
+			return t.updateProfileName(msg)
+		case modeConfirmDelete:
+			return t.updateConfirmDelete(msg)
+		case modeConfirmQuit:
+			return t.updateConfirmQuit(msg)
+		}
+		return t.updateBrowse(msg)
+	}
 
-		case "up", "k":
-			if t.cursor > 0 {
-				t.cursor--
-			}
+	return t, nil
+}
+
+func (t configTUI) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t.status = ""
+
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		if t.dirty {
+			t.mode = modeConfirmQuit
 			return t, nil
+		}
+		return t, tea.Quit
+
+	case "up", "k":
+		if t.cursor > 0 {
+			t.cursor--
+		}
 
-		case "down", "j":
-			if t.cursor < len(t.fields)-1 {
-				t.cursor++
+	case "down", "j":
+		if t.cursor < len(t.fields)-1 {
+			t.cursor++
+		}
+
+	case "home":
+		t.cursor = 0
+
+	case "end":
+		t.cursor = len(t.fields) - 1
+
+	case "enter", " ", "right", "l", "left", "h":
+		f := t.fields[t.cursor]
+		if f.kind == kindBool || f.kind == kindChoice {
+			step := 1
+			if key := msg.String(); key == "left" || key == "h" {
+				step = -1
 			}
+			t.setField(f, f.next(step))
+			return t, nil
+		}
+		if key := msg.String(); key != "enter" {
 			return t, nil
+		}
+		return t.startEdit(f)
 
-		case "enter", "":
-			// Edit the selected field
-			return t.editField(t.cursor), nil
+	case "s":
+		return t.save()
 
-		case "s":
-			// Save configuration
-			err := t.config.Save(t.configPath)
-			if err != nil {
-				return t, tea.Quit
-			}
-			return t, tea.Quit
+	case "p", "tab":
+		t.switchProfile(1)
+
+	case "P", "shift+tab":
+		t.switchProfile(-1)
+
+	case "n":
+		return t.startProfileName(profileAdd)
+
+	case "c":
+		return t.startProfileName(profileClone)
+
+	case "d":
+		if t.config.ActiveProfileName() == DefaultProfileName {
+			t.status = i18n.T("The default profile cannot be deleted")
+			return t, nil
 		}
+		t.mode = modeConfirmDelete
+
+	case "u":
+		if err := t.config.SetDefaultProfile(t.config.ActiveProfileName()); err != nil {
+			t.status = err.Error()
+			return t, nil
+		}
+		t.dirty = true
+		t.status = i18n.Sprintf("Default profile set to %s", t.config.ActiveProfileName())
 	}
 
 	return t, nil
 }
 
-func (t configTUI) View() string {
-	if t.width == 0 {
-		return "Loading..."
+func (t configTUI) startEdit(f field) (tea.Model, tea.Cmd) {
+	t.mode = modeEdit
+	t.inputErr = ""
+	t.input.Reset()
+	t.input.Prompt = f.name + ": "
+	t.input.Placeholder = ""
+	if f.optional {
+		t.input.Placeholder = i18n.T("empty to unset")
+	}
+	t.input.EchoMode = textinput.EchoNormal
+	if f.kind == kindSecret {
+		t.input.EchoMode = textinput.EchoPassword
+	}
+	t.input.SetValue(f.get())
+	t.input.CursorEnd()
+	return t, t.input.Focus()
+}
+
+func (t configTUI) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		t.input.Blur()
+		t.mode = modeBrowse
+		return t, nil
+
+	case "enter":
+		f := t.fields[t.cursor]
+		if err := t.setField(f, strings.TrimSpace(t.input.Value())); err != nil {
+			t.inputErr = err.Error()
+			return t, nil
+		}
+		t.input.Blur()
+		t.mode = modeBrowse
+		return t, nil
 	}
 
-	var content strings.Builder
+	var cmd tea.Cmd
+	t.input, cmd = t.input.Update(msg)
+	return t, cmd
+}
 
-	// Title
-	title := titleStyle.Render("GitR Configuration Editor")
-	content.WriteString(title + "\n\n")
+func (t configTUI) startProfileName(action profileAction) (tea.Model, tea.Cmd) {
+	t.mode = modeProfileName
+	t.profileAction = action
+	t.inputErr = ""
+	t.input.Reset()
+	t.input.EchoMode = textinput.EchoNormal
+	t.input.Placeholder = ""
+	t.input.Prompt = i18n.T("New profile name: ")
+	if action == profileClone {
+		t.input.Prompt = i18n.Sprintf("Clone %s as: ", t.config.ActiveProfileName())
+	}
+	return t, t.input.Focus()
+}
 
-	// Instructions
-	help := helpStyle.Render("↑/↓: Navigate • Enter: Edit • s: Save & Exit • q: Quit without saving")
-	content.WriteString(help + "\n\n")
+func (t configTUI) updateProfileName(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		t.input.Blur()
+		t.mode = modeBrowse
+		return t, nil
 
-	// Fields
-	for i, field := range t.fields {
-		var style lipgloss.Style
-		if i == t.cursor {
-			style = selectedStyle
+	case "enter":
+		name := strings.TrimSpace(t.input.Value())
+		var err error
+		if t.profileAction == profileClone {
+			err = t.config.CloneProfile(t.config.ActiveProfileName(), name)
 		} else {
-			style = fieldStyle
+			err = t.config.AddProfile(name, CreateDefaultConfig().OpenAI)
 		}
-
-		// Category header
-		if i == 0 || t.fields[i-1].category != field.category {
-			content.WriteString(fmt.Sprintf("\n%s:\n", field.category))
+		if err == nil {
+			err = t.config.SelectProfile(name)
 		}
+		if err != nil {
+			t.inputErr = err.Error()
+			return t, nil
+		}
+		t.dirty = true
+		t.input.Blur()
+		t.mode = modeBrowse
+		t.setupFields()
+		t.status = i18n.Sprintf("Profile %s created", name)
+		return t, nil
+	}
 
-		// Field
-		fieldText := fmt.Sprintf(" %s: %s", field.name, field.value)
-		content.WriteString(style.Render(fieldText) + "\n")
-		content.WriteString(fmt.Sprintf("   %s\n", helpStyle.Render(field.description)))
+	var cmd tea.Cmd
+	t.input, cmd = t.input.Update(msg)
+	return t, cmd
+}
+
+func (t configTUI) updateConfirmDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	t.mode = modeBrowse
+	if msg.String() != "y" {
+		return t, nil
 	}
 
-	// Footer
-	content.WriteString("\n" + helpStyle.Render("Press 's' to save changes or 'q' to quit without saving"))
+	name := t.config.ActiveProfileName()
+	if err := t.config.DeleteProfile(name); err != nil {
+		t.status = err.Error()
+		return t, nil
+	}
+	t.dirty = true
+	t.config.SelectProfile("")
+	t.setupFields()
+	t.status = i18n.Sprintf("Profile %s deleted", name)
+	return t, nil
+}
 
-	return content.String()
+func (t configTUI) updateConfirmQuit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y", "s":
+		return t.save()
+	case "n", "d":
+		return t, tea.Quit
+	case "esc", "c":
+		t.mode = modeBrowse
+	}
+	return t, nil
+}
+
+// setField validates and applies a new value, tracking whether anything changed
+func (t *configTUI) setField(f field, value string) error {
+	before := f.get()
+	if err := f.set(value); err != nil {
+		return err
+	}
+	if f.get() != before {
+		t.dirty = true
+	}
+	return nil
+}
+
+func (t *configTUI) switchProfile(step int) {
+	names := t.config.ProfileNames()
+	current := t.config.ActiveProfileName()
+	for i, name := range names {
+		if name == current {
+			t.config.SelectProfile(names[(i+step+len(names))%len(names)])
+			break
+		}
+	}
+	t.setupFields()
+}
+
+func (t configTUI) save() (tea.Model, tea.Cmd) {
+	if err := t.config.Save(t.configPath); err != nil {
+		t.mode = modeBrowse
+		t.status = i18n.Sprintf("Error saving config: %v", err)
+		t.saveErr = err
+		return t, nil
+	}
+	t.saved = true
+	t.saveErr = nil
+	return t, tea.Quit
 }
 
-func (t configTUI) editField(index int) tea.Model {
-	if index >= len(t.fields) {
-		return t
+func (t configTUI) View() string {
+	if t.width == 0 {
+		return i18n.T("Loading...")
 	}
 
-	field := t.fields[index]
+	var content strings.Builder
 
-	// Simple input prompt
-	fmt.Printf("\nEnter new value for %s (current: %s): ", field.name, field.value)
+	// Title
+	title := titleStyle.Render(i18n.T("GitR Configuration Editor"))
+	dirty := ""
+	if t.dirty {
+		dirty = i18n.T(" (modified)")
+	}
+	content.WriteString(title + helpStyle.Render(" "+t.configPath+dirty) + "\n")
+
+	// Profiles
+	var profiles []string
+	for _, name := range t.config.ProfileNames() {
+		label := name
+		if name == defaultProfileLabel(t.config) {
+			label += i18n.T(" (default)")
+		}
+		if name == t.config.ActiveProfileName() {
+			label = selectedStyle.Render(label)
+		}
+		profiles = append(profiles, label)
+	}
+	content.WriteString(i18n.T("Profiles: ") + strings.Join(profiles, " ") + "\n")
 
-	var input string
-	fmt.Scanln(&input)
+	// Fields, scrolled so the cursor stays visible
+	lines, cursorLine := t.renderFields()
+	visible := t.height - 7
+	if visible < 5 {
+		visible = 5
+	}
+	offset := t.offset
+	if cursorLine < offset {
+		offset = cursorLine
+	}
+	if cursorLine >= offset+visible {
+		offset = cursorLine - visible + 1
+	}
+	end := offset + visible
+	if end > len(lines) {
+		end = len(lines)
+	}
+	content.WriteString(strings.Join(lines[offset:end], "\n") + "\n\n")
 
-	if input != "" {
-		err := field.editFunc(input)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
+	// Footer
+	switch t.mode {
+	case modeEdit, modeProfileName:
+		content.WriteString(t.input.View() + "\n")
+		if t.inputErr != "" {
+			content.WriteString(errorStyle.Render(t.inputErr))
 		} else {
-			// Update the field value
-			t.fields[index].value = input
+			content.WriteString(helpStyle.Render(i18n.T("Enter: Apply • Esc: Cancel")))
 		}
+	case modeConfirmDelete:
+		content.WriteString(errorStyle.Render(i18n.Sprintf("Delete profile %s? [y/N]", t.config.ActiveProfileName())))
+	case modeConfirmQuit:
+		content.WriteString(errorStyle.Render(i18n.T("You have unsaved changes. Save before quitting? [y]es / [n]o / [esc] cancel")))
+	default:
+		if t.status != "" {
+			content.WriteString(errorStyle.Render(t.status) + "\n")
+		} else {
+			content.WriteString(helpStyle.Render(t.fields[t.cursor].description) + "\n")
+		}
+		content.WriteString(helpStyle.Render(i18n.T("↑/↓: Navigate • Enter: Edit/Toggle • ←/→: Change choice • s: Save & Exit • q: Quit")))
+		content.WriteString("\n" + helpStyle.Render(i18n.T("p/P: Switch profile • n: New profile • c: Clone • d: Delete • u: Use as default")))
 	}
 
-	return t
+	return content.String()
+}
+
+// renderFields returns the field lines and the line index of the cursor
+func (t configTUI) renderFields() ([]string, int) {
+	var lines []string
+	cursorLine := 0
+	for i, f := range t.fields {
+		// Category header
+		if i == 0 || t.fields[i-1].category != f.category {
+			lines = append(lines, "", categoryStyle.Render(f.category+":"))
+		}
+
+		style := fieldStyle
+		if i == t.cursor {
+			style = selectedStyle
+			cursorLine = len(lines)
+		}
+		lines = append(lines, style.Render(fmt.Sprintf("%-28s %s", f.name, f.display())))
+	}
+	return lines, cursorLine
 }
 
-// RunConfigEditor runs the configuration editor
+// RunConfigEditor runs the configuration editor. It uses the full-screen
+// editor in a terminal and falls back to line-based prompts otherwise.
 func RunConfigEditor(config *Config, configPath string) error {
-	// For now, use a simple interactive editor
-	fmt.Println("=== GitR Configuration Editor ===")
-	fmt.Printf("Configuration file: %s\n\n", configPath)
+	if !isatty.IsTerminal(os.Stdout.Fd()) || !isatty.IsTerminal(os.Stdin.Fd()) {
+		return runConfigPrompts(config, configPath)
+	}
 
-	// Display current configuration
-	fmt.Println("Current Configuration:")
-	fmt.Println("=====================")
-	fmt.Printf("API Key: %s\n", maskAPIKey(config.OpenAI.APIKey))
-	fmt.Printf("Base URL: %s\n", config.OpenAI.BaseURL)
-	fmt.Printf("Model: %s\n", config.OpenAI.Model)
-	fmt.Printf("Max Tokens: %d\n", *config.OpenAI.MaxTokens)
-	fmt.Printf("Temperature: %.2f\n", *config.OpenAI.Temperature)
-	fmt.Printf("Timeout: %d seconds\n", config.OpenAI.Timeout)
-	fmt.Printf("Style: %s\n", config.CommitTemplate.Style)
-	fmt.Printf("Max Length: %d\n", config.CommitTemplate.MaxLength)
-	fmt.Printf("Include Scope: %t\n", config.CommitTemplate.IncludeScope)
-	fmt.Printf("Commit Without Confirmation: %t\n", config.CommitTemplate.CommitWithoutConfirmation)
-
-	fmt.Println("\nOptions:")
-	fmt.Println("1. Edit API Key")
-	fmt.Println("2. Edit Base URL")
-	fmt.Println("3. Edit Model")
-	fmt.Println("4. Edit Max Tokens")
-	fmt.Println("5. Edit Temperature")
-	fmt.Println("6. Edit Timeout")
-	fmt.Println("7. Edit Style")
-	fmt.Println("8. Edit Max Length")
-	fmt.Println("9. Edit Include Scope")
-	fmt.Println("10. Edit Commit Without Confirmation")
-	fmt.Println("s. Save and exit")
-	fmt.Println("q. Quit without saving")
+	program := tea.NewProgram(NewConfigTUI(config, configPath), tea.WithAltScreen())
+	finalModel, err := program.Run()
+	if err != nil {
+		return err
+	}
+
+	t, ok := finalModel.(configTUI)
+	if !ok {
+		return nil
+	}
+	switch {
+	case t.saveErr != nil:
+		return fmt.Errorf("failed to save configuration: %v", t.saveErr)
+	case t.saved:
+		i18n.Printf("Configuration saved to: %s\n", configPath)
+	case t.dirty:
+		fmt.Println(i18n.T("Changes discarded."))
+	}
+	return nil
+}
+
+// stdin is shared by the line-based prompts so buffered input isn't lost between reads
+var stdin = bufio.NewReader(os.Stdin)
+
+// runConfigPrompts runs the line-based configuration editor
+func runConfigPrompts(config *Config, configPath string) error {
+	fmt.Println(i18n.T("=== GitR Configuration Editor ==="))
+	i18n.Printf("Configuration file: %s\n\n", configPath)
+
+	fields := configFields(config)
+	printConfigEditorMenu(config, fields)
 
 	for {
-		fmt.Print("\nEnter choice: ")
-		var choice string
-		fmt.Scanln(&choice)
+		choice := promptValue(i18n.T("\nEnter choice"))
 
 		switch choice {
-		case "1":
-			editStringField("API Key", &config.OpenAI.APIKey)
-		case "2":
-			editStringField("Base URL", &config.OpenAI.BaseURL)
-		case "3":
-			editStringField("Model", &config.OpenAI.Model)
-		case "4":
-			editIntField("Max Tokens", config.OpenAI.MaxTokens)
-		case "5":
-			editFloatField("Temperature", config.OpenAI.Temperature)
-		case "6":
-			editIntField("Timeout", &config.OpenAI.Timeout)
-		case "7":
-			editStringField("Style", &config.CommitTemplate.Style)
-		case "8":
-			editIntField("Max Length", &config.CommitTemplate.MaxLength)
-		case "9":
-			editBoolField("Include Scope", &config.CommitTemplate.IncludeScope)
-		case "10":
-			editBoolField("Commit Without Confirmation", &config.CommitTemplate.CommitWithoutConfirmation)
+		case "p":
+			manageProfiles(config)
+			fields = configFields(config)
+			printConfigEditorMenu(config, fields)
+		case "l":
+			printConfigEditorMenu(config, fields)
 		case "s":
 			err := config.Save(configPath)
 			if err != nil {
-				fmt.Printf("Error saving config: %v\n", err)
+				i18n.Printf("Error saving config: %v\n", err)
 				continue
 			}
-			fmt.Println("Configuration saved successfully!")
+			fmt.Println(i18n.T("Configuration saved successfully!"))
 			return nil
-		case "q":
-			fmt.Println("Exiting without saving...")
+		case "q", "":
+			fmt.Println(i18n.T("Exiting without saving..."))
 			return nil
 		default:
-			fmt.Println("Invalid choice. Please try again.")
+			index, err := strconv.Atoi(choice)
+			if err != nil || index < 1 || index > len(fields) {
+				fmt.Println(i18n.T("Invalid choice. Please try again."))
+				continue
+			}
+			editFieldPrompt(fields[index-1])
 		}
 	}
 }
 
-func maskAPIKey(key string) string {
-	if len(key) <= 8 {
-		return "***"
+// printConfigEditorMenu displays the current configuration and the editor options
+func printConfigEditorMenu(config *Config, fields []field) {
+	fmt.Println(i18n.T("Current Configuration:"))
+	fmt.Println("=====================")
+	i18n.Printf("Profile: %s (default: %s, available: %s)\n", config.ActiveProfileName(), defaultProfileLabel(config), strings.Join(config.ProfileNames(), ", "))
+
+	for i, f := range fields {
+		if i == 0 || fields[i-1].category != f.category {
+			fmt.Printf("\n%s:\n", f.category)
+		}
+		fmt.Printf("%3d. %-28s %s\n", i+1, f.name, f.display())
 	}
-	return key[:4] + "..." + key[len(key)-4:]
+
+	fmt.Println(i18n.T("\nOptions:"))
+	fmt.Println(i18n.T("<number>. Edit field"))
+	fmt.Println(i18n.T("p. Manage profiles"))
+	fmt.Println(i18n.T("l. List settings again"))
+	fmt.Println(i18n.T("s. Save and exit"))
+	fmt.Println(i18n.T("q. Quit without saving"))
 }
 
-func editStringField(name string, value *string) {
-	fmt.Printf("Enter new value for %s (current: %s): ", name, *value)
-	var input string
-	fmt.Scanln(&input)
-	if input != "" {
-		*value = input
-		fmt.Printf("%s updated to: %s\n", name, *value)
+// editFieldPrompt asks for a new value of a field until it validates
+func editFieldPrompt(f field) {
+	hint := ""
+	switch {
+	case f.kind == kindBool:
+		hint = " [true/false]"
+	case f.kind == kindChoice:
+		hint = " [" + strings.Join(f.choices, "/") + "]"
+	case f.optional:
+		hint = " ('-' to unset)"
 	}
-}
 
-func editIntField(name string, value *int) {
-	fmt.Printf("Enter new value for %s (current: %d): ", name, *value)
-	var input string
-	fmt.Scanln(&input)
-	if input != "" {
-		if val, err := strconv.Atoi(input); err == nil {
-			*value = val
-			fmt.Printf("%s updated to: %d\n", name, *value)
-		} else {
-			fmt.Printf("Invalid number: %s\n", input)
+	for {
+		input := promptValue(i18n.Sprintf("Enter new value for %s (current: %s)%s", f.name, f.display(), hint))
+		if input == "" {
+			return
+		}
+		if input == "-" && f.optional {
+			input = ""
+		}
+		if f.kind == kindChoice && !containsString(f.choices, input) {
+			i18n.Printf("Invalid value: choose one of %s\n", strings.Join(f.choices, ", "))
+			continue
 		}
+		if err := f.set(input); err != nil {
+			i18n.Printf("Invalid value: %v\n", err)
+			continue
+		}
+		i18n.Printf("%s updated to: %s\n", f.name, f.display())
+		return
 	}
 }
 
-func editFloatField(name string, value *float32) {
-	fmt.Printf("Enter new value for %s (current: %.2f): ", name, *value)
-	var input string
-	fmt.Scanln(&input)
-	if input != "" {
-		if val, err := strconv.ParseFloat(input, 32); err == nil {
-			*value = float32(val)
-			fmt.Printf("%s updated to: %.2f\n", name, *value)
-		} else {
-			fmt.Printf("Invalid number: %s\n", input)
+// manageProfiles runs the profile management submenu
+func manageProfiles(config *Config) {
+	for {
+		fmt.Println("")
+		fmt.Println(i18n.T("Profiles:"))
+		for _, name := range config.ProfileNames() {
+			settings, _ := config.Profile(name)
+			marker := " "
+			if name == config.ActiveProfileName() {
+				marker = "*"
+			}
+			fmt.Printf(" %s %s (%s, %s)\n", marker, name, providerLabel(settings.Provider), settings.Model)
+		}
+		i18n.Printf("Default profile: %s\n", defaultProfileLabel(config))
+		fmt.Println("")
+		fmt.Println(i18n.T("a. Add profile"))
+		fmt.Println(i18n.T("c. Clone profile"))
+		fmt.Println(i18n.T("d. Delete profile"))
+		fmt.Println(i18n.T("u. Use profile as default"))
+		fmt.Println(i18n.T("e. Edit another profile"))
+		fmt.Println(i18n.T("b. Back"))
+
+		choice := promptValue(i18n.T("\nEnter choice"))
+
+		var err error
+		switch choice {
+		case "a":
+			name := promptValue(i18n.T("New profile name"))
+			err = config.AddProfile(name, CreateDefaultConfig().OpenAI)
+			if err == nil {
+				err = config.SelectProfile(name)
+				i18n.Printf("Profile %s added. Its settings are now being edited.\n", name)
+			}
+		case "c":
+			source := promptValue(i18n.T("Profile to clone"))
+			name := promptValue(i18n.T("New profile name"))
+			err = config.CloneProfile(source, name)
+			if err == nil {
+				err = config.SelectProfile(name)
+				i18n.Printf("Profile %s cloned to %s. Its settings are now being edited.\n", source, name)
+			}
+		case "d":
+			name := promptValue(i18n.T("Profile to delete"))
+			err = config.DeleteProfile(name)
+			if err == nil {
+				i18n.Printf("Profile %s deleted.\n", name)
+			}
+		case "u":
+			name := promptValue(i18n.T("Profile to use by default"))
+			err = config.SetDefaultProfile(name)
+			if err == nil {
+				i18n.Printf("Default profile set to %s.\n", name)
+			}
+		case "e":
+			name := promptValue(i18n.T("Profile to edit"))
+			err = config.SelectProfile(name)
+		case "b", "":
+			return
+		default:
+			fmt.Println(i18n.T("Invalid choice. Please try again."))
+		}
+
+		if err != nil {
+			i18n.Printf("Error: %v\n", err)
 		}
 	}
 }
 
-func editBoolField(name string, value *bool) {
-	fmt.Printf("Enter new value for %s (current: %t) [true/false]: ", name, *value)
-	var input string
-	fmt.Scanln(&input)
-	if input != "" {
-		if val, err := strconv.ParseBool(input); err == nil {
-			*value = val
-			fmt.Printf("%s updated to: %t\n", name, *value)
-		} else {
-			fmt.Printf("Invalid boolean: %s\n", input)
+// promptValue prints a label and reads one trimmed line
+func promptValue(label string) string {
+	fmt.Printf("%s: ", label)
+	input, _ := stdin.ReadString('\n')
+	return strings.TrimSpace(input)
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
 		}
 	}
+	return false
+}
+
+func defaultProfileLabel(config *Config) string {
+	if config.DefaultProfile == "" {
+		return DefaultProfileName
+	}
+	return config.DefaultProfile
+}
+
+func providerLabel(provider string) string {
+	if provider == "" {
+		return ProviderOpenAI
+	}
+	return provider
+}
+
+// MaskAPIKey hides all but the ends of an API key
+func MaskAPIKey(key string) string {
+	if len(key) <= 8 {
+		return "***"
+	}
+	return key[:4] + "..." + key[len(key)-4:]
 }
 
 // RunFirstTimeSetup runs the first-time configuration setup
 func RunFirstTimeSetup(config *Config, configPath string) error {
-	fmt.Println("Welcome to GitR!")
+	fmt.Println(i18n.T("Welcome to GitR!"))
 	fmt.Println("==================")
-	fmt.Println("Let's set up your configuration for AI-powered commit messages.")
+	fmt.Println(i18n.T("Let's set up your configuration for AI-powered commit messages."))
 	fmt.Println("")
 
+	profile := config.Active()
+	if config.ActiveProfileName() != DefaultProfileName {
+		i18n.Printf("Profile: %s\n\n", config.ActiveProfileName())
+	}
+
 	// Required fields setup
-	fmt.Println("Required Configuration:")
+	fmt.Println(i18n.T("Required Configuration:"))
 	fmt.Println("-------------------------")
 
 	// API Key
 	for {
-		fmt.Print("Enter your OpenAI API Key: ")
+		fmt.Print(i18n.T("Enter your OpenAI API Key: "))
 		var apiKey string
 		fmt.Scanln(&apiKey)
-		if apiKey != "" {
-			config.OpenAI.APIKey = apiKey
+		if apiKey != "" || profile.IsLocal() {
+			profile.APIKey = apiKey
 			break
 		}
-		fmt.Println("API Key is required!")
+		fmt.Println(i18n.T("API Key is required!"))
 	}
 
 	// Base URL
-	fmt.Printf("Enter API Base URL (default: %s): ", config.OpenAI.BaseURL)
+	i18n.Printf("Enter API Base URL (default: %s): ", profile.BaseURL)
 	var baseURL string
 	fmt.Scanln(&baseURL)
 	if baseURL != "" {
-		config.OpenAI.BaseURL = baseURL
+		profile.BaseURL = baseURL
 	}
 
 	// Model
-	fmt.Printf("Enter Model name (default: %s): ", config.OpenAI.Model)
+	i18n.Printf("Enter Model name (default: %s): ", profile.Model)
 	var model string
 	fmt.Scanln(&model)
 	if model != "" {
-		config.OpenAI.Model = model
+		profile.Model = model
 	}
 
 	fmt.Println("")
-	fmt.Println("️  Optional Configuration:")
+	fmt.Println(i18n.T("️  Optional Configuration:"))
 	fmt.Println("-------------------------")
 
-	// Max Tokens
-	fmt.Printf("Enter Max Tokens (default: %d): ", *config.OpenAI.MaxTokens)
+	// Max Tokens and Temperature may be unset, leaving them to the provider
+	if profile.MaxTokens != nil {
+		i18n.Printf("Enter Max Tokens (default: %d): ", *profile.MaxTokens)
+	} else {
+		fmt.Print(i18n.T("Enter Max Tokens (default: the provider's): "))
+	}
 	var maxTokensStr string
 	fmt.Scanln(&maxTokensStr)
 	if maxTokensStr != "" {
 		if val, err := strconv.Atoi(maxTokensStr); err == nil {
-			config.OpenAI.MaxTokens = &val
+			profile.MaxTokens = &val
 		}
 	}
 
 	// Temperature
-	fmt.Printf("Enter Temperature (default: %.2f): ", *config.OpenAI.Temperature)
+	if profile.Temperature != nil {
+		i18n.Printf("Enter Temperature (default: %.2f): ", *profile.Temperature)
+	} else {
+		fmt.Print(i18n.T("Enter Temperature (default: the provider's): "))
+	}
 	var tempStr string
 	fmt.Scanln(&tempStr)
 	if tempStr != "" {
 		if val, err := strconv.ParseFloat(tempStr, 32); err == nil {
 			fval := float32(val)
-			config.OpenAI.Temperature = &fval
+			profile.Temperature = &fval
 		}
 	}
 
 	// Commit Style
-	fmt.Printf("Enter Commit Style (default: %s): ", config.CommitTemplate.Style)
+	i18n.Printf("Enter Commit Style (default: %s): ", config.CommitTemplate.Style)
 	var style string
 	fmt.Scanln(&style)
 	if style != "" {
@@ -489,7 +774,7 @@ func RunFirstTimeSetup(config *Config, configPath string) error {
 	}
 
 	// Max Length
-	fmt.Printf("Enter Max Commit Length (default: %d): ", config.CommitTemplate.MaxLength)
+	i18n.Printf("Enter Max Commit Length (default: %d): ", config.CommitTemplate.MaxLength)
 	var maxLengthStr string
 	fmt.Scanln(&maxLengthStr)
 	if maxLengthStr != "" {
@@ -499,7 +784,7 @@ func RunFirstTimeSetup(config *Config, configPath string) error {
 	}
 
 	// Include Scope
-	fmt.Printf("Include scope in commits? (default: %t) [true/false]: ", config.CommitTemplate.IncludeScope)
+	i18n.Printf("Include scope in commits? (default: %t) [true/false]: ", config.CommitTemplate.IncludeScope)
 	var scopeStr string
 	fmt.Scanln(&scopeStr)
 	if scopeStr != "" {
@@ -509,7 +794,7 @@ func RunFirstTimeSetup(config *Config, configPath string) error {
 	}
 
 	// Commit Without Confirmation
-	fmt.Printf("Commit without confirmation? (default: %t) [true/false]: ", config.CommitTemplate.CommitWithoutConfirmation)
+	i18n.Printf("Commit without confirmation? (default: %t) [true/false]: ", config.CommitTemplate.CommitWithoutConfirmation)
 	var bypassStr string
 	fmt.Scanln(&bypassStr)
 	if bypassStr != "" {
@@ -519,7 +804,7 @@ func RunFirstTimeSetup(config *Config, configPath string) error {
 	}
 
 	fmt.Println("")
-	fmt.Println("Saving configuration...")
+	fmt.Println(i18n.T("Saving configuration..."))
 
 	// Save the configuration
 	err := config.Save(configPath)
@@ -527,10 +812,10 @@ func RunFirstTimeSetup(config *Config, configPath string) error {
 		return fmt.Errorf("failed to save configuration: %v", err)
 	}
 
-	fmt.Printf("Configuration saved to: %s\n", configPath)
+	i18n.Printf("Configuration saved to: %s\n", configPath)
 	fmt.Println("")
-	fmt.Println("Setup complete! You can now use GitR to generate commit messages.")
-	fmt.Println("  Run 'gitr config' anytime to modify your settings.")
+	fmt.Println(i18n.T("Setup complete! You can now use GitR to generate commit messages."))
+	fmt.Println(i18n.T("  Run 'gitr config' anytime to modify your settings."))
 	fmt.Println("")
 
 	return nil
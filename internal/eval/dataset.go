@@ -0,0 +1,98 @@
+// Package eval scores generated commit messages against the messages people
+// wrote for the same changes.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Sample is a diff and the reference message written for it
+type Sample struct {
+	ID        string `json:"id"`
+	Diff      string `json:"diff"`
+	Reference string `json:"reference"`
+}
+
+// LoadDataset reads samples from a JSON Lines file with one
+// {"id", "diff", "reference"} object per line. Missing IDs are numbered.
+func LoadDataset(path string) ([]Sample, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var samples []Sample
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var sample Sample
+		if err := json.Unmarshal([]byte(text), &sample); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		if strings.TrimSpace(sample.Diff) == "" {
+			return nil, fmt.Errorf("%s:%d: sample has no diff", path, line)
+		}
+		if sample.ID == "" {
+			sample.ID = fmt.Sprintf("sample-%d", len(samples)+1)
+		}
+		samples = append(samples, sample)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return samples, nil
+}
+
+// SaveDataset writes samples as JSON Lines, so a dataset built from history can be reused
+func SaveDataset(path string, samples []Sample) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, sample := range samples {
+		if err := encoder.Encode(sample); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// FromHistory builds samples from up to count non-merge commits reachable
+// from rev, using each commit's diff and message. Commits without changes
+// are skipped.
+func FromHistory(rev string, count int) ([]Sample, error) {
+	hashes, err := git.Commits(rev, count)
+	if err != nil {
+		return nil, err
+	}
+
+	var samples []Sample
+	for _, hash := range hashes {
+		diff, err := git.CommitDiff(hash)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(diff) == "" {
+			continue
+		}
+		message, err := git.CommitMessage(hash)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, Sample{ID: hash[:12], Diff: diff, Reference: message})
+	}
+	return samples, nil
+}
@@ -0,0 +1,181 @@
+package eval
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Result is the outcome of one sample with one profile
+type Result struct {
+	Sample           string   `json:"sample"`
+	Profile          string   `json:"profile"`
+	Model            string   `json:"model"`
+	Message          string   `json:"message,omitempty"`
+	Error            string   `json:"error,omitempty"`
+	Score            *Score   `json:"score,omitempty"`
+	LatencyMs        int64    `json:"latency_ms"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	CostUSD          *float64 `json:"cost_usd,omitempty"`
+}
+
+// Summary aggregates the results of one profile. Rates are between 0 and 1
+// and only count samples that produced a message.
+type Summary struct {
+	Profile          string   `json:"profile"`
+	Model            string   `json:"model"`
+	Samples          int      `json:"samples"`
+	Errors           int      `json:"errors"`
+	LintPassRate     float64  `json:"lint_pass_rate"`
+	WithinLengthRate float64  `json:"within_length_rate"`
+	AvgHeaderLength  float64  `json:"avg_header_length"`
+	TypeAccuracy     *float64 `json:"type_accuracy,omitempty"`
+	AvgJudge         *float64 `json:"avg_judge,omitempty"`
+	AvgLatencyMs     int64    `json:"avg_latency_ms"`
+	PromptTokens     int      `json:"prompt_tokens"`
+	CompletionTokens int      `json:"completion_tokens"`
+	CostUSD          float64  `json:"cost_usd"`
+}
+
+// Report is the comparison of all profiles on a dataset
+type Report struct {
+	Version   int       `json:"version"`
+	Dataset   string    `json:"dataset"`
+	Samples   []Sample  `json:"samples"`
+	Summaries []Summary `json:"summaries"`
+	Results   []Result  `json:"results"`
+}
+
+// ReportVersion is bumped when the JSON report changes incompatibly
+const ReportVersion = 1
+
+// Summarize aggregates the results of one profile
+func Summarize(profile, model string, results []Result) Summary {
+	summary := Summary{Profile: profile, Model: model, Samples: len(results)}
+
+	var scored, lintPassed, withinLength, headerLength, typed, typeMatches, judged int
+	var judgeTotal float64
+	var latency int64
+	for _, result := range results {
+		latency += result.LatencyMs
+		summary.PromptTokens += result.PromptTokens
+		summary.CompletionTokens += result.CompletionTokens
+		if result.CostUSD != nil {
+			summary.CostUSD += *result.CostUSD
+		}
+		if result.Score == nil {
+			summary.Errors++
+			continue
+		}
+
+		score := result.Score
+		scored++
+		headerLength += score.HeaderLength
+		if len(score.LintProblems) == 0 {
+			lintPassed++
+		}
+		if score.WithinLength {
+			withinLength++
+		}
+		if score.TypeMatch != nil {
+			typed++
+			if *score.TypeMatch {
+				typeMatches++
+			}
+		}
+		if score.Judge != nil {
+			judged++
+			judgeTotal += *score.Judge
+		}
+	}
+
+	if len(results) > 0 {
+		summary.AvgLatencyMs = latency / int64(len(results))
+	}
+	if scored > 0 {
+		summary.LintPassRate = float64(lintPassed) / float64(scored)
+		summary.WithinLengthRate = float64(withinLength) / float64(scored)
+		summary.AvgHeaderLength = float64(headerLength) / float64(scored)
+	}
+	if typed > 0 {
+		accuracy := float64(typeMatches) / float64(typed)
+		summary.TypeAccuracy = &accuracy
+	}
+	if judged > 0 {
+		average := judgeTotal / float64(judged)
+		summary.AvgJudge = &average
+	}
+	return summary
+}
+
+// Markdown renders the report as a Markdown document: a comparison table
+// followed by every generated message next to its reference
+func (r Report) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# GitR evaluation\n\nDataset: %s (%d samples)\n\n", r.Dataset, len(r.Samples))
+
+	b.WriteString("| Profile | Model | Errors | Lint pass | Within length | Avg header | Type accuracy | Judge | Avg latency | Tokens | Cost |\n")
+	b.WriteString("| --- | --- | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: | ---: |\n")
+	for _, s := range r.Summaries {
+		fmt.Fprintf(&b, "| %s | %s | %d | %s | %s | %.1f | %s | %s | %d ms | %d | $%.4f |\n",
+			s.Profile, s.Model, s.Errors, percent(s.LintPassRate), percent(s.WithinLengthRate), s.AvgHeaderLength,
+			optionalPercent(s.TypeAccuracy), optionalRating(s.AvgJudge), s.AvgLatencyMs,
+			s.PromptTokens+s.CompletionTokens, s.CostUSD)
+	}
+
+	b.WriteString("\n## Samples\n")
+	for _, sample := range r.Samples {
+		fmt.Fprintf(&b, "\n### %s\n\n| Profile | Message | Lint | Type | Judge |\n| --- | --- | --- | --- | ---: |\n", sample.ID)
+		fmt.Fprintf(&b, "| *reference* | %s | | | |\n", cell(header(sample.Reference)))
+		for _, result := range r.Results {
+			if result.Sample != sample.ID {
+				continue
+			}
+			if result.Score == nil {
+				fmt.Fprintf(&b, "| %s | *error: %s* | | | |\n", result.Profile, cell(result.Error))
+				continue
+			}
+			lint := "ok"
+			if len(result.Score.LintProblems) > 0 {
+				lint = strings.Join(result.Score.LintProblems, "; ")
+			}
+			typeMatch := ""
+			if result.Score.TypeMatch != nil {
+				typeMatch = map[bool]string{true: "match", false: "differs"}[*result.Score.TypeMatch]
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n",
+				result.Profile, cell(header(result.Message)), cell(lint), typeMatch, optionalRating(result.Score.Judge))
+		}
+	}
+	return b.String()
+}
+
+func percent(rate float64) string {
+	return fmt.Sprintf("%.0f%%", rate*100)
+}
+
+func optionalPercent(rate *float64) string {
+	if rate == nil {
+		return "-"
+	}
+	return percent(*rate)
+}
+
+func optionalRating(rating *float64) string {
+	if rating == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f", *rating)
+}
+
+// header returns the first line of a message
+func header(message string) string {
+	first, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return first
+}
+
+// cell escapes text for a Markdown table cell
+func cell(text string) string {
+	text = strings.ReplaceAll(text, "|", "\\|")
+	return strings.ReplaceAll(text, "\n", " ")
+}
@@ -0,0 +1,71 @@
+package eval
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gitr/internal/git"
+	"gitr/internal/output"
+)
+
+// Score holds the deterministic checks of one generated message and the
+// judge's rating, if one was asked
+type Score struct {
+	LintProblems []string `json:"lint_problems"`
+	HeaderLength int      `json:"header_length"`
+	WithinLength bool     `json:"within_length"`
+	// TypeMatch compares the conventional type with the reference's; nil when the reference has none
+	TypeMatch *bool    `json:"type_match,omitempty"`
+	Judge     *float64 `json:"judge,omitempty"`
+}
+
+// Check scores a generated message against the reference
+func Check(generated, reference string, opts output.LintOptions) Score {
+	parsed := output.ParseConventional(generated)
+	score := Score{
+		LintProblems: output.Lint(generated, opts),
+		HeaderLength: utf8.RuneCountInString(parsed.Header),
+	}
+	if score.LintProblems == nil {
+		score.LintProblems = []string{}
+	}
+	score.WithinLength = opts.MaxLength == 0 || score.HeaderLength <= opts.MaxLength
+
+	if want := output.ParseConventional(reference).Type; want != "" {
+		match := strings.EqualFold(parsed.Type, want)
+		score.TypeMatch = &match
+	}
+	return score
+}
+
+// JudgeInstruction is the system prompt of the LLM judge
+const JudgeInstruction = `You evaluate generated git commit messages. You get a diff, the message a developer wrote for it (the reference) and a generated candidate.
+Rate the candidate from 1 to 5: 5 means it describes the change as accurately and usefully as the reference or better, 1 means it is wrong or useless.
+Judge the content, not the wording. Answer with the number only.`
+
+// judgeDiffTokens is how much of the diff the judge gets to see
+const judgeDiffTokens = 3000
+
+// JudgePrompt builds the user prompt asking the judge to rate a candidate.
+// Long diffs are summarized so the prompt stays small.
+func JudgePrompt(sample Sample, candidate string, count func(string) int) string {
+	diff := git.SummarizeDiff(sample.Diff, func(text string) bool {
+		return count(text) <= judgeDiffTokens
+	})
+	return fmt.Sprintf("Diff:\n\n%s\n\nReference message:\n\n%s\n\nCandidate message:\n\n%s\n",
+		diff, strings.TrimSpace(sample.Reference), strings.TrimSpace(candidate))
+}
+
+var ratingPattern = regexp.MustCompile(`\b([1-5](?:\.\d+)?)\b`)
+
+// ParseRating extracts the 1-5 rating from the judge's answer
+func ParseRating(answer string) (float64, error) {
+	m := ratingPattern.FindStringSubmatch(answer)
+	if m == nil {
+		return 0, fmt.Errorf("no rating in judge answer %q", strings.TrimSpace(answer))
+	}
+	return strconv.ParseFloat(m[1], 64)
+}
@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/output"
+)
+
+func TestCheck(t *testing.T) {
+	opts := output.LintOptions{MaxLength: 50, Conventional: true}
+	yes, no := true, false
+
+	tests := []struct {
+		name             string
+		generated        string
+		reference        string
+		wantProblems     int
+		wantLength       int
+		wantWithinLength bool
+		wantTypeMatch    *bool
+	}{
+		{
+			name:             "clean message with matching type",
+			generated:        "feat(api): add greeting",
+			reference:        "feat: add Hello",
+			wantLength:       23,
+			wantWithinLength: true,
+			wantTypeMatch:    &yes,
+		},
+		{
+			name:             "type is compared case-insensitively",
+			generated:        "Fix: handle empty input",
+			reference:        "fix(parser): guard against empty input",
+			wantLength:       23,
+			wantWithinLength: true,
+			wantTypeMatch:    &yes,
+		},
+		{
+			name:             "different type",
+			generated:        "chore: update files",
+			reference:        "fix: close the file on error",
+			wantLength:       19,
+			wantWithinLength: true,
+			wantTypeMatch:    &no,
+		},
+		{
+			name:             "reference without a type",
+			generated:        "feat: add greeting",
+			reference:        "Add a greeting",
+			wantLength:       18,
+			wantWithinLength: true,
+		},
+		{
+			name:             "header over the limit",
+			generated:        "feat: " + strings.Repeat("x", 50),
+			reference:        "feat: add things",
+			wantProblems:     1,
+			wantLength:       56,
+			wantWithinLength: false,
+			wantTypeMatch:    &yes,
+		},
+		{
+			name:             "not conventional",
+			generated:        "Updated the files.",
+			reference:        "docs: fix typo",
+			wantProblems:     2,
+			wantLength:       18,
+			wantWithinLength: true,
+			wantTypeMatch:    &no,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := Check(tt.generated, tt.reference, opts)
+			if score.LintProblems == nil {
+				t.Error("LintProblems is nil, want an empty list")
+			}
+			if len(score.LintProblems) != tt.wantProblems {
+				t.Errorf("lint problems = %q, want %d", score.LintProblems, tt.wantProblems)
+			}
+			if score.HeaderLength != tt.wantLength {
+				t.Errorf("HeaderLength = %d, want %d", score.HeaderLength, tt.wantLength)
+			}
+			if score.WithinLength != tt.wantWithinLength {
+				t.Errorf("WithinLength = %v, want %v", score.WithinLength, tt.wantWithinLength)
+			}
+			switch {
+			case tt.wantTypeMatch == nil && score.TypeMatch != nil:
+				t.Errorf("TypeMatch = %v, want nil", *score.TypeMatch)
+			case tt.wantTypeMatch != nil && score.TypeMatch == nil:
+				t.Errorf("TypeMatch = nil, want %v", *tt.wantTypeMatch)
+			case tt.wantTypeMatch != nil && *score.TypeMatch != *tt.wantTypeMatch:
+				t.Errorf("TypeMatch = %v, want %v", *score.TypeMatch, *tt.wantTypeMatch)
+			}
+		})
+	}
+}
+
+func TestCheckWithoutLimit(t *testing.T) {
+	score := Check("feat: "+strings.Repeat("x", 200), "", output.LintOptions{})
+	if !score.WithinLength {
+		t.Error("a header is over the limit when there is none")
+	}
+}
+
+func TestParseRating(t *testing.T) {
+	tests := []struct {
+		answer  string
+		want    float64
+		wantErr bool
+	}{
+		{"4", 4, false},
+		{" 5\n", 5, false},
+		{"Rating: 3.5/5", 3.5, false},
+		{"I'd give it a 2.", 2, false},
+		{"10", 0, true},
+		{"0", 0, true},
+		{"none", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRating(tt.answer)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseRating(%q) = %v, %v; want %v, error %v", tt.answer, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	yes, no := true, false
+	judge := 4.0
+	cost := 0.25
+	results := []Result{
+		{LatencyMs: 100, PromptTokens: 10, CompletionTokens: 5, CostUSD: &cost,
+			Score: &Score{LintProblems: []string{}, HeaderLength: 20, WithinLength: true, TypeMatch: &yes, Judge: &judge}},
+		{LatencyMs: 300, PromptTokens: 20, CompletionTokens: 5, CostUSD: &cost,
+			Score: &Score{LintProblems: []string{"subject ends with a period"}, HeaderLength: 80, WithinLength: false, TypeMatch: &no}},
+		{LatencyMs: 200, Error: "timeout"},
+	}
+
+	summary := Summarize("default", "gpt-4o-mini", results)
+	if summary.Samples != 3 || summary.Errors != 1 {
+		t.Errorf("samples = %d, errors = %d", summary.Samples, summary.Errors)
+	}
+	if summary.LintPassRate != 0.5 || summary.WithinLengthRate != 0.5 || summary.AvgHeaderLength != 50 {
+		t.Errorf("rates = %v, %v, length %v", summary.LintPassRate, summary.WithinLengthRate, summary.AvgHeaderLength)
+	}
+	if summary.TypeAccuracy == nil || *summary.TypeAccuracy != 0.5 {
+		t.Errorf("TypeAccuracy = %v", summary.TypeAccuracy)
+	}
+	if summary.AvgJudge == nil || *summary.AvgJudge != 4 {
+		t.Errorf("AvgJudge = %v", summary.AvgJudge)
+	}
+	if summary.AvgLatencyMs != 200 || summary.PromptTokens != 30 || summary.CompletionTokens != 10 || summary.CostUSD != 0.5 {
+		t.Errorf("summary = %+v", summary)
+	}
+
+	if empty := Summarize("default", "m", nil); empty.TypeAccuracy != nil || empty.AvgJudge != nil || empty.LintPassRate != 0 {
+		t.Errorf("empty summary = %+v", empty)
+	}
+}
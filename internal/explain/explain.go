@@ -0,0 +1,120 @@
+// Package explain describes in plain language what a commit or a range of
+// commits did, for people who are new to the code.
+package explain
+
+import (
+	"fmt"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Depths of an explanation; each adds a layer to the previous one
+const (
+	DepthSummary = 1 // a one-sentence summary
+	DepthFiles   = 2 // what changed in every file
+	DepthRisks   = 3 // possible risks
+)
+
+// Commit is a commit of the explained revision
+type Commit struct {
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+}
+
+// Target is the commit or range to explain and what it changed
+type Target struct {
+	Spec    string
+	From    string
+	To      string
+	Commits []Commit
+	Diff    string
+}
+
+// maxCommits is how many commits of a range are listed in the prompt
+const maxCommits = 50
+
+// fullMessages is how many commits get their whole message in the prompt;
+// beyond that only subjects are listed
+const fullMessages = 10
+
+// Load resolves a revision or range and reads its commits and enriched diff
+func Load(spec string) (*Target, error) {
+	from, to, err := git.ResolveRange(spec)
+	if err != nil {
+		return nil, err
+	}
+	target := &Target{Spec: spec, From: from, To: to}
+
+	hashes := []string{to}
+	if git.IsRange(spec) {
+		if hashes, err = git.Commits(from+".."+to, maxCommits); err != nil {
+			return nil, err
+		}
+	}
+	for _, hash := range hashes {
+		message, err := git.CommitMessage(hash)
+		if err != nil {
+			return nil, err
+		}
+		target.Commits = append(target.Commits, Commit{Hash: hash, Message: message})
+	}
+
+	if target.Diff, err = git.EnrichedDiff(git.EnrichOptions{From: from, To: to}); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Instruction is the system prompt for an explanation of the given depth
+func Instruction(depth int) string {
+	var b strings.Builder
+	b.WriteString(`You explain git changes to an engineer who is new to the codebase.
+Use plain language, name the files and functions involved and don't speculate beyond what the commit messages and the diff show.
+Write plain text for a terminal: no Markdown headings, tables or code fences.
+`)
+	switch {
+	case depth <= DepthSummary:
+		b.WriteString("Answer with one sentence that says what the change does and why.")
+	default:
+		b.WriteString("Start with one sentence that says what the change does and why.\n")
+		b.WriteString("Then write a blank line, \"What changed:\" and one bullet per file, or group of closely related files, saying what changed in it and how it fits the whole.")
+		if depth >= DepthRisks {
+			b.WriteString("\nFinish with a blank line, \"Possible risks:\" and bullets on behavior changes, edge cases, compatibility or migration concerns and what deserves extra testing. Write \"None apparent\" if there are none.")
+		}
+	}
+	return b.String()
+}
+
+// Prompt builds the user prompt with the commit messages and the diff
+func Prompt(target *Target) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Explain %s.\n\n", target.Spec)
+
+	if len(target.Commits) == 1 {
+		fmt.Fprintf(&b, "Commit message:\n\n%s\n\n", target.Commits[0].Message)
+	} else if len(target.Commits) > 1 {
+		fmt.Fprintf(&b, "Commits, newest first:\n\n")
+		for _, commit := range target.Commits {
+			message := commit.Message
+			if len(target.Commits) > fullMessages {
+				message, _, _ = strings.Cut(message, "\n")
+			}
+			fmt.Fprintf(&b, "%s %s\n", short(commit.Hash), strings.ReplaceAll(message, "\n", "\n    "))
+		}
+		if len(target.Commits) == maxCommits {
+			fmt.Fprintf(&b, "(only the newest %d commits are listed)\n", maxCommits)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "Diff:\n\n%s", target.Diff)
+	return b.String()
+}
+
+func short(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
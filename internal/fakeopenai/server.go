@@ -0,0 +1,272 @@
+// Package fakeopenai is an OpenAI-compatible chat completions server that
+// answers with scripted replies and records every request, for exercising
+// GitR end to end without a provider.
+package fakeopenai
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reply is a scripted answer to one request
+type Reply struct {
+	// Status is the HTTP status code; 0 means 200. Other codes answer with an error body.
+	Status int
+	// RetryAfter is sent as the Retry-After header, e.g. "1"
+	RetryAfter string
+	// Delay holds the response back, e.g. to trigger client timeouts
+	Delay time.Duration
+
+	Content   string
+	ToolCalls []ToolCall
+	// FinishReason defaults to "tool_calls" when there are tool calls and "stop" otherwise
+	FinishReason     string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// ToolCall is a function call requested by the model
+type ToolCall struct {
+	Name      string
+	Arguments string // JSON object
+}
+
+// Request is a recorded chat completions request
+type Request struct {
+	Model      string          `json:"model"`
+	Messages   []Message       `json:"messages"`
+	Tools      []Tool          `json:"tools"`
+	ToolChoice json.RawMessage `json:"tool_choice"`
+	MaxTokens  int             `json:"max_tokens"`
+	Stream     bool            `json:"stream"`
+	// Body is the raw request body
+	Body []byte `json:"-"`
+}
+
+// Message is a chat message of a recorded request
+type Message struct {
+	Role       string `json:"role"`
+	Content    string `json:"content"`
+	ToolCallID string `json:"tool_call_id"`
+}
+
+// Tool is a tool offered in a recorded request
+type Tool struct {
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
+}
+
+// Server is a running fake server
+type Server struct {
+	// URL is the base URL to configure as base_url, ending in /v1
+	URL string
+	// Default answers requests once the script is used up; the zero value answers with an error
+	Default *Reply
+
+	mu       sync.Mutex
+	replies  []Reply
+	requests []Request
+	server   *httptest.Server
+}
+
+// New starts a server that answers with the given replies in order
+func New(replies ...Reply) *Server {
+	s := &Server{replies: replies}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	s.URL = s.server.URL + "/v1"
+	return s
+}
+
+// Close shuts the server down
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Script adds replies to the end of the script
+func (s *Server) Script(replies ...Reply) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replies = append(s.replies, replies...)
+}
+
+// Requests returns the requests received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest returns the latest request, or false if there was none
+func (s *Server) LastRequest() (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return Request{}, false
+	}
+	return s.requests[len(s.requests)-1], true
+}
+
+// next records a request and returns its reply
+func (s *Server) next(request Request) (Reply, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, request)
+	if len(s.replies) > 0 {
+		reply := s.replies[0]
+		s.replies = s.replies[1:]
+		return reply, true
+	}
+	if s.Default != nil {
+		return *s.Default, true
+	}
+	return Reply{}, false
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		writeError(w, http.StatusNotFound, "not found: "+r.Method+" "+r.URL.Path)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var request Request
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid JSON: "+err.Error())
+		return
+	}
+	request.Body = body
+
+	reply, ok := s.next(request)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("no scripted reply for request %d", len(s.Requests())))
+		return
+	}
+
+	if reply.Delay > 0 {
+		select {
+		case <-time.After(reply.Delay):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if reply.RetryAfter != "" {
+		w.Header().Set("Retry-After", reply.RetryAfter)
+	}
+	if reply.Status != 0 && reply.Status != http.StatusOK {
+		writeError(w, reply.Status, fmt.Sprintf("scripted status %d", reply.Status))
+		return
+	}
+
+	if request.Stream {
+		writeStream(w, request, reply)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(completion(request, reply))
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]any{"message": message, "type": "fake_error"},
+	})
+}
+
+// completion builds a chat.completion response
+func completion(request Request, reply Reply) map[string]any {
+	message := map[string]any{"role": "assistant", "content": reply.Content}
+	if calls := toolCalls(reply); len(calls) > 0 {
+		message["tool_calls"] = calls
+	}
+	return map[string]any{
+		"id":      "chatcmpl-fake",
+		"object":  "chat.completion",
+		"created": time.Now().Unix(),
+		"model":   request.Model,
+		"choices": []any{map[string]any{
+			"index":         0,
+			"message":       message,
+			"finish_reason": finishReason(reply),
+		}},
+		"usage": usage(reply),
+	}
+}
+
+// writeStream sends the reply as server-sent events: the content, then the
+// finish reason with the usage
+func writeStream(w http.ResponseWriter, request Request, reply Reply) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	flusher, _ := w.(http.Flusher)
+	send := func(chunk map[string]any) {
+		data, _ := json.Marshal(chunk)
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	chunk := func(delta map[string]any, finish any) map[string]any {
+		return map[string]any{
+			"id":      "chatcmpl-fake",
+			"object":  "chat.completion.chunk",
+			"created": time.Now().Unix(),
+			"model":   request.Model,
+			"choices": []any{map[string]any{"index": 0, "delta": delta, "finish_reason": finish}},
+		}
+	}
+
+	for _, word := range strings.SplitAfter(reply.Content, " ") {
+		send(chunk(map[string]any{"role": "assistant", "content": word}, nil))
+	}
+	if calls := toolCalls(reply); len(calls) > 0 {
+		for i, call := range calls {
+			call["index"] = i
+		}
+		send(chunk(map[string]any{"role": "assistant", "tool_calls": calls}, nil))
+	}
+	last := chunk(map[string]any{}, finishReason(reply))
+	last["usage"] = usage(reply)
+	send(last)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+}
+
+func toolCalls(reply Reply) []map[string]any {
+	var calls []map[string]any
+	for i, call := range reply.ToolCalls {
+		calls = append(calls, map[string]any{
+			"id":       fmt.Sprintf("call_%d", i+1),
+			"type":     "function",
+			"function": map[string]any{"name": call.Name, "arguments": call.Arguments},
+		})
+	}
+	return calls
+}
+
+func finishReason(reply Reply) string {
+	switch {
+	case reply.FinishReason != "":
+		return reply.FinishReason
+	case len(reply.ToolCalls) > 0:
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+func usage(reply Reply) map[string]any {
+	return map[string]any{
+		"prompt_tokens":     reply.PromptTokens,
+		"completion_tokens": reply.CompletionTokens,
+		"total_tokens":      reply.PromptTokens + reply.CompletionTokens,
+	}
+}
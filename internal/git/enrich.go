@@ -0,0 +1,428 @@
+package git
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EnrichOptions controls the diff prepared for the model
+type EnrichOptions struct {
+	All     bool // diff every change to tracked files against HEAD instead of the staged changes
+	Context int  // lines of context around each change; 0 uses git's default of 3
+	// From and To compare two revisions instead, e.g. a commit with its parent
+	From, To string
+}
+
+// EnrichedDiff returns the diff with a short header per file and the
+// enclosing function or type of every hunk. Go files are parsed with
+// go/parser; other files use the function name git puts in hunk headers,
+// which follows the diff drivers set in .gitattributes.
+func EnrichedDiff(opts EnrichOptions) (string, error) {
+	files, err := annotatedFiles(opts)
+	if err != nil {
+		return "", err
+	}
+
+	var enriched strings.Builder
+	for _, file := range files {
+		enriched.WriteString(file.String())
+	}
+	return enriched.String(), nil
+}
+
+// FileChange summarizes the change to one file
+type FileChange struct {
+	Path    string // path after the change
+	OldPath string // path before a rename
+	Status  string // added, modified, deleted or renamed
+	Binary  bool
+	Added   int
+	Deleted int
+	Symbols []string // enclosing functions and types of the changed lines
+}
+
+// Changes summarizes the staged changes per file, or with all every change
+// to tracked files
+func Changes(all bool) ([]FileChange, error) {
+	files, err := annotatedFiles(EnrichOptions{All: all})
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]FileChange, len(files))
+	for i, f := range files {
+		changes[i] = FileChange{
+			Path:    f.path(),
+			Status:  f.status,
+			Binary:  f.binary,
+			Added:   f.added,
+			Deleted: f.deleted,
+			Symbols: f.symbols,
+		}
+		if f.status == "renamed" {
+			changes[i].OldPath = f.oldPath
+		}
+	}
+	return changes, nil
+}
+
+// annotatedFiles runs git diff and finds the enclosing symbols of every hunk
+func annotatedFiles(opts EnrichOptions) ([]*diffFile, error) {
+	args := append([]string{"diff", "--no-color", "--no-ext-diff"}, prefixArgs...)
+	if opts.Context > 0 {
+		args = append(args, "-U"+strconv.Itoa(opts.Context))
+	}
+	// The revisions whose files are parsed for symbols; "" is the index
+	oldRev, newRev := "HEAD", ""
+	switch {
+	case opts.From != "":
+		args = append(args, opts.From, opts.To, "--")
+		oldRev, newRev = opts.From, opts.To
+	case opts.All:
+		args = append(args, headOrEmptyTree())
+		newRev = RevisionWorktree
+	default:
+		args = append(args, "--cached")
+	}
+
+	output, err := Command(args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed: %v", err)
+	}
+
+	files := splitDiff(string(output))
+	for _, file := range files {
+		file.annotate(oldRev, newRev)
+	}
+	return files, nil
+}
+
+// diffFile is the part of a unified diff for one file
+type diffFile struct {
+	header  []string // diff --git, index, ---/+++ and mode lines
+	hunks   []*diffHunk
+	oldPath string
+	newPath string
+	status  string
+	binary  bool
+
+	added   int
+	deleted int
+	symbols []string // enclosing symbols of all hunks, in order
+}
+
+// diffHunk is one @@ section of a file diff
+type diffHunk struct {
+	ranges             string // "@@ -a,b +c,d @@"
+	oldStart, newStart int
+	lines              []string
+	funcName           string   // function name from git's hunk header
+	symbols            []string // enclosing symbols of the changed lines
+}
+
+var hunkPattern = regexp.MustCompile(`^(@@ -(\d+)(?:,\d+)? \+(\d+)(?:,\d+)? @@) ?(.*)$`)
+
+// splitDiff splits a unified diff into files and hunks
+func splitDiff(diff string) []*diffFile {
+	var files []*diffFile
+	var file *diffFile
+	var hunk *diffHunk
+
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			file = &diffFile{header: []string{line}, status: "modified"}
+			files = append(files, file)
+			hunk = nil
+			continue
+		}
+		if file == nil {
+			continue
+		}
+
+		if m := hunkPattern.FindStringSubmatch(line); m != nil {
+			oldStart, _ := strconv.Atoi(m[2])
+			newStart, _ := strconv.Atoi(m[3])
+			hunk = &diffHunk{ranges: m[1], oldStart: oldStart, newStart: newStart, funcName: strings.TrimSpace(m[4])}
+			file.hunks = append(file.hunks, hunk)
+			continue
+		}
+		if hunk != nil {
+			hunk.lines = append(hunk.lines, line)
+			switch {
+			case strings.HasPrefix(line, "+"):
+				file.added++
+			case strings.HasPrefix(line, "-"):
+				file.deleted++
+			}
+			continue
+		}
+
+		// File header
+		file.header = append(file.header, line)
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			file.oldPath = strings.TrimPrefix(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			file.newPath = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+		case strings.HasPrefix(line, "new file mode"):
+			file.status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			file.status = "renamed"
+			file.oldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.newPath = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "Binary files "):
+			file.binary = true
+		}
+	}
+
+	// Files without ---/+++ lines (binary, pure renames, mode changes) take the path from diff --git
+	for _, f := range files {
+		if f.newPath == "" || f.newPath == "/dev/null" {
+			if f.oldPath == "" || f.oldPath == "/dev/null" {
+				f.oldPath = diffGitPath(f.header[0])
+			}
+			if f.newPath == "" {
+				f.newPath = f.oldPath
+			}
+		}
+	}
+	return files
+}
+
+// diffGitPath returns the new path from a "diff --git a/x b/x" line
+func diffGitPath(line string) string {
+	path := strings.TrimPrefix(line, "diff --git ")
+	if i := strings.Index(path, " b/"); i >= 0 {
+		return path[i+3:]
+	}
+	return path
+}
+
+// path returns the path a file has after the change
+func (f *diffFile) path() string {
+	if f.newPath == "/dev/null" {
+		return f.oldPath
+	}
+	return f.newPath
+}
+
+// annotate finds the enclosing symbols of every hunk, reading the files
+// before and after the change from the given revisions
+func (f *diffFile) annotate(oldRev, newRev string) {
+	var oldDecls, newDecls []goDecl
+	if strings.HasSuffix(f.path(), ".go") && !f.binary {
+		if f.status != "added" {
+			oldDecls = parseGoDecls(f.oldPath, readRevision(oldRev+":"+f.oldPath))
+		}
+		if f.status != "deleted" {
+			if newRev == RevisionWorktree {
+				newDecls = parseGoDecls(f.newPath, readWorktree(f.newPath))
+			} else {
+				newDecls = parseGoDecls(f.newPath, readRevision(newRev+":"+f.newPath))
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, hunk := range f.hunks {
+		if oldDecls != nil || newDecls != nil {
+			hunk.symbols = hunk.changedSymbols(oldDecls, newDecls)
+		} else if hunk.funcName != "" {
+			hunk.symbols = []string{hunk.funcName}
+		}
+		for _, symbol := range hunk.symbols {
+			if !seen[symbol] {
+				seen[symbol] = true
+				f.symbols = append(f.symbols, symbol)
+			}
+		}
+	}
+}
+
+// changedSymbols returns the declarations containing the hunk's added and removed lines
+func (h *diffHunk) changedSymbols(oldDecls, newDecls []goDecl) []string {
+	var symbols []string
+	seen := map[string]bool{}
+	add := func(decls []goDecl, line int) {
+		for _, decl := range decls {
+			if line >= decl.start && line <= decl.end && !seen[decl.name] {
+				seen[decl.name] = true
+				symbols = append(symbols, decl.name)
+			}
+		}
+	}
+
+	oldLine, newLine := h.oldStart, h.newStart
+	for _, line := range h.lines {
+		switch {
+		case strings.HasPrefix(line, "+"):
+			add(newDecls, newLine)
+			newLine++
+		case strings.HasPrefix(line, "-"):
+			add(oldDecls, oldLine)
+			oldLine++
+		case strings.HasPrefix(line, `\`):
+			// "\ No newline at end of file"
+		default:
+			oldLine++
+			newLine++
+		}
+	}
+	return symbols
+}
+
+// String renders the file as a short header followed by its annotated diff
+func (f *diffFile) String() string {
+	var b strings.Builder
+
+	path := f.path()
+	if f.status == "renamed" {
+		path = f.oldPath + " -> " + f.newPath
+	}
+	stats := fmt.Sprintf("+%d -%d", f.added, f.deleted)
+	if f.binary {
+		stats = "binary"
+	}
+	fmt.Fprintf(&b, "File: %s (%s, %s)\n", path, f.status, stats)
+	if len(f.symbols) > 0 {
+		fmt.Fprintf(&b, "Changes in: %s\n", strings.Join(f.symbols, ", "))
+	}
+
+	for _, line := range f.header {
+		b.WriteString(line + "\n")
+	}
+	for _, hunk := range f.hunks {
+		b.WriteString(hunk.ranges)
+		if len(hunk.symbols) > 0 {
+			b.WriteString(" in " + strings.Join(hunk.symbols, ", "))
+		}
+		b.WriteString("\n")
+		for _, line := range hunk.lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+// goDecl is a top-level Go declaration and the lines it spans
+type goDecl struct {
+	name       string
+	start, end int
+}
+
+// parseGoDecls lists the top-level declarations of a Go file; nil if it doesn't parse
+func parseGoDecls(filename string, src []byte) []goDecl {
+	if src == nil {
+		return nil
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+
+	lines := func(from, to token.Pos) (int, int) {
+		return fset.Position(from).Line, fset.Position(to).Line
+	}
+
+	var decls []goDecl
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			from := d.Pos()
+			if d.Doc != nil {
+				from = d.Doc.Pos()
+			}
+			start, end := lines(from, d.End())
+			decls = append(decls, goDecl{name: funcName(d), start: start, end: end})
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				from, to := spec.Pos(), spec.End()
+				// A single spec owns the whole declaration, including its doc comment
+				if len(d.Specs) == 1 {
+					from, to = d.Pos(), d.End()
+					if d.Doc != nil {
+						from = d.Doc.Pos()
+					}
+				}
+				start, end := lines(from, to)
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					decls = append(decls, goDecl{name: "type " + s.Name.Name, start: start, end: end})
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						decls = append(decls, goDecl{name: d.Tok.String() + " " + name.Name, start: start, end: end})
+					}
+				case *ast.ImportSpec:
+					decls = append(decls, goDecl{name: "imports", start: start, end: end})
+				}
+			}
+		}
+	}
+	return decls
+}
+
+// funcName describes a function or method, e.g. "func Commit" or "method (*CommitTUI).SetOutput"
+func funcName(d *ast.FuncDecl) string {
+	if d.Recv == nil || len(d.Recv.List) == 0 {
+		return "func " + d.Name.Name
+	}
+	recv := d.Recv.List[0].Type
+	prefix := ""
+	if star, ok := recv.(*ast.StarExpr); ok {
+		prefix = "*"
+		recv = star.X
+	}
+	// Drop type parameters of generic receivers
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+	if ident, ok := recv.(*ast.Ident); ok {
+		return fmt.Sprintf("method (%s%s).%s", prefix, ident.Name, d.Name.Name)
+	}
+	return "func " + d.Name.Name
+}
+
+// readRevision returns a file from git (e.g. "HEAD:path" or ":path" for the index), or nil
+func readRevision(object string) []byte {
+	output, err := Command("show", object).Output()
+	if err != nil {
+		return nil
+	}
+	return output
+}
+
+// readWorktree returns a file from the working tree, relative to the repository root, or nil
+func readWorktree(path string) []byte {
+	root, err := RepoRoot()
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(root, path))
+	if err != nil {
+		return nil
+	}
+	return data
+}
+
+// headOrEmptyTree returns HEAD, or the empty tree before the first commit
+func headOrEmptyTree() string {
+	if err := Command("rev-parse", "--verify", "--quiet", "HEAD").Run(); err != nil {
+		return emptyTree
+	}
+	return "HEAD"
+}
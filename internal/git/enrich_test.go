@@ -0,0 +1,44 @@
+package git_test
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/git"
+	"gitr/internal/gitfixture"
+)
+
+func TestEnrichedDiffIgnoresPrefixConfig(t *testing.T) {
+	for _, config := range [][]string{
+		{"diff.noprefix", "true"},
+		{"diff.mnemonicPrefix", "true"},
+	} {
+		t.Run(config[0], func(t *testing.T) {
+			repo := gitfixture.New(t)
+			repo.Commit("initial", map[string]string{"pkg/calc.go": "package pkg\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n"})
+			repo.Git("config", config[0], config[1])
+			repo.Write("pkg/calc.go", "package pkg\n\nfunc Add(a, b int) int {\n\treturn b + a\n}\n")
+			repo.Stage()
+			repo.Enter()
+
+			changes, err := git.Changes(false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(changes) != 1 || changes[0].Path != "pkg/calc.go" {
+				t.Fatalf("changes = %+v, want pkg/calc.go", changes)
+			}
+			if len(changes[0].Symbols) == 0 || !strings.Contains(strings.Join(changes[0].Symbols, " "), "Add") {
+				t.Errorf("symbols = %v, want the enclosing function Add", changes[0].Symbols)
+			}
+
+			staged, err := git.GetStagedChanges()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(staged, "diff --git a/pkg/calc.go b/pkg/calc.go") {
+				t.Errorf("staged diff doesn't use a/ and b/ prefixes:\n%s", staged)
+			}
+		})
+	}
+}
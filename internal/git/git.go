@@ -1,21 +1,26 @@
 package git
 
 import (
+	"bytes"
 	"fmt"
-	"os/exec"
+	"io"
 	"strings"
 )
 
 // IsRepository checks if the current directory is a git repository
 func IsRepository() bool {
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd := Command("rev-parse", "--git-dir")
 	err := cmd.Run()
 	return err == nil
 }
 
+// prefixArgs pin the a/ and b/ path prefixes that diffs are parsed with,
+// whatever diff.noprefix or diff.mnemonicPrefix say
+var prefixArgs = []string{"--src-prefix=a/", "--dst-prefix=b/"}
+
 // GetStagedChanges returns the staged changes using git diff --cached
 func GetStagedChanges() (string, error) {
-	cmd := exec.Command("git", "diff", "--cached")
+	cmd := Command(append([]string{"diff", "--cached"}, prefixArgs...)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
@@ -23,15 +28,72 @@ func GetStagedChanges() (string, error) {
 	return string(output), nil
 }
 
-// Commit creates a git commit with the given message
-func Commit(message string) error {
-	// Escape the message for shell execution
-	escapedMessage := strings.ReplaceAll(message, `"`, `\"`)
-	cmd := exec.Command("git", "commit", "-m", escapedMessage)
+// Cleanup modes accepted by git commit --cleanup
+var CleanupModes = []string{"default", "strip", "whitespace", "verbatim", "scissors"}
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("git commit failed: %s", string(output))
+// CommitOptions controls how git commit is run
+type CommitOptions struct {
+	Cleanup    string // --cleanup mode; empty uses git's default
+	Signoff    bool   // add a Signed-off-by trailer
+	Sign       bool   // sign the commit (GPG or SSH, as configured in git)
+	SigningKey string // key to sign with; implies Sign
+	NoSign     bool   // don't sign, even if commit.gpgsign is set
+	NoVerify   bool   // skip the pre-commit and commit-msg hooks
+	Author     string // override the commit author ("Name <email>")
+	All        bool   // also commit unstaged changes to tracked files (git commit --all)
+
+	// Stdout and Stderr receive git's and the hooks' output as it happens
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// Commit creates a git commit with the given message. The message is passed
+// on stdin so it reaches git byte for byte.
+func Commit(message string, opts CommitOptions) error {
+	args := []string{"commit", "-F", "-"}
+	if opts.Cleanup != "" {
+		args = append(args, "--cleanup="+opts.Cleanup)
+	}
+	if opts.Signoff {
+		args = append(args, "--signoff")
+	}
+	switch {
+	case opts.NoSign:
+		args = append(args, "--no-gpg-sign")
+	case opts.SigningKey != "":
+		args = append(args, "--gpg-sign="+opts.SigningKey)
+	case opts.Sign:
+		args = append(args, "--gpg-sign")
+	}
+	if opts.NoVerify {
+		args = append(args, "--no-verify")
+	}
+	if opts.Author != "" {
+		args = append(args, "--author="+opts.Author)
+	}
+	if opts.All {
+		args = append(args, "--all")
+	}
+
+	cmd := Command(args...)
+	cmd.Stdin = strings.NewReader(message)
+	cmd.Stdout = opts.Stdout
+	cmd.Stderr = opts.Stderr
+
+	// Keep the output for the error when nobody is watching it live
+	var output bytes.Buffer
+	if cmd.Stderr == nil {
+		cmd.Stderr = &output
+	}
+	if cmd.Stdout == nil {
+		cmd.Stdout = &output
+	}
+
+	if err := cmd.Run(); err != nil {
+		if output.Len() > 0 {
+			return fmt.Errorf("git commit failed: %s", strings.TrimSpace(output.String()))
+		}
+		return fmt.Errorf("git commit failed: %v", err)
 	}
 
 	return nil
@@ -45,3 +107,35 @@ func HasStagedChanges() (bool, error) {
 	}
 	return strings.TrimSpace(changes) != "", nil
 }
+
+// ConfigValue returns the value of a git config key, or an empty string if it is unset
+func ConfigValue(key string) string {
+	cmd := Command("config", "--get", key)
+	output, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// RepoProfile returns the GitR profile pinned for the current repository
+// via `git config gitr.profile <name>`
+func RepoProfile() string {
+	return ConfigValue("gitr.profile")
+}
+
+// RepoLanguage returns the message language set for the current repository
+// via `git config gitr.language <code>`
+func RepoLanguage() string {
+	return ConfigValue("gitr.language")
+}
+
+// RepoRoot returns the absolute path of the top-level directory of the repository
+func RepoRoot() (string, error) {
+	cmd := Command("rev-parse", "--show-toplevel")
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
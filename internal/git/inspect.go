@@ -0,0 +1,222 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Revisions that the read-only inspection functions accept
+const (
+	RevisionHead  = "HEAD"
+	RevisionIndex = "index"
+	// RevisionWorktree is only accepted by ReadDirectory
+	RevisionWorktree = "worktree"
+)
+
+// CleanPath checks that path stays inside the repository and returns it
+// relative to the root, "" for the root itself
+func CleanPath(p string) (string, error) {
+	p = strings.TrimSpace(strings.ReplaceAll(p, "\\", "/"))
+	if strings.HasPrefix(p, "/") {
+		return "", fmt.Errorf("path %q must be relative to the repository root", p)
+	}
+	cleaned := path.Clean("/" + p)[1:]
+	for _, part := range strings.Split(p, "/") {
+		if part == ".." {
+			return "", fmt.Errorf("path %q leaves the repository", p)
+		}
+	}
+	// Case-insensitive file systems open .GIT as .git
+	if first, _, _ := strings.Cut(cleaned, "/"); strings.EqualFold(first, ".git") {
+		return "", fmt.Errorf("path %q is inside .git", p)
+	}
+	return cleaned, nil
+}
+
+// ShowFile returns a file as it is at HEAD or in the index
+func ShowFile(ctx context.Context, revision, file string) (string, error) {
+	object, err := objectName(revision, file)
+	if err != nil {
+		return "", err
+	}
+	return runInRootContext(ctx, "show", object)
+}
+
+// ListDirectory lists the entries of a directory at HEAD or in the index;
+// subdirectories end in a slash
+func ListDirectory(ctx context.Context, revision, dir string) ([]string, error) {
+	var args []string
+	switch revision {
+	case RevisionHead, "":
+		args = []string{"ls-tree", "-r", "--name-only", "HEAD"}
+	case RevisionIndex:
+		args = []string{"ls-files", "--cached"}
+	default:
+		return nil, fmt.Errorf("unknown revision %q (expected %s or %s)", revision, RevisionHead, RevisionIndex)
+	}
+	if dir != "" {
+		args = append(args, "--", dir)
+	}
+	output, err := runInRootContext(ctx, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Collapse the recursive listing to the direct children of dir
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+	}
+	seen := map[string]bool{}
+	var entries []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		rest, ok := strings.CutPrefix(line, prefix)
+		if !ok || rest == "" {
+			continue
+		}
+		if name, _, isDir := strings.Cut(rest, "/"); isDir {
+			rest = name + "/"
+		}
+		if !seen[rest] {
+			seen[rest] = true
+			entries = append(entries, rest)
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("directory %q not found", dir)
+	}
+	return entries, nil
+}
+
+// ReadDirectory returns the contents of the tracked files directly inside dir
+// at HEAD, in the index or in the working tree, keyed by path. A directory
+// that doesn't exist at that revision has no files.
+func ReadDirectory(revision, dir string, keep func(name string) bool) (map[string]string, error) {
+	var args []string
+	switch revision {
+	case RevisionHead, "":
+		args = []string{"ls-tree", "--name-only", headOrEmptyTree()}
+	case RevisionIndex, RevisionWorktree:
+		args = []string{"ls-files", "--cached"}
+	default:
+		return nil, fmt.Errorf("unknown revision %q", revision)
+	}
+	prefix := ""
+	if dir != "" {
+		prefix = dir + "/"
+		args = append(args, "--", prefix)
+	}
+	output, err := runInRoot(args...)
+	if err != nil {
+		return nil, err
+	}
+	root, err := RepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string]string{}
+	for _, file := range strings.Split(strings.TrimSpace(output), "\n") {
+		name, ok := strings.CutPrefix(file, prefix)
+		if !ok || name == "" || strings.Contains(name, "/") || !keep(name) {
+			continue
+		}
+		var content string
+		if revision == RevisionWorktree {
+			data, err := os.ReadFile(filepath.Join(root, file))
+			if errors.Is(err, fs.ErrNotExist) {
+				// Deleted but not staged
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			content = string(data)
+		} else {
+			object, _ := objectName(revision, file)
+			if content, err = runInRoot("show", object); err != nil {
+				return nil, err
+			}
+		}
+		files[file] = content
+	}
+	return files, nil
+}
+
+// Log returns the one-line history of a path, newest first
+func Log(ctx context.Context, file string, count int) (string, error) {
+	args := []string{"log", "--no-color", "--format=%h %ad %s", "--date=short", "-n", strconv.Itoa(count)}
+	if file != "" {
+		args = append(args, "--", file)
+	}
+	return runInRootContext(ctx, args...)
+}
+
+// Grep searches the tracked files in the index for a regular expression and
+// returns up to maxLines matching lines as path:line:text
+func Grep(ctx context.Context, pattern, dir string, maxLines int) (string, error) {
+	args := []string{"grep", "--cached", "-n", "-I", "-E", "--no-color", "-m", "5", "-e", pattern}
+	if dir != "" {
+		args = append(args, "--", dir)
+	}
+	output, err := runInRootContext(ctx, args...)
+	if err != nil {
+		// git grep exits with 1 when nothing matches
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
+		return "", err
+	}
+
+	lines := strings.Split(strings.TrimRight(output, "\n"), "\n")
+	if len(lines) > maxLines {
+		lines = append(lines[:maxLines], fmt.Sprintf("... %d more matches", len(lines)-maxLines))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// objectName returns the git object name of a path at HEAD or in the index
+func objectName(revision, file string) (string, error) {
+	switch revision {
+	case RevisionHead, "":
+		return "HEAD:" + file, nil
+	case RevisionIndex:
+		return ":" + file, nil
+	}
+	return "", fmt.Errorf("unknown revision %q (expected %s or %s)", revision, RevisionHead, RevisionIndex)
+}
+
+// runInRoot runs a git command in the repository root and returns its output
+func runInRoot(args ...string) (string, error) {
+	return runInRootContext(context.Background(), args...)
+}
+
+// runInRootContext is runInRoot for a command that's stopped when ctx is
+// done; it then returns the context's error
+func runInRootContext(ctx context.Context, args ...string) (string, error) {
+	root, err := RepoRoot()
+	if err != nil {
+		return "", err
+	}
+	cmd := CommandContext(ctx, args...)
+	cmd.Dir = root
+	output, err := cmd.Output()
+	if ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,35 @@
+package git
+
+import "testing"
+
+func TestCleanPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"", "", false},
+		{".", "", false},
+		{"cmd/root.go", "cmd/root.go", false},
+		{"./cmd//root.go", "cmd/root.go", false},
+		{" internal/git/ ", "internal/git", false},
+		{`internal\git\git.go`, "internal/git/git.go", false},
+		{".github/workflows/ci.yml", ".github/workflows/ci.yml", false},
+		{"../x", "", true},
+		{"a/../../x", "", true},
+		{"a/../x", "", true},
+		{`a\..\..\x`, "", true},
+		{"/etc/passwd", "", true},
+		{`\etc\passwd`, "", true},
+		{".git", "", true},
+		{".git/config", "", true},
+		{"./.git/config", "", true},
+		{".GIT/config", "", true},
+	}
+	for _, tt := range tests {
+		got, err := CleanPath(tt.path)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("CleanPath(%q) = %q, %v; want %q, error %v", tt.path, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
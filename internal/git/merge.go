@@ -0,0 +1,93 @@
+package git
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// MergeState describes a merge that is waiting to be committed
+type MergeState struct {
+	Heads     []string // the commits being merged, from MERGE_HEAD
+	Message   string   // the message git proposes in MERGE_MSG, without comments
+	Conflicts []string // files that had conflicts, as listed in MERGE_MSG
+	Unmerged  []string // files whose conflicts aren't resolved yet
+}
+
+// Merging returns the merge in progress, or nil when there is none
+func Merging() (*MergeState, error) {
+	heads, err := readGitFile("MERGE_HEAD")
+	if err != nil || heads == "" {
+		return nil, err
+	}
+	state := &MergeState{Heads: strings.Fields(heads)}
+
+	message, err := readGitFile("MERGE_MSG")
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	inConflicts := false
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+			continue
+		}
+		// git lists the conflicts as "# Conflicts:" followed by "#\tpath" lines
+		comment := strings.TrimPrefix(line, "#")
+		switch {
+		case strings.TrimSpace(comment) == "Conflicts:":
+			inConflicts = true
+		case inConflicts && strings.HasPrefix(comment, "\t"):
+			state.Conflicts = append(state.Conflicts, strings.TrimSpace(comment))
+		default:
+			inConflicts = false
+		}
+	}
+	state.Message = strings.TrimSpace(strings.Join(lines, "\n"))
+
+	unmerged, err := runInRoot("diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range strings.Split(unmerged, "\n") {
+		if path != "" {
+			state.Unmerged = append(state.Unmerged, path)
+		}
+	}
+	return state, nil
+}
+
+// readGitFile returns a file from the .git directory, or "" if it doesn't exist
+func readGitFile(name string) (string, error) {
+	path, err := runInRoot("rev-parse", "--path-format=absolute", "--git-path", name)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(strings.TrimSpace(path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", nil
+	}
+	return strings.TrimSpace(string(data)), err
+}
+
+// MergeBase returns the best common ancestor of two revisions
+func MergeBase(a, b string) (string, error) {
+	output, err := runInRoot("merge-base", a, b)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// ResetSoft moves the current branch to rev and keeps the changes since then staged
+func ResetSoft(rev string) error {
+	_, err := runInRoot("reset", "--soft", rev)
+	return err
+}
+
+// HeadCommit returns the full hash of HEAD
+func HeadCommit() (string, error) {
+	return ResolveCommit("HEAD")
+}
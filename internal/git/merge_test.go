@@ -0,0 +1,58 @@
+package git_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gitr/internal/git"
+	"gitr/internal/gitfixture"
+)
+
+func TestMerging(t *testing.T) {
+	repo := gitfixture.New(t)
+	repo.Commit("initial", map[string]string{"a.txt": "a\n", "b.txt": "b\n"})
+	main := repo.Git("rev-parse", "--abbrev-ref", "HEAD")
+	repo.Git("checkout", "--quiet", "-b", "topic")
+	topic := repo.Commit("topic changes", map[string]string{"a.txt": "topic\n", "b.txt": "topic\n"})
+	repo.Git("checkout", "--quiet", main)
+	repo.Commit("main changes", map[string]string{"a.txt": "main\n", "b.txt": "main\n"})
+	repo.Enter()
+
+	if state, err := git.Merging(); err != nil || state != nil {
+		t.Fatalf("Merging = %+v, %v; want no merge in progress", state, err)
+	}
+
+	merge := exec.Command("git", "merge", "--no-edit", "topic")
+	merge.Dir = repo.Dir
+	merge.Env = append(os.Environ(), repo.Env()...)
+	if err := merge.Run(); err == nil {
+		t.Fatal("merge succeeded, want conflicts")
+	}
+	// b.txt is resolved, a.txt isn't
+	repo.Write("b.txt", "both\n")
+	repo.Stage("b.txt")
+	mergeMsg := "Merge branch 'topic'\n\n# Conflicts:\n#\ta.txt\n#\tb.txt\n#\n# It looks like you may be committing a merge.\n#\tnot/a/conflict\n"
+	if err := os.WriteFile(filepath.Join(repo.Dir, ".git", "MERGE_MSG"), []byte(mergeMsg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err := git.Merging()
+	if err != nil || state == nil {
+		t.Fatalf("Merging = %+v, %v; want the merge in progress", state, err)
+	}
+	if strings.Join(state.Heads, " ") != topic {
+		t.Errorf("heads = %v, want %s", state.Heads, topic)
+	}
+	if state.Message != "Merge branch 'topic'" {
+		t.Errorf("message = %q, want it without the comments", state.Message)
+	}
+	if strings.Join(state.Conflicts, " ") != "a.txt b.txt" {
+		t.Errorf("conflicts = %q, want a.txt and b.txt", state.Conflicts)
+	}
+	if strings.Join(state.Unmerged, " ") != "a.txt" {
+		t.Errorf("unmerged = %q, want a.txt", state.Unmerged)
+	}
+}
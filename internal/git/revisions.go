@@ -0,0 +1,104 @@
+package git
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Commits returns the hashes of up to count commits reachable from rev,
+// newest first. Merge commits are skipped; count 0 means no limit.
+func Commits(rev string, count int) ([]string, error) {
+	args := []string{"rev-list", "--no-merges"}
+	if count > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(count))
+	}
+	output, err := runInRoot(append(args, rev, "--")...)
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
+// CommitMessage returns the full message of a commit
+func CommitMessage(rev string) (string, error) {
+	output, err := runInRoot("log", "-1", "--format=%B", rev, "--")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// CommitDiff returns the changes a commit made to its first parent, or to
+// the empty tree for a root commit
+func CommitDiff(rev string) (string, error) {
+	args := append([]string{"show", "--format=", "--patch", "--no-color", "--no-ext-diff", "--first-parent"}, prefixArgs...)
+	return runInRoot(append(args, rev, "--")...)
+}
+
+// ResolveRange returns the two commits whose diff shows what a revision or
+// range changed: a commit is compared with its first parent, or the empty
+// tree for a root commit; "a..b" compares a with b and "a...b" their merge
+// base with b. A missing end of a range is HEAD.
+func ResolveRange(spec string) (from, to string, err error) {
+	if strings.HasPrefix(spec, "-") {
+		return "", "", fmt.Errorf("invalid revision %q", spec)
+	}
+	if a, b, ok := strings.Cut(spec, "..."); ok {
+		if to, err = ResolveCommit(orHead(b)); err != nil {
+			return "", "", err
+		}
+		base, err := MergeBase(orHead(a), to)
+		if err != nil {
+			return "", "", fmt.Errorf("no merge base of %s and %s", orHead(a), orHead(b))
+		}
+		return base, to, nil
+	}
+	if a, b, ok := strings.Cut(spec, ".."); ok {
+		if from, err = ResolveCommit(orHead(a)); err != nil {
+			return "", "", err
+		}
+		if to, err = ResolveCommit(orHead(b)); err != nil {
+			return "", "", err
+		}
+		return from, to, nil
+	}
+
+	if to, err = ResolveCommit(spec); err != nil {
+		return "", "", err
+	}
+	if from, err = ResolveCommit(to + "^1"); err != nil {
+		return emptyTree, to, nil
+	}
+	return from, to, nil
+}
+
+// IsRange reports whether a revision spec names a range rather than one commit
+func IsRange(spec string) bool {
+	return strings.Contains(spec, "..")
+}
+
+// ResolveCommit returns the full hash of the commit a revision names
+func ResolveCommit(rev string) (string, error) {
+	output, err := runInRoot("rev-parse", "--verify", "--quiet", rev+"^{commit}")
+	if err != nil || strings.TrimSpace(output) == "" {
+		return "", fmt.Errorf("unknown revision %q", rev)
+	}
+	return strings.TrimSpace(output), nil
+}
+
+func orHead(rev string) string {
+	if rev == "" {
+		return "HEAD"
+	}
+	return rev
+}
+
+// CountCommits returns the number of non-merge commits in a range
+func CountCommits(revisions string) (int, error) {
+	output, err := runInRoot("rev-list", "--no-merges", "--count", revisions, "--")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(output))
+}
@@ -0,0 +1,54 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"time"
+)
+
+// Runner creates the git commands GitR runs. Replace it with SetRunner to
+// run git against a fixture repository or to fake its output.
+type Runner interface {
+	// Command returns an unstarted git command with the given arguments
+	Command(args ...string) *exec.Cmd
+}
+
+// ExecRunner runs the git binary on the PATH in the current directory
+type ExecRunner struct {
+	// Env is added to the environment of every command, e.g. GIT_CONFIG_GLOBAL=/dev/null
+	Env []string
+}
+
+// Command returns a git command with the given arguments
+func (r ExecRunner) Command(args ...string) *exec.Cmd {
+	cmd := exec.Command("git", args...)
+	if len(r.Env) > 0 {
+		cmd.Env = append(os.Environ(), r.Env...)
+	}
+	return cmd
+}
+
+var runner Runner = ExecRunner{}
+
+// SetRunner replaces the runner used for all git commands and returns the previous one
+func SetRunner(r Runner) Runner {
+	previous := runner
+	runner = r
+	return previous
+}
+
+// Command returns a git command created by the current runner
+func Command(args ...string) *exec.Cmd {
+	return runner.Command(args...)
+}
+
+// CommandContext is Command for a git command that's killed when ctx is done
+func CommandContext(ctx context.Context, args ...string) *exec.Cmd {
+	cmd := Command(args...)
+	bound := exec.CommandContext(ctx, cmd.Path, cmd.Args[1:]...)
+	bound.Env, bound.Dir = cmd.Env, cmd.Dir
+	// Don't wait for children of git that still hold the output open
+	bound.WaitDelay = time.Second
+	return bound
+}
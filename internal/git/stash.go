@@ -0,0 +1,63 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Stash is an entry of git stash list
+type Stash struct {
+	Ref     string `json:"ref"`     // e.g. stash@{0}
+	Hash    string `json:"hash"`    // the stash commit
+	Subject string `json:"subject"` // e.g. "On main: message" or "WIP on main: <commit>"
+}
+
+// Unnamed reports whether the stash was saved without a message, so git
+// named it after the commit it was based on
+func (s Stash) Unnamed() bool {
+	return strings.HasPrefix(s.Subject, "WIP on ")
+}
+
+// StashOptions controls how git stash push is run
+type StashOptions struct {
+	IncludeUntracked bool // also stash untracked files
+	KeepIndex        bool // leave the staged changes in place
+}
+
+// Stashes lists the stash entries, newest first
+func Stashes() ([]Stash, error) {
+	output, err := runInRoot("stash", "list", "--format=%gd%x00%H%x00%gs")
+	if err != nil {
+		return nil, err
+	}
+	var stashes []Stash
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.SplitN(line, "\x00", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		stashes = append(stashes, Stash{Ref: fields[0], Hash: fields[1], Subject: fields[2]})
+	}
+	return stashes, nil
+}
+
+// StashDiff returns the changes a stash holds to tracked files, compared
+// with the commit it was based on
+func StashDiff(stash Stash) (string, error) {
+	return EnrichedDiff(EnrichOptions{From: stash.Hash + "^1", To: stash.Hash})
+}
+
+// StashPush stashes the local changes under the given message
+func StashPush(message string, opts StashOptions) error {
+	args := []string{"stash", "push", "--message", message}
+	if opts.IncludeUntracked {
+		args = append(args, "--include-untracked")
+	}
+	if opts.KeepIndex {
+		args = append(args, "--keep-index")
+	}
+	if _, err := runInRoot(args...); err != nil {
+		return fmt.Errorf("git stash failed: %v", err)
+	}
+	return nil
+}
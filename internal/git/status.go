@@ -0,0 +1,203 @@
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FileStatus is one changed path from git status
+type FileStatus struct {
+	Path      string
+	OrigPath  string // source of a rename or copy
+	Index     byte   // status in the index: M, A, D, R, C, T or '.' when unchanged
+	Worktree  byte   // status in the worktree, same letters as Index
+	Untracked bool
+	Unmerged  bool
+
+	// Line counts of the unstaged changes; untracked files count all lines
+	Added   int
+	Deleted int
+	Binary  bool
+}
+
+// HasUnstaged reports whether the path has changes that aren't staged yet
+func (f FileStatus) HasUnstaged() bool {
+	return f.Untracked || f.Unmerged || f.Worktree != '.'
+}
+
+// HasStaged reports whether the path has staged changes
+func (f FileStatus) HasStaged() bool {
+	return !f.Untracked && !f.Unmerged && f.Index != '.'
+}
+
+// Kind describes the unstaged change, e.g. "modified" or "untracked"
+func (f FileStatus) Kind() string {
+	switch {
+	case f.Untracked:
+		return "untracked"
+	case f.Unmerged:
+		return "conflict"
+	}
+	switch f.Worktree {
+	case 'M':
+		return "modified"
+	case 'D':
+		return "deleted"
+	case 'T':
+		return "typechange"
+	case 'A':
+		return "added"
+	}
+	return "staged"
+}
+
+// Status lists the changed paths using git status --porcelain=v2, with line
+// counts for the unstaged changes. Untracked files are listed individually.
+func Status() ([]FileStatus, error) {
+	cmd := Command("status", "--porcelain=v2", "-z", "--untracked-files=all")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %v", err)
+	}
+
+	files, err := parseStatus(output)
+	if err != nil {
+		return nil, err
+	}
+	if err := addDiffStats(files); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// parseStatus parses the NUL-separated output of git status --porcelain=v2 -z
+func parseStatus(output []byte) ([]FileStatus, error) {
+	var files []FileStatus
+	entries := strings.Split(strings.TrimSuffix(string(output), "\x00"), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if entry == "" {
+			continue
+		}
+
+		switch entry[0] {
+		case '1':
+			// 1 XY sub mH mI mW hH hI path
+			fields := strings.SplitN(entry, " ", 9)
+			if len(fields) != 9 {
+				return nil, fmt.Errorf("unexpected git status entry %q", entry)
+			}
+			files = append(files, FileStatus{Path: fields[8], Index: fields[1][0], Worktree: fields[1][1]})
+		case '2':
+			// 2 XY sub mH mI mW hH hI Xscore path, followed by the original path
+			fields := strings.SplitN(entry, " ", 10)
+			if len(fields) != 10 || i+1 >= len(entries) {
+				return nil, fmt.Errorf("unexpected git status entry %q", entry)
+			}
+			i++
+			files = append(files, FileStatus{Path: fields[9], OrigPath: entries[i], Index: fields[1][0], Worktree: fields[1][1]})
+		case 'u':
+			// u XY sub m1 m2 m3 mW h1 h2 h3 path
+			fields := strings.SplitN(entry, " ", 11)
+			if len(fields) != 11 {
+				return nil, fmt.Errorf("unexpected git status entry %q", entry)
+			}
+			files = append(files, FileStatus{Path: fields[10], Index: fields[1][0], Worktree: fields[1][1], Unmerged: true})
+		case '?':
+			files = append(files, FileStatus{Path: entry[2:], Index: '.', Worktree: '?', Untracked: true})
+		}
+	}
+	return files, nil
+}
+
+// addDiffStats fills in the line counts of the unstaged changes
+func addDiffStats(files []FileStatus) error {
+	cmd := Command("diff", "--numstat", "-z", "--no-renames")
+	output, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("git diff failed: %v", err)
+	}
+
+	type stat struct {
+		added, deleted int
+		binary         bool
+	}
+	stats := map[string]stat{}
+	for _, entry := range strings.Split(string(output), "\x00") {
+		fields := strings.SplitN(entry, "\t", 3)
+		if len(fields) != 3 {
+			continue
+		}
+		added, errAdded := strconv.Atoi(fields[0])
+		deleted, errDeleted := strconv.Atoi(fields[1])
+		stats[fields[2]] = stat{added, deleted, errAdded != nil || errDeleted != nil}
+	}
+
+	for i := range files {
+		if files[i].Untracked {
+			files[i].Added, files[i].Binary = countLines(files[i].Path)
+			continue
+		}
+		if s, ok := stats[files[i].Path]; ok {
+			files[i].Added, files[i].Deleted, files[i].Binary = s.added, s.deleted, s.binary
+		}
+	}
+	return nil
+}
+
+// countLines counts the lines of an untracked file, relative to the repository root
+func countLines(path string) (int, bool) {
+	root, err := RepoRoot()
+	if err != nil {
+		return 0, false
+	}
+	data, err := os.ReadFile(filepath.Join(root, path))
+	if err != nil {
+		return 0, false
+	}
+	if bytes.IndexByte(data, 0) >= 0 {
+		return 0, true
+	}
+	lines := bytes.Count(data, []byte("\n"))
+	if len(data) > 0 && data[len(data)-1] != '\n' {
+		lines++
+	}
+	return lines, false
+}
+
+// Stage adds the given paths to the index, including deletions
+func Stage(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	// Paths from git status are relative to the repository root
+	root, err := RepoRoot()
+	if err != nil {
+		return err
+	}
+	cmd := Command(append([]string{"add", "-A", "--"}, paths...)...)
+	cmd.Dir = root
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git add failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// emptyTree is the hash of git's empty tree, used as the base before the first commit
+const emptyTree = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+
+// GetAllChanges returns the staged and unstaged changes to tracked files,
+// which is what git commit --all would commit
+func GetAllChanges() (string, error) {
+	cmd := Command(append([]string{"diff", headOrEmptyTree()}, prefixArgs...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(output), nil
+}
@@ -0,0 +1,68 @@
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseStatus(t *testing.T) {
+	hash := strings.Repeat("a", 40)
+	entries := []string{
+		"1 M. N... 100644 100644 100644 " + hash + " " + hash + " staged.go",
+		"1 .M N... 100644 100644 100644 " + hash + " " + hash + " dir/with space.go",
+		"1 AD N... 000000 100644 000000 " + hash + " " + hash + " added-then-deleted.go",
+		"2 R. N... 100644 100644 100644 " + hash + " " + hash + " R100 new name.go", "old name.go",
+		"u UU N... 100644 100644 100644 100644 " + hash + " " + hash + " " + hash + " conflict.go",
+		"? untracked/file.txt",
+		"! ignored.log",
+	}
+	output := []byte(strings.Join(entries, "\x00") + "\x00")
+
+	files, err := parseStatus(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []FileStatus{
+		{Path: "staged.go", Index: 'M', Worktree: '.'},
+		{Path: "dir/with space.go", Index: '.', Worktree: 'M'},
+		{Path: "added-then-deleted.go", Index: 'A', Worktree: 'D'},
+		{Path: "new name.go", OrigPath: "old name.go", Index: 'R', Worktree: '.'},
+		{Path: "conflict.go", Index: 'U', Worktree: 'U', Unmerged: true},
+		{Path: "untracked/file.txt", Index: '.', Worktree: '?', Untracked: true},
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("parseStatus =\n%+v\nwant\n%+v", files, want)
+	}
+
+	kinds := []string{"staged", "modified", "deleted", "staged", "conflict", "untracked"}
+	for i, f := range files {
+		if got := f.Kind(); got != kinds[i] {
+			t.Errorf("%s: Kind = %q, want %q", f.Path, got, kinds[i])
+		}
+	}
+	if !files[0].HasStaged() || files[0].HasUnstaged() {
+		t.Error("staged.go should only have staged changes")
+	}
+	if files[1].HasStaged() || !files[1].HasUnstaged() {
+		t.Error("dir/with space.go should only have unstaged changes")
+	}
+	if files[4].HasStaged() || files[5].HasStaged() {
+		t.Error("conflicts and untracked files have nothing staged")
+	}
+}
+
+func TestParseStatusMalformed(t *testing.T) {
+	for _, output := range []string{
+		"1 M. N... 100644 staged.go\x00",
+		"2 R. N... 100644 100644 100644 h h R100 renamed.go\x00",
+		"u UU N... conflict.go\x00",
+	} {
+		if _, err := parseStatus([]byte(output)); err == nil {
+			t.Errorf("parseStatus(%q) accepted a malformed entry", output)
+		}
+	}
+	if files, err := parseStatus(nil); err != nil || len(files) != 0 {
+		t.Errorf("parseStatus(nil) = %v, %v", files, err)
+	}
+}
@@ -0,0 +1,87 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SummarizeDiff shortens a diff until fits accepts it. Hunk bodies are cut
+// to fewer and fewer lines first; if that isn't enough only the file
+// headers remain, and finally the list of files is cut off.
+func SummarizeDiff(diff string, fits func(string) bool) string {
+	for _, keep := range []int{20, 8, 3, 0} {
+		if summary := shortenHunks(diff, keep); fits(summary) {
+			return summary
+		}
+	}
+
+	files := fileHeaders(diff)
+	for n := len(files); n > 0; n-- {
+		summary := strings.Join(files[:n], "\n")
+		if n < len(files) {
+			summary += fmt.Sprintf("\n... and %d more files", len(files)-n)
+		}
+		if fits(summary) {
+			return summary
+		}
+	}
+	return fmt.Sprintf("%d files changed", len(files))
+}
+
+// isFileStart reports whether a line starts a file, in plain or enriched diffs
+func isFileStart(line string) bool {
+	return strings.HasPrefix(line, "diff --git ") || strings.HasPrefix(line, "File: ")
+}
+
+// shortenHunks keeps the first keep lines of every hunk body
+func shortenHunks(diff string, keep int) string {
+	var out []string
+	inHunk := false
+	kept, dropped := 0, 0
+
+	flush := func() {
+		if dropped > 0 {
+			out = append(out, fmt.Sprintf("... (%d more lines)", dropped))
+		}
+		kept, dropped = 0, 0
+	}
+
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case isFileStart(line):
+			flush()
+			inHunk = false
+		case strings.HasPrefix(line, "@@"):
+			flush()
+			inHunk = true
+		case inHunk:
+			if kept < keep {
+				kept++
+				out = append(out, line)
+			} else {
+				dropped++
+			}
+			continue
+		}
+		out = append(out, line)
+	}
+	flush()
+	return strings.Join(out, "\n")
+}
+
+// fileHeaders returns one summary line per file of a diff
+func fileHeaders(diff string) []string {
+	var files []string
+	enriched := strings.HasPrefix(diff, "File: ")
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case enriched && strings.HasPrefix(line, "File: "):
+			files = append(files, line)
+		case enriched && strings.HasPrefix(line, "Changes in: ") && len(files) > 0:
+			files[len(files)-1] += "; " + line
+		case !enriched && strings.HasPrefix(line, "diff --git "):
+			files = append(files, "File: "+diffGitPath(line))
+		}
+	}
+	return files
+}
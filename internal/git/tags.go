@@ -0,0 +1,73 @@
+package git
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TagOptions controls how git tag is run
+type TagOptions struct {
+	Sign       bool   // sign the tag (GPG or SSH, as configured in git)
+	SigningKey string // key to sign with; implies Sign
+	NoSign     bool   // don't sign, even if tag.gpgSign is set
+	Force      bool   // replace an existing tag of the same name
+}
+
+// PreviousTag returns the newest tag reachable from rev other than exclude,
+// or "" if there is none
+func PreviousTag(rev, exclude string) (string, error) {
+	if _, err := ResolveCommit(rev); err != nil {
+		return "", err
+	}
+	output, err := runInRoot("describe", "--tags", "--abbrev=0", "--exclude="+exclude, rev)
+	if err != nil {
+		// describe fails when no tag can describe rev
+		return "", nil
+	}
+	return strings.TrimSpace(output), nil
+}
+
+// TagExists reports whether a tag of the given name exists
+func TagExists(name string) bool {
+	_, err := runInRoot("rev-parse", "--verify", "--quiet", "refs/tags/"+name)
+	return err == nil
+}
+
+// CheckTagName returns an error if name isn't a valid tag name
+func CheckTagName(name string) error {
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid tag name %q", name)
+	}
+	if _, err := runInRoot("check-ref-format", "refs/tags/"+name); err != nil {
+		return fmt.Errorf("invalid tag name %q", name)
+	}
+	return nil
+}
+
+// CreateTag creates an annotated tag on rev with the given message. The
+// message is passed on stdin so it reaches git byte for byte.
+func CreateTag(name, rev, message string, opts TagOptions) error {
+	args := []string{"tag", "-a", "-F", "-", "--cleanup=whitespace"}
+	switch {
+	case opts.NoSign:
+		args = append(args, "--no-sign")
+	case opts.SigningKey != "":
+		args = append(args, "--local-user="+opts.SigningKey)
+	case opts.Sign:
+		args = append(args, "--sign")
+	}
+	if opts.Force {
+		args = append(args, "--force")
+	}
+	args = append(args, name, rev)
+
+	cmd := Command(args...)
+	cmd.Stdin = strings.NewReader(message)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if len(output) > 0 {
+			return fmt.Errorf("git tag failed: %s", strings.TrimSpace(string(output)))
+		}
+		return fmt.Errorf("git tag failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package git_test
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/git"
+	"gitr/internal/gitfixture"
+)
+
+func TestCreateTag(t *testing.T) {
+	repo := gitfixture.New(t)
+	first := repo.Commit("feat: add a", map[string]string{"a.txt": "a\n"})
+	repo.Commit("fix: fix a", map[string]string{"a.txt": "b\n"})
+	repo.Enter()
+
+	if err := git.CreateTag("v1.0.0", first, "Release v1.0.0\n\nFeatures:\n- add a\n", git.TagOptions{NoSign: true}); err != nil {
+		t.Fatal(err)
+	}
+	if kind := strings.TrimSpace(repo.Git("cat-file", "-t", "v1.0.0")); kind != "tag" {
+		t.Errorf("v1.0.0 is a %s object, want an annotated tag", kind)
+	}
+	if target := strings.TrimSpace(repo.Git("rev-parse", "v1.0.0^{commit}")); target != first {
+		t.Errorf("v1.0.0 points at %s, want %s", target, first)
+	}
+	if message := strings.TrimSpace(repo.Git("tag", "-l", "--format=%(contents)", "v1.0.0")); message != "Release v1.0.0\n\nFeatures:\n- add a" {
+		t.Errorf("tag message = %q", message)
+	}
+
+	if err := git.CreateTag("v1.0.0", "HEAD", "Release again", git.TagOptions{NoSign: true}); err == nil {
+		t.Error("an existing tag was replaced without Force")
+	}
+	if err := git.CreateTag("v1.0.0", "HEAD", "Release again", git.TagOptions{NoSign: true, Force: true}); err != nil {
+		t.Fatal(err)
+	}
+	if previous, err := git.PreviousTag("HEAD", "v2.0.0"); err != nil || previous != "v1.0.0" {
+		t.Errorf("PreviousTag = %q, %v; want v1.0.0", previous, err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package gitfixture creates throwaway git repositories with an isolated git
+// configuration, for running GitR against known history.
+package gitfixture
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// TB is the part of testing.TB the fixtures need
+type TB interface {
+	Helper()
+	Fatalf(format string, args ...any)
+	TempDir() string
+	Cleanup(func())
+	Setenv(key, value string)
+}
+
+// Repo is a temporary repository
+type Repo struct {
+	Dir string
+	t   TB
+	env []string
+}
+
+// New creates an empty repository on branch main. Global and system git
+// configuration are ignored, and author and committer are fixed, so commit
+// hashes only depend on the content and dates.
+func New(t TB) *Repo {
+	t.Helper()
+	dir := t.TempDir()
+	// Resolve symlinks so paths match what git reports, e.g. /private/var on macOS
+	if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+		dir = resolved
+	}
+	r := &Repo{
+		Dir: dir,
+		t:   t,
+		env: []string{
+			"GIT_CONFIG_GLOBAL=" + os.DevNull,
+			"GIT_CONFIG_NOSYSTEM=1",
+			"GIT_AUTHOR_NAME=Test Author",
+			"GIT_AUTHOR_EMAIL=author@example.com",
+			"GIT_AUTHOR_DATE=2024-01-01T00:00:00Z",
+			"GIT_COMMITTER_NAME=Test Committer",
+			"GIT_COMMITTER_EMAIL=committer@example.com",
+			"GIT_COMMITTER_DATE=2024-01-01T00:00:00Z",
+		},
+	}
+	r.Git("init", "--quiet", "--initial-branch=main")
+	r.Git("config", "commit.gpgsign", "false")
+	return r
+}
+
+// Env returns the environment additions that isolate git in this repository
+func (r *Repo) Env() []string {
+	return append([]string(nil), r.env...)
+}
+
+// Runner returns a runner for GitR's git commands with the isolated configuration
+func (r *Repo) Runner() git.Runner {
+	return git.ExecRunner{Env: r.Env()}
+}
+
+// Enter makes the repository the working directory and GitR's git runner
+// until the test ends. HOME and the XDG directories point to a temporary
+// directory, so the user's configuration, cache, history and usage files are
+// left alone. Tests that use it can't run in parallel.
+func (r *Repo) Enter() {
+	r.t.Helper()
+	home := r.t.TempDir()
+	r.t.Setenv("HOME", home)
+	for _, dir := range []string{"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME"} {
+		r.t.Setenv(dir, filepath.Join(home, strings.ToLower(strings.TrimPrefix(dir, "XDG_"))))
+	}
+
+	previousDir, err := os.Getwd()
+	if err != nil {
+		r.t.Fatalf("getwd: %v", err)
+	}
+	if err := os.Chdir(r.Dir); err != nil {
+		r.t.Fatalf("chdir: %v", err)
+	}
+	previousRunner := git.SetRunner(r.Runner())
+	r.t.Cleanup(func() {
+		git.SetRunner(previousRunner)
+		os.Chdir(previousDir)
+	})
+}
+
+// Git runs git in the repository and returns its trimmed output
+func (r *Repo) Git(args ...string) string {
+	r.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.Dir
+	cmd.Env = append(os.Environ(), r.env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		r.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// Write creates or replaces a file, creating its directories
+func (r *Repo) Write(path, content string) {
+	r.t.Helper()
+	full := filepath.Join(r.Dir, filepath.FromSlash(path))
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		r.t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		r.t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// Remove deletes a file from the worktree
+func (r *Repo) Remove(path string) {
+	r.t.Helper()
+	if err := os.Remove(filepath.Join(r.Dir, filepath.FromSlash(path))); err != nil {
+		r.t.Fatalf("remove %s: %v", path, err)
+	}
+}
+
+// Stage stages the given paths, or everything when none are given
+func (r *Repo) Stage(paths ...string) {
+	r.t.Helper()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	r.Git(append([]string{"add", "-A", "--"}, paths...)...)
+}
+
+// Commit writes the files, stages everything and commits with the message.
+// It returns the hash of the new commit.
+func (r *Repo) Commit(message string, files map[string]string) string {
+	r.t.Helper()
+	for path, content := range files {
+		r.Write(path, content)
+	}
+	r.Stage()
+	r.Git("commit", "--quiet", "--allow-empty", "-m", message)
+	return r.Git("rev-parse", "HEAD")
+}
+
+// HeadMessage returns the full message of the latest commit
+func (r *Repo) HeadMessage() string {
+	r.t.Helper()
+	return r.Git("log", "-1", "--format=%B")
+}
+
+// WriteConfig writes a GitR configuration to .gitr_config in the repository
+func (r *Repo) WriteConfig(xml string) {
+	r.t.Helper()
+	r.Write(".gitr_config", xml)
+}
+
+// ProviderConfig returns a minimal GitR configuration for an OpenAI-compatible
+// server at baseURL, e.g. a fakeopenai.Server
+func ProviderConfig(baseURL, model string) string {
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<config>
+ <openai>
+  <provider>local</provider>
+  <base_url>` + baseURL + `</base_url>
+  <api_key>test</api_key>
+  <model>` + model + `</model>
+ </openai>
+ <commit_template>
+  <style>conventional</style>
+  <max_length>72</max_length>
+ </commit_template>
+ <retry>
+  <max_attempts>1</max_attempts>
+ </retry>
+ <cache>
+  <enabled>false</enabled>
+ </cache>
+ <history>
+  <enabled>false</enabled>
+ </history>
+</config>
+`
+}
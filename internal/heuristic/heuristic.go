@@ -0,0 +1,244 @@
+// Package heuristic builds a Conventional Commit message from the changed
+// files alone, for when no model can be reached.
+package heuristic
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"gitr/internal/git"
+)
+
+// Options controls the generated message
+type Options struct {
+	MaxLength    int  // longest header; 0 means no limit
+	IncludeScope bool // add a scope derived from the common directory
+}
+
+// maxBodyFiles is how many files the body lists
+const maxBodyFiles = 10
+
+// Message builds a commit message: the type is inferred from the kind of
+// files, the scope from their common directory and the subject from the
+// file and symbol names. The body lists the changed files.
+func Message(changes []git.FileChange, opts Options) string {
+	if len(changes) == 0 {
+		return ""
+	}
+
+	kind := Type(changes)
+	header := kind
+	if opts.IncludeScope {
+		// docs(docs) says nothing the type doesn't
+		if scope := Scope(changes); scope != "" && scope != kind {
+			header += "(" + scope + ")"
+		}
+	}
+	header += ": "
+	header += fitSubject(subject(changes), opts.MaxLength-utf8.RuneCountInString(header))
+
+	var body strings.Builder
+	for i, change := range changes {
+		if i == maxBodyFiles {
+			fmt.Fprintf(&body, "- and %d more files\n", len(changes)-maxBodyFiles)
+			break
+		}
+		name := change.Path
+		if change.OldPath != "" {
+			name = change.OldPath + " -> " + change.Path
+		}
+		stats := fmt.Sprintf("+%d -%d", change.Added, change.Deleted)
+		if change.Binary {
+			stats = "binary"
+		}
+		fmt.Fprintf(&body, "- %s (%s, %s)\n", name, change.Status, stats)
+	}
+	return header + "\n\n" + strings.TrimRight(body.String(), "\n")
+}
+
+// Type infers the conventional commit type from the changed files
+func Type(changes []git.FileChange) string {
+	for _, category := range []struct {
+		name  string
+		match func(string) bool
+	}{
+		{"test", isTest},
+		{"docs", isDocs},
+		{"ci", isCI},
+		{"build", isBuild},
+	} {
+		if every(changes, func(c git.FileChange) bool { return category.match(c.Path) }) {
+			return category.name
+		}
+	}
+
+	code := some(changes, func(c git.FileChange) bool { return isCode(c.Path) })
+	added, deleted := 0, 0
+	for _, c := range changes {
+		added += c.Added
+		deleted += c.Deleted
+	}
+
+	switch {
+	case every(changes, func(c git.FileChange) bool { return c.Status == "deleted" }) || (added == 0 && deleted > 0):
+		if code {
+			return "refactor"
+		}
+		return "chore"
+	case every(changes, func(c git.FileChange) bool { return c.Status == "renamed" && c.Added == 0 && c.Deleted == 0 }):
+		return "refactor"
+	case !code:
+		return "chore"
+	case some(changes, func(c git.FileChange) bool { return c.Status == "added" && isCode(c.Path) && !isTest(c.Path) }):
+		return "feat"
+	case added > deleted:
+		return "feat"
+	}
+	return "refactor"
+}
+
+// genericDirs don't make a useful scope on their own
+var genericDirs = map[string]bool{
+	"src": true, "lib": true, "pkg": true, "internal": true, "app": true, "source": true,
+}
+
+// Scope returns the most specific directory all changed files share, or ""
+func Scope(changes []git.FileChange) string {
+	common := path.Dir(changes[0].Path)
+	for _, change := range changes[1:] {
+		for common != "." && !strings.HasPrefix(change.Path, common+"/") {
+			common = path.Dir(common)
+		}
+	}
+	if common == "." || genericDirs[path.Base(common)] {
+		return ""
+	}
+	// Test and doc folders say less than the code they belong to
+	if base := path.Base(common); (base == "test" || base == "tests" || base == "docs") && path.Dir(common) != "." {
+		common = path.Dir(common)
+		if genericDirs[path.Base(common)] {
+			return ""
+		}
+	}
+	return path.Base(common)
+}
+
+// subject describes what happened to the files
+func subject(changes []git.FileChange) string {
+	if len(changes) == 1 {
+		change := changes[0]
+		name := path.Base(change.Path)
+		switch change.Status {
+		case "added":
+			return "add " + name
+		case "deleted":
+			return "remove " + name
+		case "renamed":
+			return fmt.Sprintf("rename %s to %s", path.Base(change.OldPath), name)
+		}
+		if symbols := symbolNames(change.Symbols, 2); len(symbols) > 0 {
+			return "update " + join(symbols) + " in " + name
+		}
+		return "update " + name
+	}
+
+	verb := "update"
+	switch {
+	case every(changes, func(c git.FileChange) bool { return c.Status == "added" }):
+		verb = "add"
+	case every(changes, func(c git.FileChange) bool { return c.Status == "deleted" }):
+		verb = "remove"
+	case every(changes, func(c git.FileChange) bool { return c.Status == "renamed" }):
+		verb = "move"
+	}
+
+	if len(changes) <= 3 {
+		names := make([]string, len(changes))
+		for i, change := range changes {
+			names[i] = path.Base(change.Path)
+		}
+		return verb + " " + join(names)
+	}
+	if scope := Scope(changes); scope != "" {
+		return fmt.Sprintf("%s %d files in %s", verb, len(changes), scope)
+	}
+	return fmt.Sprintf("%s %d files", verb, len(changes))
+}
+
+// fitSubject shortens a subject to width characters, cutting at a word
+func fitSubject(subject string, width int) string {
+	if width <= 0 || utf8.RuneCountInString(subject) <= width {
+		return subject
+	}
+	runes := []rune(subject)
+	cut := string(runes[:width])
+	// Keep the last word when the cut falls right after it
+	if i := strings.LastIndex(cut, " "); i > 0 && runes[width] != ' ' {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,")
+}
+
+var identifierPattern = regexp.MustCompile(`[A-Za-z_$][\w$]*`)
+
+// symbolNames turns enclosing symbols such as "method (*T).Get" or git's
+// "def load(self):" into plain names, up to limit of them
+func symbolNames(symbols []string, limit int) []string {
+	var names []string
+	for _, symbol := range symbols {
+		if len(names) == limit {
+			break
+		}
+		name := ""
+		switch {
+		case symbol == "imports":
+			continue
+		case strings.HasPrefix(symbol, "method ("):
+			// method (*T).Get -> T.Get
+			name = strings.NewReplacer("method (", "", "*", "", ")", "").Replace(symbol)
+		case strings.HasPrefix(symbol, "func "), strings.HasPrefix(symbol, "type "),
+			strings.HasPrefix(symbol, "var "), strings.HasPrefix(symbol, "const "):
+			_, name, _ = strings.Cut(symbol, " ")
+		default:
+			// The identifier before the first parenthesis, e.g. "function load(x) {"
+			head, _, _ := strings.Cut(symbol, "(")
+			words := identifierPattern.FindAllString(head, -1)
+			if len(words) > 0 {
+				name = words[len(words)-1]
+			}
+		}
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// join lists names as "a", "a and b" or "a, b and c"
+func join(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+func every(changes []git.FileChange, f func(git.FileChange) bool) bool {
+	for _, change := range changes {
+		if !f(change) {
+			return false
+		}
+	}
+	return true
+}
+
+func some(changes []git.FileChange, f func(git.FileChange) bool) bool {
+	for _, change := range changes {
+		if f(change) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package heuristic
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/git"
+)
+
+// modified is a change to an existing file with the given line counts
+func modified(p string, added, deleted int) git.FileChange {
+	return git.FileChange{Path: p, Status: "modified", Added: added, Deleted: deleted}
+}
+
+func TestType(t *testing.T) {
+	tests := []struct {
+		name    string
+		changes []git.FileChange
+		want    string
+	}{
+		{"go tests", []git.FileChange{modified("internal/git/git_test.go", 10, 2), modified("testdata/a.txt", 1, 0)}, "test"},
+		{"docs", []git.FileChange{modified("README.md", 3, 1), modified("docs/setup.html", 1, 1)}, "docs"},
+		{"workflow", []git.FileChange{modified(".github/workflows/ci.yml", 2, 2)}, "ci"},
+		{"dependencies", []git.FileChange{modified("go.mod", 1, 1), modified("go.sum", 2, 2)}, "build"},
+		{"requirements.txt is build, not docs", []git.FileChange{modified("requirements.txt", 1, 0)}, "build"},
+		{"new source file", []git.FileChange{{Path: "cmd/stash.go", Status: "added", Added: 40}}, "feat"},
+		{"mostly additions", []git.FileChange{modified("cmd/root.go", 20, 3)}, "feat"},
+		{"mostly deletions", []git.FileChange{modified("cmd/root.go", 3, 20)}, "refactor"},
+		{"deleted code", []git.FileChange{{Path: "cmd/old.go", Status: "deleted", Deleted: 50}}, "refactor"},
+		{"deleted config", []git.FileChange{{Path: "config.yaml", Status: "deleted", Deleted: 5}}, "chore"},
+		{"pure rename", []git.FileChange{{Path: "cmd/new.go", OldPath: "cmd/old.go", Status: "renamed"}}, "refactor"},
+		{"config only", []git.FileChange{modified(".editorconfig", 2, 0), modified("config.yaml", 1, 1)}, "chore"},
+		{"test next to new code", []git.FileChange{{Path: "a/a.go", Status: "added", Added: 5}, {Path: "a/a_test.go", Status: "added", Added: 9}}, "feat"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Type(tt.changes); got != tt.want {
+				t.Errorf("Type = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{[]string{"internal/llm/retry.go"}, "llm"},
+		{[]string{"internal/llm/retry.go", "internal/llm/llm.go"}, "llm"},
+		{[]string{"internal/llm/retry.go", "internal/git/git.go"}, ""},
+		{[]string{"cmd/root.go", "README.md"}, ""},
+		{[]string{"src/main.go"}, ""},
+		{[]string{"web/src/app/page.tsx"}, ""},
+		{[]string{"parser/tests/lexer_test.go", "parser/tests/testdata/x"}, "parser"},
+		{[]string{"docs/usage.md"}, "docs"},
+		{[]string{"api/v1/users.go", "api/v2/users.go"}, "api"},
+		{[]string{"apiary/x.go", "api/y.go"}, ""},
+	}
+	for _, tt := range tests {
+		changes := make([]git.FileChange, len(tt.paths))
+		for i, p := range tt.paths {
+			changes[i] = modified(p, 1, 1)
+		}
+		if got := Scope(changes); got != tt.want {
+			t.Errorf("Scope(%v) = %q, want %q", tt.paths, got, tt.want)
+		}
+	}
+}
+
+func TestFitSubject(t *testing.T) {
+	tests := []struct {
+		subject string
+		width   int
+		want    string
+	}{
+		{"update retry.go", 0, "update retry.go"},
+		{"update retry.go", -3, "update retry.go"},
+		{"update retry.go", 15, "update retry.go"},
+		{"update retry.go and llm.go", 20, "update retry.go and"},
+		{"update a.go, b.go and c.go", 13, "update a.go"},
+		{"supercalifragilistic", 5, "super"},
+		{"ändere größe und breite", 12, "ändere größe"},
+	}
+	for _, tt := range tests {
+		if got := fitSubject(tt.subject, tt.width); got != tt.want {
+			t.Errorf("fitSubject(%q, %d) = %q, want %q", tt.subject, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	changes := []git.FileChange{
+		{Path: "internal/llm/retry.go", Status: "modified", Added: 12, Deleted: 3, Symbols: []string{"method (*retryTransport).RoundTrip", "func backoff"}},
+	}
+	got := Message(changes, Options{MaxLength: 72, IncludeScope: true})
+	want := "feat(llm): update retryTransport.RoundTrip and backoff in retry.go\n\n- internal/llm/retry.go (modified, +12 -3)"
+	if got != want {
+		t.Errorf("Message =\n%s\nwant\n%s", got, want)
+	}
+
+	header, _, _ := strings.Cut(Message(changes, Options{MaxLength: 30}), "\n")
+	if header != "feat: update" {
+		t.Errorf("header = %q, want it cut at a word within 30 characters", header)
+	}
+
+	if got := Message(nil, Options{}); got != "" {
+		t.Errorf("Message(nil) = %q", got)
+	}
+}
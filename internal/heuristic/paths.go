@@ -0,0 +1,99 @@
+package heuristic
+
+import (
+	"path"
+	"strings"
+)
+
+// isTest reports whether a path is a test file or lives in a test directory
+func isTest(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	switch {
+	case strings.HasSuffix(base, "_test.go"),
+		strings.HasPrefix(base, "test_") && strings.HasSuffix(base, ".py"),
+		strings.HasSuffix(base, "_test.py"),
+		strings.Contains(base, ".test."),
+		strings.Contains(base, ".spec."),
+		strings.HasSuffix(base, "test.java"),
+		strings.HasSuffix(base, "tests.cs"):
+		return true
+	}
+	return inDir(p, "test", "tests", "__tests__", "testdata", "spec")
+}
+
+// isDocs reports whether a path is documentation
+func isDocs(p string) bool {
+	if isBuild(p) {
+		return false
+	}
+	base := strings.ToLower(path.Base(p))
+	switch path.Ext(base) {
+	case ".md", ".markdown", ".rst", ".adoc", ".txt":
+		return true
+	}
+	for _, prefix := range []string{"readme", "license", "changelog", "contributing", "authors", "notice"} {
+		if strings.HasPrefix(base, prefix) {
+			return true
+		}
+	}
+	return inDir(p, "docs", "doc", "documentation")
+}
+
+// isCI reports whether a path configures continuous integration
+func isCI(p string) bool {
+	lower := strings.ToLower(p)
+	for _, prefix := range []string{".github/workflows/", ".github/actions/", ".circleci/", ".buildkite/", ".gitlab/ci/", ".woodpecker/"} {
+		if strings.HasPrefix(lower, prefix) {
+			return true
+		}
+	}
+	switch lower {
+	case ".gitlab-ci.yml", ".travis.yml", "jenkinsfile", "azure-pipelines.yml", "bitbucket-pipelines.yml",
+		"appveyor.yml", ".drone.yml", ".woodpecker.yml", "cloudbuild.yaml":
+		return true
+	}
+	return false
+}
+
+// isBuild reports whether a path belongs to the build system or dependencies
+func isBuild(p string) bool {
+	base := strings.ToLower(path.Base(p))
+	switch base {
+	case "go.mod", "go.sum", "makefile", "gnumakefile", "cmakelists.txt", "dockerfile", ".dockerignore",
+		"package.json", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "bun.lockb",
+		"cargo.toml", "cargo.lock", "pyproject.toml", "setup.py", "setup.cfg", "requirements.txt",
+		"poetry.lock", "pipfile", "pipfile.lock", "pom.xml", "build.gradle", "build.gradle.kts",
+		"settings.gradle", "gemfile", "gemfile.lock", "composer.json", "composer.lock",
+		".goreleaser.yml", ".goreleaser.yaml", "docker-compose.yml", "docker-compose.yaml":
+		return true
+	}
+	return strings.HasSuffix(base, ".mk") || strings.HasPrefix(base, "dockerfile.") || strings.HasPrefix(base, "requirements")
+}
+
+// codeExtensions are source files; everything else counts as configuration or assets
+var codeExtensions = map[string]bool{
+	".go": true, ".py": true, ".js": true, ".jsx": true, ".ts": true, ".tsx": true, ".mjs": true, ".cjs": true,
+	".java": true, ".kt": true, ".kts": true, ".scala": true, ".rs": true, ".c": true, ".h": true, ".cc": true,
+	".cpp": true, ".hpp": true, ".cs": true, ".rb": true, ".php": true, ".swift": true, ".m": true, ".mm": true,
+	".sh": true, ".bash": true, ".zsh": true, ".ps1": true, ".lua": true, ".dart": true, ".ex": true, ".exs": true,
+	".erl": true, ".hs": true, ".clj": true, ".sql": true, ".vue": true, ".svelte": true, ".css": true, ".scss": true,
+	".html": true, ".zig": true, ".r": true, ".pl": true,
+}
+
+// isCode reports whether a path is source code
+func isCode(p string) bool {
+	return codeExtensions[strings.ToLower(path.Ext(p))]
+}
+
+// inDir reports whether any directory of the path has one of the names
+func inDir(p string, names ...string) bool {
+	dirs := strings.Split(strings.ToLower(path.Dir(p)), "/")
+	for _, dir := range dirs {
+		for _, name := range names {
+			if dir == name {
+				return true
+			}
+		}
+	}
+	return false
+}
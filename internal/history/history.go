@@ -0,0 +1,227 @@
+package history
+
+import (
+	"bufio"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gitr/internal/config"
+)
+
+// Actions taken on a generated message
+const (
+	ActionDisplayed = "displayed" // shown without committing (plain `gitr`)
+	ActionAccepted  = "accepted"  // committed verbatim
+	ActionEdited    = "edited"    // committed after the user edited it
+	ActionRejected  = "rejected"  // rejected in the commit dialog
+	ActionBypassed  = "bypassed"  // committed without confirmation
+	ActionFailed    = "failed"    // git commit failed
+)
+
+// Entry represents one generation and what happened to it
+type Entry struct {
+	ID            string    `json:"id"`
+	Timestamp     time.Time `json:"timestamp"`
+	Repo          string    `json:"repo"`
+	Profile       string    `json:"profile"`
+	Model         string    `json:"model"`
+	DiffHash      string    `json:"diff_hash"`
+	Cached        bool      `json:"cached,omitempty"`
+	RawResponse   string    `json:"raw_response"`
+	Message       string    `json:"message"`
+	FinalMessage  string    `json:"final_message,omitempty"`
+	Action        string    `json:"action"`
+	Regenerations int       `json:"regenerations,omitempty"`
+}
+
+// Filter selects history entries
+type Filter struct {
+	Repo   string // only entries for this repository
+	Model  string // only entries for this model
+	Search string // case-insensitive substring of the messages
+	Limit  int    // maximum number of entries (newest first), 0 for all
+}
+
+// ModelStats summarizes the actions taken on messages from one model
+type ModelStats struct {
+	Model   string
+	Total   int
+	Actions map[string]int
+}
+
+// AcceptanceRate returns the share of reviewed messages committed verbatim
+func (s ModelStats) AcceptanceRate() float64 {
+	return s.rate(s.Actions[ActionAccepted])
+}
+
+// EditRate returns the share of reviewed messages edited before committing
+func (s ModelStats) EditRate() float64 {
+	return s.rate(s.Actions[ActionEdited])
+}
+
+// RejectionRate returns the share of reviewed messages rejected
+func (s ModelStats) RejectionRate() float64 {
+	return s.rate(s.Actions[ActionRejected])
+}
+
+// Reviewed returns how many messages went through the commit dialog
+func (s ModelStats) Reviewed() int {
+	return s.Actions[ActionAccepted] + s.Actions[ActionEdited] + s.Actions[ActionRejected]
+}
+
+func (s ModelStats) rate(n int) float64 {
+	reviewed := s.Reviewed()
+	if reviewed == 0 {
+		return 0
+	}
+	return float64(n) / float64(reviewed)
+}
+
+// Path returns the location of the history file
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.jsonl"), nil
+}
+
+// Record appends an entry to the history file
+func Record(entry Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	if entry.ID == "" {
+		entry.ID = newID()
+	}
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.Write(append(data, '\n'))
+	return err
+}
+
+// List returns the entries matching the filter, newest first
+func List(filter Filter) ([]Entry, error) {
+	entries, err := readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	search := strings.ToLower(filter.Search)
+	var matches []Entry
+	for i := len(entries) - 1; i >= 0; i-- {
+		entry := entries[i]
+		if filter.Repo != "" && entry.Repo != filter.Repo {
+			continue
+		}
+		if filter.Model != "" && entry.Model != filter.Model {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(entry.Message), search) &&
+			!strings.Contains(strings.ToLower(entry.FinalMessage), search) {
+			continue
+		}
+		matches = append(matches, entry)
+		if filter.Limit > 0 && len(matches) >= filter.Limit {
+			break
+		}
+	}
+
+	return matches, nil
+}
+
+// Stats returns per-model statistics for the entries matching the filter
+func Stats(filter Filter) ([]ModelStats, error) {
+	filter.Limit = 0
+	entries, err := List(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	byModel := map[string]*ModelStats{}
+	for _, entry := range entries {
+		stats, ok := byModel[entry.Model]
+		if !ok {
+			stats = &ModelStats{Model: entry.Model, Actions: map[string]int{}}
+			byModel[entry.Model] = stats
+		}
+		stats.Total++
+		stats.Actions[entry.Action]++
+	}
+
+	result := make([]ModelStats, 0, len(byModel))
+	for _, stats := range byModel {
+		result = append(result, *stats)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total != result[j].Total {
+			return result[i].Total > result[j].Total
+		}
+		return result[i].Model < result[j].Model
+	})
+
+	return result, nil
+}
+
+// readAll reads every entry in the history file, oldest first
+func readAll() ([]Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		// Skip lines that are corrupt, e.g. from an interrupted write
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+func newID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
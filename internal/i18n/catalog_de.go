@@ -0,0 +1,210 @@
+package i18n
+
+// catalogDE is the German translation of the interface
+var catalogDE = map[string]string{
+	"Generated Commit Message:":                          "Generierte Commit-Nachricht:",
+	"Generated Tag Message:":                             "Generierte Tag-Nachricht:",
+	"Options:":                                           "Optionen:",
+	"Accept and commit (default)":                        "Übernehmen und committen (Standard)",
+	"Accept and tag (default)":                           "Übernehmen und Tag erstellen (Standard)",
+	"Edit message":                                       "Nachricht bearbeiten",
+	"Regenerate message":                                 "Nachricht neu generieren",
+	"Regenerate with an extra instruction":               "Mit zusätzlicher Anweisung neu generieren",
+	"Add co-authors":                                     "Co-Autoren hinzufügen",
+	"Reject (don't commit)":                              "Verwerfen (nicht committen)",
+	"Reject (don't tag)":                                 "Verwerfen (kein Tag erstellen)",
+	"Choose an option [%s] (or press Enter to accept): ": "Option wählen [%s] (oder Enter zum Übernehmen): ",
+	"Error editing message: %v\n":                        "Fehler beim Bearbeiten der Nachricht: %v\n",
+	"Updated Commit Message:":                            "Aktualisierte Commit-Nachricht:",
+	"Updated Tag Message:":                               "Aktualisierte Tag-Nachricht:",
+	"Invalid option. Please choose %s.\n":                "Ungültige Option. Bitte %s wählen.\n",
+	"Extra instruction: ":                                "Zusätzliche Anweisung: ",
+	"Regenerating commit message...":                     "Commit-Nachricht wird neu generiert...",
+	"Regenerating tag message...":                        "Tag-Nachricht wird neu generiert...",
+	"Error regenerating message: %v\n":                   "Fehler beim Neugenerieren der Nachricht: %v\n",
+	"Regenerated Commit Message:":                        "Neu generierte Commit-Nachricht:",
+	"Regenerated Tag Message:":                           "Neu generierte Tag-Nachricht:",
+	"Team: %s\n":                                         "Team: %s\n",
+	"Co-authors (comma-separated): ":                     "Co-Autoren (durch Kommas getrennt): ",
+	"Error adding co-authors: %v\n":                      "Fehler beim Hinzufügen der Co-Autoren: %v\n",
+	", or ":                                              " oder ",
+	"Edit Commit Message:":                               "Commit-Nachricht bearbeiten:",
+	"Edit Tag Message:":                                  "Tag-Nachricht bearbeiten:",
+	"Current message: %s\n":                              "Aktuelle Nachricht: %s\n",
+	"Edit message: ":                                     "Nachricht bearbeiten: ",
+	"e.g. mention the migration, use scope api":          "z. B. Migration erwähnen, Scope api verwenden",
+	"Instruction: ":                                      "Anweisung: ",
+	"alias, name or \"Name <email>\", comma-separated":   "Alias, Name oder \"Name <E-Mail>\", durch Kommas getrennt",
+	"Co-authors: ":                                       "Co-Autoren: ",
+	"Error regenerating message: %v":                     "Fehler beim Neugenerieren der Nachricht: %v",
+	"Message regenerated":                                "Nachricht neu generiert",
+	"Co-authors added":                                   "Co-Autoren hinzugefügt",
+	"Loading...":                                         "Wird geladen...",
+	"GitR Commit Review":                                 "GitR Commit-Prüfung",
+	"GitR Tag Review":                                    "GitR Tag-Prüfung",
+	"Editing • Esc/Ctrl+S: Done • Ctrl+C: Cancel commit": "Bearbeiten • Esc/Strg+S: Fertig • Strg+C: Commit abbrechen",
+	"Editing • Esc/Ctrl+S: Done • Ctrl+C: Cancel tag":    "Bearbeiten • Esc/Strg+S: Fertig • Strg+C: Tag abbrechen",
+	"Enter: Regenerate • Esc: Back":                      "Enter: Neu generieren • Esc: Zurück",
+	"Enter: Add • Esc: Back":                             "Enter: Hinzufügen • Esc: Zurück",
+	"Please wait...":                                     "Bitte warten...",
+	"a/Enter: Accept • e: Edit":                          "a/Enter: Übernehmen • e: Bearbeiten",
+	"r: Regenerate • i: Regenerate with instruction":     "r: Neu generieren • i: Mit Anweisung neu generieren",
+	"w: Co-authors":                                      "w: Co-Autoren",
+	"Tab: Files/Diff • ↑/↓: Navigate • q/Esc: Cancel":    "Tab: Dateien/Diff • ↑/↓: Navigieren • q/Esc: Abbrechen",
+	"No files":                                           "Keine Dateien",
+	"Header: %d":                                         "Kopfzeile: %d",
+	" (too long)":                                        " (zu lang)",
+	"The default profile cannot be deleted":              "Das Standardprofil kann nicht gelöscht werden",
+	"Default profile set to %s":                          "Standardprofil auf %s gesetzt",
+	"empty to unset":                                     "leer lassen zum Zurücksetzen",
+	"New profile name: ":                                 "Name des neuen Profils: ",
+	"Clone %s as: ":                                      "%s klonen als: ",
+	"Profile %s created":                                 "Profil %s erstellt",
+	"Profile %s deleted":                                 "Profil %s gelöscht",
+	"Error saving config: %v":                            "Fehler beim Speichern der Konfiguration: %v",
+	"GitR Configuration Editor":                          "GitR Konfigurationseditor",
+	" (modified)":                                        " (geändert)",
+	" (default)":                                         " (Standard)",
+	"Profiles: ":                                         "Profile: ",
+	"Enter: Apply • Esc: Cancel":                         "Enter: Übernehmen • Esc: Abbrechen",
+	"Delete profile %s? [y/N]":                           "Profil %s löschen? [y/N]",
+	"You have unsaved changes. Save before quitting? [y]es / [n]o / [esc] cancel":        "Es gibt ungespeicherte Änderungen. Vor dem Beenden speichern? [y] ja / [n] nein / [esc] abbrechen",
+	"↑/↓: Navigate • Enter: Edit/Toggle • ←/→: Change choice • s: Save & Exit • q: Quit": "↑/↓: Navigieren • Enter: Bearbeiten/Umschalten • ←/→: Auswahl ändern • s: Speichern & Beenden • q: Beenden",
+	"p/P: Switch profile • n: New profile • c: Clone • d: Delete • u: Use as default":    "p/P: Profil wechseln • n: Neues Profil • c: Klonen • d: Löschen • u: Als Standard verwenden",
+	"Configuration saved to: %s\n":                           "Konfiguration gespeichert in: %s\n",
+	"Changes discarded.":                                     "Änderungen verworfen.",
+	"=== GitR Configuration Editor ===":                      "=== GitR Konfigurationseditor ===",
+	"Configuration file: %s\n\n":                             "Konfigurationsdatei: %s\n\n",
+	"\nEnter choice":                                         "\nAuswahl eingeben",
+	"Error saving config: %v\n":                              "Fehler beim Speichern der Konfiguration: %v\n",
+	"Configuration saved successfully!":                      "Konfiguration erfolgreich gespeichert!",
+	"Exiting without saving...":                              "Beenden ohne zu speichern...",
+	"Invalid choice. Please try again.":                      "Ungültige Auswahl. Bitte erneut versuchen.",
+	"Current Configuration:":                                 "Aktuelle Konfiguration:",
+	"Profile: %s (default: %s, available: %s)\n":             "Profil: %s (Standard: %s, verfügbar: %s)\n",
+	"\nOptions:":                                             "\nOptionen:",
+	"<number>. Edit field":                                   "<Nummer>. Feld bearbeiten",
+	"p. Manage profiles":                                     "p. Profile verwalten",
+	"l. List settings again":                                 "l. Einstellungen erneut anzeigen",
+	"s. Save and exit":                                       "s. Speichern und beenden",
+	"q. Quit without saving":                                 "q. Beenden ohne zu speichern",
+	"Enter new value for %s (current: %s)%s":                 "Neuer Wert für %s (aktuell: %s)%s",
+	"Invalid value: choose one of %s\n":                      "Ungültiger Wert: einen von %s wählen\n",
+	"Invalid value: %v\n":                                    "Ungültiger Wert: %v\n",
+	"%s updated to: %s\n":                                    "%s geändert auf: %s\n",
+	"Profiles:":                                              "Profile:",
+	"Default profile: %s\n":                                  "Standardprofil: %s\n",
+	"a. Add profile":                                         "a. Profil hinzufügen",
+	"c. Clone profile":                                       "c. Profil klonen",
+	"d. Delete profile":                                      "d. Profil löschen",
+	"u. Use profile as default":                              "u. Profil als Standard verwenden",
+	"e. Edit another profile":                                "e. Anderes Profil bearbeiten",
+	"b. Back":                                                "b. Zurück",
+	"New profile name":                                       "Name des neuen Profils",
+	"Profile %s added. Its settings are now being edited.\n": "Profil %s hinzugefügt. Seine Einstellungen werden jetzt bearbeitet.\n",
+	"Profile to clone":                                       "Zu klonendes Profil",
+	"Profile %s cloned to %s. Its settings are now being edited.\n": "Profil %s nach %s geklont. Seine Einstellungen werden jetzt bearbeitet.\n",
+	"Profile to delete":            "Zu löschendes Profil",
+	"Profile %s deleted.\n":        "Profil %s gelöscht.\n",
+	"Profile to use by default":    "Als Standard zu verwendendes Profil",
+	"Default profile set to %s.\n": "Standardprofil auf %s gesetzt.\n",
+	"Profile to edit":              "Zu bearbeitendes Profil",
+	"Error: %v\n":                  "Fehler: %v\n",
+	"Welcome to GitR!":             "Willkommen bei GitR!",
+	"Let's set up your configuration for AI-powered commit messages.": "Richten wir die Konfiguration für KI-generierte Commit-Nachrichten ein.",
+	"Profile: %s\n\n":                                                                "Profil: %s\n\n",
+	"Required Configuration:":                                                        "Erforderliche Konfiguration:",
+	"Enter your OpenAI API Key: ":                                                    "OpenAI-API-Schlüssel eingeben: ",
+	"API Key is required!":                                                           "Der API-Schlüssel ist erforderlich!",
+	"Enter API Base URL (default: %s): ":                                             "API-Basis-URL eingeben (Standard: %s): ",
+	"Enter Model name (default: %s): ":                                               "Modellname eingeben (Standard: %s): ",
+	"️  Optional Configuration:":                                                     "️  Optionale Konfiguration:",
+	"Enter Max Tokens (default: %d): ":                                               "Maximale Tokens eingeben (Standard: %d): ",
+	"Enter Temperature (default: %.2f): ":                                            "Temperatur eingeben (Standard: %.2f): ",
+	"Enter Commit Style (default: %s): ":                                             "Commit-Stil eingeben (Standard: %s): ",
+	"Enter Max Commit Length (default: %d): ":                                        "Maximale Commit-Länge eingeben (Standard: %d): ",
+	"Include scope in commits? (default: %t) [true/false]: ":                         "Scope in Commits angeben? (Standard: %t) [true/false]: ",
+	"Commit without confirmation? (default: %t) [true/false]: ":                      "Ohne Bestätigung committen? (Standard: %t) [true/false]: ",
+	"Saving configuration...":                                                        "Konfiguration wird gespeichert...",
+	"Setup complete! You can now use GitR to generate commit messages.":              "Einrichtung abgeschlossen! GitR kann jetzt Commit-Nachrichten generieren.",
+	"  Run 'gitr config' anytime to modify your settings.":                           "  Mit 'gitr config' lassen sich die Einstellungen jederzeit ändern.",
+	"Error finding config file: %v":                                                  "Fehler beim Suchen der Konfigurationsdatei: %v",
+	"Configuration error: no configuration found. Run 'gitr config' to set up GitR.": "Konfigurationsfehler: keine Konfiguration gefunden. Mit 'gitr config' lässt sich GitR einrichten.",
+	"First time setup detected!":                                                     "Ersteinrichtung erkannt!",
+	"Error during setup: %v\n":                                                       "Fehler bei der Einrichtung: %v\n",
+	"Error loading config: %v":                                                       "Fehler beim Laden der Konfiguration: %v",
+	"Run 'gitr config' to manage your profiles.":                                     "Mit 'gitr config' lassen sich die Profile verwalten.",
+	"Configuration error: %v":                                                        "Konfigurationsfehler: %v",
+	"Configuration error: configuration incomplete. Run 'gitr config' to finish the setup.": "Konfigurationsfehler: Konfiguration unvollständig. Mit 'gitr config' lässt sich die Einrichtung abschließen.",
+	"Configuration incomplete. Running setup...":                                            "Konfiguration unvollständig. Einrichtung wird gestartet...",
+	"Supported languages: %s":                                                               "Unterstützte Sprachen: %s",
+	"Error: unknown language %q":                                                            "Fehler: unbekannte Sprache %q",
+	"Run 'gitr config' to fix your configuration.":                                          "Mit 'gitr config' lässt sich die Konfiguration korrigieren.",
+	"Error: Not in a git repository":                                                        "Fehler: kein Git-Repository",
+	"No staged changes found":                                                               "Keine vorgemerkten Änderungen gefunden",
+	"Error: unknown cleanup mode %q (expected %s)":                                          "Fehler: unbekannter Cleanup-Modus %q (erwartet: %s)",
+	"Please stage some changes first with 'git add', or use --all or --pick":                "Bitte zuerst Änderungen mit 'git add' vormerken oder --all bzw. --pick verwenden",
+	"Failed to generate commit message":                                                     "Die Commit-Nachricht konnte nicht generiert werden",
+	"Bypassing confirmation (using generated message)...":                                   "Bestätigung wird übersprungen (generierte Nachricht wird verwendet)...",
+	"Error in commit editor: %v":                                                            "Fehler im Commit-Editor: %v",
+	"Commit cancelled":                                                                      "Commit abgebrochen",
+	"Committing changes...":                                                                 "Änderungen werden committet...",
+	"Commit failed: %v":                                                                     "Commit fehlgeschlagen: %v",
+	"Commit successful!":                                                                    "Commit erfolgreich!",
+	"Committed with message: %s\n":                                                          "Committet mit der Nachricht: %s\n",
+	"Error generating commit message: %v":                                                   "Fehler beim Generieren der Commit-Nachricht: %v",
+	"Error generating commit message: %v\n":                                                 "Fehler beim Generieren der Commit-Nachricht: %v\n",
+	"Falling back to a heuristic message built from the changed files.":                     "Stattdessen wird eine heuristische Nachricht aus den geänderten Dateien erstellt.",
+	"Warning: %v\n":                                                                         "Warnung: %v\n",
+	"Heuristic message built from the changed files (no model was used)":                    "Heuristische Nachricht aus den geänderten Dateien (kein Modell verwendet)",
+	"Error reading changes: %v":                                                             "Fehler beim Lesen der Änderungen: %v",
+	"Error getting changes: %v":                                                             "Fehler beim Abrufen der Änderungen: %v",
+	"Error getting staged changes: %v":                                                      "Fehler beim Abrufen der vorgemerkten Änderungen: %v",
+	"Warning: failed to enrich diff: %v\n":                                                  "Warnung: Diff konnte nicht angereichert werden: %v\n",
+	"Warning: failed to compare Go APIs: %v\n":                                              "Warnung: Go-APIs konnten nicht verglichen werden: %v\n",
+	"Warning: the API of %s wasn't compared, nothing in it is marked as breaking: %s\n":     "Warnung: die API von %s wurde nicht verglichen, nichts darin wird als inkompatibel markiert: %s\n",
+	"Warning: failed to read the merge state: %v\n":                                         "Warnung: Merge-Status konnte nicht gelesen werden: %v\n",
+	"Resolve the conflicts and stage the files first":                                       "Zuerst die Konflikte lösen und die Dateien vormerken",
+	"The merge has unresolved conflicts in %s":                                              "Der Merge hat ungelöste Konflikte in %s",
+	"Warning: failed to list the merged commits: %v\n":                                      "Warnung: gemergte Commits konnten nicht aufgelistet werden: %v\n",
+	"Error listing changes: %v":                                                             "Fehler beim Auflisten der Änderungen: %v",
+	"No unstaged changes to pick from":                                                      "Keine nicht vorgemerkten Änderungen zur Auswahl",
+	"Error picking files: %v":                                                               "Fehler bei der Dateiauswahl: %v",
+	"Error staging files: %v":                                                               "Fehler beim Vormerken der Dateien: %v",
+	"Staged %d file(s)\n":                                                                   "%d Datei(en) vorgemerkt\n",
+	"Fix the roster or change commit.roster with 'gitr config set'.":                        "Die Teamliste korrigieren oder commit.roster mit 'gitr config set' ändern.",
+	"Error: %v":                                               "Fehler: %v",
+	"Error adding trailers: %v":                               "Fehler beim Hinzufügen der Trailer: %v",
+	"Warning: failed to record history: %v\n":                 "Warnung: Verlauf konnte nicht gespeichert werden: %v\n",
+	"usage not reported":                                      "Verbrauch nicht gemeldet",
+	"%d prompt + %d completion tokens":                        "%d Prompt- + %d Antwort-Tokens",
+	"cached":                                                  "aus dem Cache",
+	"Profile %q, model %s, %s, %s\n":                          "Profil %q, Modell %s, %s, %s\n",
+	"Error listing stashes: %v":                               "Fehler beim Auflisten der Stashes: %v",
+	"Warning: can't read %s: %v\n":                            "Warnung: %s kann nicht gelesen werden: %v\n",
+	"Warning: can't describe %s: %v\n":                        "Warnung: %s kann nicht beschrieben werden: %v\n",
+	"Error reading status: %v":                                "Fehler beim Lesen des Status: %v",
+	"No local changes to save":                                "Keine lokalen Änderungen zum Sichern",
+	"Saved stash: %s\n":                                       "Stash gesichert: %s\n",
+	"Error generating stash message: %v":                      "Fehler beim Generieren der Stash-Nachricht: %v",
+	"Error generating stash message: %v\n":                    "Fehler beim Generieren der Stash-Nachricht: %v\n",
+	"Falling back to a message built from the changed files.": "Stattdessen wird eine Nachricht aus den geänderten Dateien erstellt.",
+	"binary":            "binär",
+	"untracked":         "neu",
+	"conflict":          "Konflikt",
+	"modified":          "geändert",
+	"deleted":           "gelöscht",
+	"typechange":        "Typwechsel",
+	"added":             "hinzugefügt",
+	"staged":            "vorgemerkt",
+	" (partly staged)":  " (teilweise vorgemerkt)",
+	"Unstaged changes:": "Nicht vorgemerkte Änderungen:",
+	"Files to stage (e.g. 1,3-4, 'a' for all, Enter to cancel): ": "Vorzumerkende Dateien (z. B. 1,3-4, 'a' für alle, Enter zum Abbrechen): ",
+	"Invalid selection: %v\n":                                     "Ungültige Auswahl: %v\n",
+	"%q is not a number":                                          "%q ist keine Zahl",
+	"%q is out of range 1-%d":                                     "%q liegt außerhalb von 1-%d",
+	"GitR Stage Changes":                                          "GitR Änderungen vormerken",
+	"%d of %d selected • Space: Toggle • a: All • Enter: Stage and continue • q/Esc: Cancel": "%d von %d ausgewählt • Leertaste: Umschalten • a: Alle • Enter: Vormerken und fortfahren • q/Esc: Abbrechen",
+	"Enter Max Tokens (default: the provider's): ":                                           "Maximale Tokens eingeben (Standard: der des Anbieters): ",
+	"Enter Temperature (default: the provider's): ":                                          "Temperatur eingeben (Standard: die des Anbieters): ",
+}
@@ -0,0 +1,210 @@
+package i18n
+
+// catalogJA is the Japanese translation of the interface
+var catalogJA = map[string]string{
+	"Generated Commit Message:":                          "生成されたコミットメッセージ:",
+	"Generated Tag Message:":                             "生成されたタグメッセージ:",
+	"Options:":                                           "オプション:",
+	"Accept and commit (default)":                        "承認してコミット (デフォルト)",
+	"Accept and tag (default)":                           "承認してタグを作成 (デフォルト)",
+	"Edit message":                                       "メッセージを編集",
+	"Regenerate message":                                 "メッセージを再生成",
+	"Regenerate with an extra instruction":               "追加の指示付きで再生成",
+	"Add co-authors":                                     "共同作成者を追加",
+	"Reject (don't commit)":                              "却下 (コミットしない)",
+	"Reject (don't tag)":                                 "却下 (タグを作成しない)",
+	"Choose an option [%s] (or press Enter to accept): ": "オプションを選択 [%s] (Enter で承認): ",
+	"Error editing message: %v\n":                        "メッセージの編集中にエラーが発生しました: %v\n",
+	"Updated Commit Message:":                            "更新されたコミットメッセージ:",
+	"Updated Tag Message:":                               "更新されたタグメッセージ:",
+	"Invalid option. Please choose %s.\n":                "無効なオプションです。%s を選択してください。\n",
+	"Extra instruction: ":                                "追加の指示: ",
+	"Regenerating commit message...":                     "コミットメッセージを再生成しています...",
+	"Regenerating tag message...":                        "タグメッセージを再生成しています...",
+	"Error regenerating message: %v\n":                   "メッセージの再生成中にエラーが発生しました: %v\n",
+	"Regenerated Commit Message:":                        "再生成されたコミットメッセージ:",
+	"Regenerated Tag Message:":                           "再生成されたタグメッセージ:",
+	"Team: %s\n":                                         "チーム: %s\n",
+	"Co-authors (comma-separated): ":                     "共同作成者 (カンマ区切り): ",
+	"Error adding co-authors: %v\n":                      "共同作成者の追加中にエラーが発生しました: %v\n",
+	", or ":                                              "、または ",
+	"Edit Commit Message:":                               "コミットメッセージの編集:",
+	"Edit Tag Message:":                                  "タグメッセージの編集:",
+	"Current message: %s\n":                              "現在のメッセージ: %s\n",
+	"Edit message: ":                                     "メッセージを編集: ",
+	"e.g. mention the migration, use scope api":          "例: マイグレーションに触れる、スコープ api を使う",
+	"Instruction: ":                                      "指示: ",
+	"alias, name or \"Name <email>\", comma-separated":   "エイリアス、名前または \"Name <email>\" (カンマ区切り)",
+	"Co-authors: ":                                       "共同作成者: ",
+	"Error regenerating message: %v":                     "メッセージの再生成中にエラーが発生しました: %v",
+	"Message regenerated":                                "メッセージを再生成しました",
+	"Co-authors added":                                   "共同作成者を追加しました",
+	"Loading...":                                         "読み込み中...",
+	"GitR Commit Review":                                 "GitR コミットレビュー",
+	"GitR Tag Review":                                    "GitR タグレビュー",
+	"Editing • Esc/Ctrl+S: Done • Ctrl+C: Cancel commit": "編集中 • Esc/Ctrl+S: 完了 • Ctrl+C: コミットを中止",
+	"Editing • Esc/Ctrl+S: Done • Ctrl+C: Cancel tag":    "編集中 • Esc/Ctrl+S: 完了 • Ctrl+C: タグ作成を中止",
+	"Enter: Regenerate • Esc: Back":                      "Enter: 再生成 • Esc: 戻る",
+	"Enter: Add • Esc: Back":                             "Enter: 追加 • Esc: 戻る",
+	"Please wait...":                                     "お待ちください...",
+	"a/Enter: Accept • e: Edit":                          "a/Enter: 承認 • e: 編集",
+	"r: Regenerate • i: Regenerate with instruction":     "r: 再生成 • i: 指示付きで再生成",
+	"w: Co-authors":                                      "w: 共同作成者",
+	"Tab: Files/Diff • ↑/↓: Navigate • q/Esc: Cancel":    "Tab: ファイル/差分 • ↑/↓: 移動 • q/Esc: 中止",
+	"No files":                                           "ファイルなし",
+	"Header: %d":                                         "ヘッダー: %d",
+	" (too long)":                                        " (長すぎます)",
+	"The default profile cannot be deleted":              "デフォルトプロファイルは削除できません",
+	"Default profile set to %s":                          "デフォルトプロファイルを %s に設定しました",
+	"empty to unset":                                     "空欄で設定解除",
+	"New profile name: ":                                 "新しいプロファイル名: ",
+	"Clone %s as: ":                                      "%s の複製先: ",
+	"Profile %s created":                                 "プロファイル %s を作成しました",
+	"Profile %s deleted":                                 "プロファイル %s を削除しました",
+	"Error saving config: %v":                            "設定の保存中にエラーが発生しました: %v",
+	"GitR Configuration Editor":                          "GitR 設定エディター",
+	" (modified)":                                        " (変更あり)",
+	" (default)":                                         " (デフォルト)",
+	"Profiles: ":                                         "プロファイル: ",
+	"Enter: Apply • Esc: Cancel":                         "Enter: 適用 • Esc: キャンセル",
+	"Delete profile %s? [y/N]":                           "プロファイル %s を削除しますか? [y/N]",
+	"You have unsaved changes. Save before quitting? [y]es / [n]o / [esc] cancel":        "未保存の変更があります。終了する前に保存しますか? [y] はい / [n] いいえ / [esc] キャンセル",
+	"↑/↓: Navigate • Enter: Edit/Toggle • ←/→: Change choice • s: Save & Exit • q: Quit": "↑/↓: 移動 • Enter: 編集/切り替え • ←/→: 選択肢を変更 • s: 保存して終了 • q: 終了",
+	"p/P: Switch profile • n: New profile • c: Clone • d: Delete • u: Use as default":    "p/P: プロファイル切り替え • n: 新規プロファイル • c: 複製 • d: 削除 • u: デフォルトに設定",
+	"Configuration saved to: %s\n":                           "設定を保存しました: %s\n",
+	"Changes discarded.":                                     "変更を破棄しました。",
+	"=== GitR Configuration Editor ===":                      "=== GitR 設定エディター ===",
+	"Configuration file: %s\n\n":                             "設定ファイル: %s\n\n",
+	"\nEnter choice":                                         "\n選択してください",
+	"Error saving config: %v\n":                              "設定の保存中にエラーが発生しました: %v\n",
+	"Configuration saved successfully!":                      "設定を保存しました!",
+	"Exiting without saving...":                              "保存せずに終了します...",
+	"Invalid choice. Please try again.":                      "無効な選択です。もう一度お試しください。",
+	"Current Configuration:":                                 "現在の設定:",
+	"Profile: %s (default: %s, available: %s)\n":             "プロファイル: %s (デフォルト: %s、利用可能: %s)\n",
+	"\nOptions:":                                             "\nオプション:",
+	"<number>. Edit field":                                   "<番号>. 項目を編集",
+	"p. Manage profiles":                                     "p. プロファイルを管理",
+	"l. List settings again":                                 "l. 設定を再表示",
+	"s. Save and exit":                                       "s. 保存して終了",
+	"q. Quit without saving":                                 "q. 保存せずに終了",
+	"Enter new value for %s (current: %s)%s":                 "%s の新しい値を入力 (現在: %s)%s",
+	"Invalid value: choose one of %s\n":                      "無効な値です: %s のいずれかを選択してください\n",
+	"Invalid value: %v\n":                                    "無効な値です: %v\n",
+	"%s updated to: %s\n":                                    "%s を更新しました: %s\n",
+	"Profiles:":                                              "プロファイル:",
+	"Default profile: %s\n":                                  "デフォルトプロファイル: %s\n",
+	"a. Add profile":                                         "a. プロファイルを追加",
+	"c. Clone profile":                                       "c. プロファイルを複製",
+	"d. Delete profile":                                      "d. プロファイルを削除",
+	"u. Use profile as default":                              "u. プロファイルをデフォルトに設定",
+	"e. Edit another profile":                                "e. 別のプロファイルを編集",
+	"b. Back":                                                "b. 戻る",
+	"New profile name":                                       "新しいプロファイル名",
+	"Profile %s added. Its settings are now being edited.\n": "プロファイル %s を追加しました。続けてその設定を編集します。\n",
+	"Profile to clone":                                       "複製するプロファイル",
+	"Profile %s cloned to %s. Its settings are now being edited.\n": "プロファイル %s を %s に複製しました。続けてその設定を編集します。\n",
+	"Profile to delete":            "削除するプロファイル",
+	"Profile %s deleted.\n":        "プロファイル %s を削除しました。\n",
+	"Profile to use by default":    "デフォルトにするプロファイル",
+	"Default profile set to %s.\n": "デフォルトプロファイルを %s に設定しました。\n",
+	"Profile to edit":              "編集するプロファイル",
+	"Error: %v\n":                  "エラー: %v\n",
+	"Welcome to GitR!":             "GitR へようこそ!",
+	"Let's set up your configuration for AI-powered commit messages.": "AI によるコミットメッセージ生成の設定を行いましょう。",
+	"Profile: %s\n\n":                                                                "プロファイル: %s\n\n",
+	"Required Configuration:":                                                        "必須の設定:",
+	"Enter your OpenAI API Key: ":                                                    "OpenAI API キーを入力: ",
+	"API Key is required!":                                                           "API キーは必須です!",
+	"Enter API Base URL (default: %s): ":                                             "API ベース URL を入力 (デフォルト: %s): ",
+	"Enter Model name (default: %s): ":                                               "モデル名を入力 (デフォルト: %s): ",
+	"️  Optional Configuration:":                                                     "️  任意の設定:",
+	"Enter Max Tokens (default: %d): ":                                               "最大トークン数を入力 (デフォルト: %d): ",
+	"Enter Temperature (default: %.2f): ":                                            "温度を入力 (デフォルト: %.2f): ",
+	"Enter Commit Style (default: %s): ":                                             "コミットスタイルを入力 (デフォルト: %s): ",
+	"Enter Max Commit Length (default: %d): ":                                        "コミットの最大長を入力 (デフォルト: %d): ",
+	"Include scope in commits? (default: %t) [true/false]: ":                         "コミットにスコープを含めますか? (デフォルト: %t) [true/false]: ",
+	"Commit without confirmation? (default: %t) [true/false]: ":                      "確認なしでコミットしますか? (デフォルト: %t) [true/false]: ",
+	"Saving configuration...":                                                        "設定を保存しています...",
+	"Setup complete! You can now use GitR to generate commit messages.":              "セットアップが完了しました! GitR でコミットメッセージを生成できます。",
+	"  Run 'gitr config' anytime to modify your settings.":                           "  設定はいつでも 'gitr config' で変更できます。",
+	"Error finding config file: %v":                                                  "設定ファイルの検索中にエラーが発生しました: %v",
+	"Configuration error: no configuration found. Run 'gitr config' to set up GitR.": "設定エラー: 設定が見つかりません。'gitr config' で GitR をセットアップしてください。",
+	"First time setup detected!":                                                     "初回セットアップを検出しました!",
+	"Error during setup: %v\n":                                                       "セットアップ中にエラーが発生しました: %v\n",
+	"Error loading config: %v":                                                       "設定の読み込み中にエラーが発生しました: %v",
+	"Run 'gitr config' to manage your profiles.":                                     "'gitr config' でプロファイルを管理できます。",
+	"Configuration error: %v":                                                        "設定エラー: %v",
+	"Configuration error: configuration incomplete. Run 'gitr config' to finish the setup.": "設定エラー: 設定が不完全です。'gitr config' でセットアップを完了してください。",
+	"Configuration incomplete. Running setup...":                                            "設定が不完全です。セットアップを実行しています...",
+	"Supported languages: %s":                                                               "対応している言語: %s",
+	"Error: unknown language %q":                                                            "エラー: 不明な言語 %q",
+	"Run 'gitr config' to fix your configuration.":                                          "'gitr config' で設定を修正してください。",
+	"Error: Not in a git repository":                                                        "エラー: Git リポジトリではありません",
+	"No staged changes found":                                                               "ステージされた変更がありません",
+	"Error: unknown cleanup mode %q (expected %s)":                                          "エラー: 不明なクリーンアップモード %q (%s のいずれか)",
+	"Please stage some changes first with 'git add', or use --all or --pick":                "先に 'git add' で変更をステージするか、--all または --pick を使ってください",
+	"Failed to generate commit message":                                                     "コミットメッセージを生成できませんでした",
+	"Bypassing confirmation (using generated message)...":                                   "確認を省略します (生成されたメッセージを使用)...",
+	"Error in commit editor: %v":                                                            "コミットエディタでエラーが発生しました: %v",
+	"Commit cancelled":                                                                      "コミットを取り消しました",
+	"Committing changes...":                                                                 "変更をコミットしています...",
+	"Commit failed: %v":                                                                     "コミットに失敗しました: %v",
+	"Commit successful!":                                                                    "コミットしました!",
+	"Committed with message: %s\n":                                                          "コミットメッセージ: %s\n",
+	"Error generating commit message: %v":                                                   "コミットメッセージの生成中にエラーが発生しました: %v",
+	"Error generating commit message: %v\n":                                                 "コミットメッセージの生成中にエラーが発生しました: %v\n",
+	"Falling back to a heuristic message built from the changed files.":                     "代わりに変更されたファイルからヒューリスティックなメッセージを作成します。",
+	"Warning: %v\n":                                                                         "警告: %v\n",
+	"Heuristic message built from the changed files (no model was used)":                    "変更されたファイルから作成したヒューリスティックなメッセージ (モデルは使用していません)",
+	"Error reading changes: %v":                                                             "変更の読み取り中にエラーが発生しました: %v",
+	"Error getting changes: %v":                                                             "変更の取得中にエラーが発生しました: %v",
+	"Error getting staged changes: %v":                                                      "ステージされた変更の取得中にエラーが発生しました: %v",
+	"Warning: failed to enrich diff: %v\n":                                                  "警告: 差分を補強できませんでした: %v\n",
+	"Warning: failed to compare Go APIs: %v\n":                                              "警告: Go の API を比較できませんでした: %v\n",
+	"Warning: the API of %s wasn't compared, nothing in it is marked as breaking: %s\n":     "警告: %s の API は比較されなかったため、破壊的変更としてマークしません: %s\n",
+	"Warning: failed to read the merge state: %v\n":                                         "警告: マージの状態を読み取れませんでした: %v\n",
+	"Resolve the conflicts and stage the files first":                                       "先にコンフリクトを解決してファイルをステージしてください",
+	"The merge has unresolved conflicts in %s":                                              "マージに未解決のコンフリクトがあります: %s",
+	"Warning: failed to list the merged commits: %v\n":                                      "警告: マージされたコミットを一覧できませんでした: %v\n",
+	"Error listing changes: %v":                                                             "変更の一覧中にエラーが発生しました: %v",
+	"No unstaged changes to pick from":                                                      "選択できるステージされていない変更はありません",
+	"Error picking files: %v":                                                               "ファイルの選択中にエラーが発生しました: %v",
+	"Error staging files: %v":                                                               "ファイルのステージ中にエラーが発生しました: %v",
+	"Staged %d file(s)\n":                                                                   "%d 個のファイルをステージしました\n",
+	"Fix the roster or change commit.roster with 'gitr config set'.":                        "チーム名簿を修正するか、'gitr config set' で commit.roster を変更してください。",
+	"Error: %v":                                               "エラー: %v",
+	"Error adding trailers: %v":                               "トレーラーの追加中にエラーが発生しました: %v",
+	"Warning: failed to record history: %v\n":                 "警告: 履歴を記録できませんでした: %v\n",
+	"usage not reported":                                      "使用量の報告なし",
+	"%d prompt + %d completion tokens":                        "プロンプト %d + 応答 %d トークン",
+	"cached":                                                  "キャッシュ",
+	"Profile %q, model %s, %s, %s\n":                          "プロファイル %q、モデル %s、%s、%s\n",
+	"Error listing stashes: %v":                               "stash の一覧中にエラーが発生しました: %v",
+	"Warning: can't read %s: %v\n":                            "警告: %s を読み取れません: %v\n",
+	"Warning: can't describe %s: %v\n":                        "警告: %s を説明できません: %v\n",
+	"Error reading status: %v":                                "ステータスの読み取り中にエラーが発生しました: %v",
+	"No local changes to save":                                "保存するローカルの変更はありません",
+	"Saved stash: %s\n":                                       "stash を保存しました: %s\n",
+	"Error generating stash message: %v":                      "stash メッセージの生成中にエラーが発生しました: %v",
+	"Error generating stash message: %v\n":                    "stash メッセージの生成中にエラーが発生しました: %v\n",
+	"Falling back to a message built from the changed files.": "代わりに変更されたファイルからメッセージを作成します。",
+	"binary":            "バイナリ",
+	"untracked":         "未追跡",
+	"conflict":          "コンフリクト",
+	"modified":          "変更",
+	"deleted":           "削除",
+	"typechange":        "種類変更",
+	"added":             "追加",
+	"staged":            "ステージ済み",
+	" (partly staged)":  " (一部ステージ済み)",
+	"Unstaged changes:": "ステージされていない変更:",
+	"Files to stage (e.g. 1,3-4, 'a' for all, Enter to cancel): ": "ステージするファイル (例: 1,3-4、'a' ですべて、Enter で取り消し): ",
+	"Invalid selection: %v\n":                                     "無効な選択です: %v\n",
+	"%q is not a number":                                          "%q は数値ではありません",
+	"%q is out of range 1-%d":                                     "%q は 1-%d の範囲外です",
+	"GitR Stage Changes":                                          "GitR 変更のステージ",
+	"%d of %d selected • Space: Toggle • a: All • Enter: Stage and continue • q/Esc: Cancel": "%d / %d 個を選択 • Space: 切り替え • a: すべて • Enter: ステージして続行 • q/Esc: 取り消し",
+	"Enter Max Tokens (default: the provider's): ":                                           "最大トークン数を入力 (デフォルト: プロバイダーの既定値): ",
+	"Enter Temperature (default: the provider's): ":                                          "温度を入力 (デフォルト: プロバイダーの既定値): ",
+}
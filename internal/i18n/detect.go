@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// stopwords are frequent short words that tell languages written in the
+// Latin script apart
+var stopwords = map[string][]string{
+	"en": {"the", "and", "to", "of", "for", "with", "in", "is", "on", "this", "that", "from", "by", "when", "instead", "now", "into", "it", "be", "not"},
+	"de": {"der", "die", "das", "und", "nicht", "mit", "für", "von", "zu", "auf", "ist", "ein", "eine", "den", "dem", "des", "im", "wird", "werden", "bei", "aus", "nach", "wenn", "statt", "jetzt", "über", "sowie", "neue", "neuen"},
+	"fr": {"le", "la", "les", "et", "des", "du", "pour", "avec", "dans", "est", "une", "sur", "qui", "pas", "lors"},
+	"es": {"el", "la", "los", "las", "y", "del", "para", "con", "en", "es", "una", "por", "que", "al", "cuando"},
+	"it": {"il", "lo", "gli", "e", "del", "della", "per", "con", "nel", "una", "che", "non", "quando"},
+	"pt": {"o", "os", "as", "e", "do", "da", "para", "com", "em", "uma", "que", "não", "quando"},
+	"nl": {"de", "het", "en", "van", "voor", "met", "een", "op", "niet", "bij", "wordt", "naar"},
+}
+
+// codeSpans matches what is usually identifiers or paths rather than prose
+var codeSpans = regexp.MustCompile("`[^`]*`|\\S+[/._]\\S+|\\S*[a-z][A-Z]\\S*")
+
+// Matches reports whether text looks like it's written in the language. The
+// check is heuristic: Japanese, Chinese and Korean need their script, and
+// languages written in the Latin script need more of their own frequent
+// words than of any other. Text that gives no clue matches.
+func Matches(text, code string) bool {
+	text = codeSpans.ReplaceAllString(text, " ")
+
+	var kana, han, hangul, letters int
+	for _, r := range text {
+		switch {
+		case unicode.In(r, unicode.Hiragana, unicode.Katakana):
+			kana++
+		case unicode.Is(unicode.Han, r):
+			han++
+		case unicode.Is(unicode.Hangul, r):
+			hangul++
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	cjk := kana + han + hangul
+
+	switch code {
+	case "ja":
+		return kana+han >= 2
+	case "zh":
+		return han >= 2 && kana == 0
+	case "ko":
+		return hangul >= 2
+	}
+	// A Latin-script language shouldn't be mostly written in another script
+	if cjk > 0 && cjk*2 >= letters {
+		return false
+	}
+
+	counts := map[string]int{}
+	for _, word := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		for lang, words := range stopwords {
+			for _, w := range words {
+				if word == w {
+					counts[lang]++
+				}
+			}
+		}
+	}
+	for lang, n := range counts {
+		if lang != code && n > counts[code] {
+			return false
+		}
+	}
+	return true
+}
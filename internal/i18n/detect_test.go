@@ -0,0 +1,56 @@
+package i18n
+
+import "testing"
+
+func TestMatches(t *testing.T) {
+	tests := []struct {
+		text string
+		code string
+		want bool
+	}{
+		{"add retries to the provider client and stop when the limit is reached", "en", true},
+		{"add retries to the provider client and stop when the limit is reached", "de", false},
+		{"Wiederholungen für den Client hinzufügen, die bei Fehlern nicht abbrechen", "de", true},
+		{"Wiederholungen für den Client hinzufügen, die bei Fehlern nicht abbrechen", "en", false},
+		{"プロバイダーのクライアントに再試行を追加", "ja", true},
+		{"プロバイダーのクライアントに再試行を追加", "en", false},
+		{"add retries to the provider client", "ja", false},
+		{"为客户端添加重试", "zh", true},
+		{"クライアントに再試行を追加", "zh", false},
+		{"클라이언트에 재시도 추가", "ko", true},
+		// Identifiers and paths aren't prose
+		{"`retryTransport` in internal/llm/retry.go für den Client", "de", true},
+		{"parseRetryAfter und newRetryTransport anpassen", "de", true},
+		{"update parseRetryAfter and newRetryTransport", "de", false},
+		// No clue either way
+		{"bump version", "fr", true},
+		{"", "de", true},
+	}
+	for _, tt := range tests {
+		if got := Matches(tt.text, tt.code); got != tt.want {
+			t.Errorf("Matches(%q, %q) = %v, want %v", tt.text, tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"de":          "de",
+		"DE":          "de",
+		"de_DE.UTF-8": "de",
+		"ja-JP":       "ja",
+		"pt_BR@euro":  "pt",
+		"German":      "de",
+		"deutsch":     "de",
+		"日本語":         "ja",
+		" español ":   "es",
+		"C":           "",
+		"klingon":     "",
+		"":            "",
+	}
+	for lang, want := range tests {
+		if got := Normalize(lang); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", lang, got, want)
+		}
+	}
+}
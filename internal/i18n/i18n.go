@@ -0,0 +1,62 @@
+// Package i18n translates GitR's own interface strings and knows the languages
+// commit messages can be written in.
+//
+// Catalogs map the English text, which is used as the key, to its
+// translation. Text without an entry in the catalog of the selected language
+// is shown in English, so a catalog can be incomplete.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// catalogs holds the translations of the interface per language code
+var catalogs = map[string]map[string]string{
+	"de": catalogDE,
+	"ja": catalogJA,
+}
+
+// current is the catalog of the selected interface language; nil is English
+var current map[string]string
+
+// SetLanguage selects the interface language. Languages without a catalog,
+// and "", select English.
+func SetLanguage(lang string) {
+	current = catalogs[Normalize(lang)]
+}
+
+// Locale returns the language of the user's locale from LC_ALL, LC_MESSAGES
+// or LANG, as for other command line tools; "" when it's unset or C/POSIX
+func Locale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			return Normalize(value)
+		}
+	}
+	return ""
+}
+
+// T returns the translation of text in the interface language
+func T(text string) string {
+	if translated, ok := current[text]; ok {
+		return translated
+	}
+	return text
+}
+
+// Sprintf formats the translation of format
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Printf prints the translation of format to stdout
+func Printf(format string, args ...any) {
+	fmt.Printf(T(format), args...)
+}
+
+// Fprintf prints the translation of format to w
+func Fprintf(w io.Writer, format string, args ...any) {
+	fmt.Fprintf(w, T(format), args...)
+}
@@ -0,0 +1,54 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+var verbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// Translations must keep the format verbs of their key, or Sprintf garbles them
+func TestCatalogVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for key, translated := range catalog {
+			want := verbPattern.FindAllString(key, -1)
+			if got := verbPattern.FindAllString(translated, -1); !slices.Equal(got, want) {
+				t.Errorf("%s: %q has verbs %v, want %v", lang, translated, got, want)
+			}
+		}
+	}
+}
+
+func TestCatalogsHaveTheSameKeys(t *testing.T) {
+	for key := range catalogDE {
+		if _, ok := catalogJA[key]; !ok {
+			t.Errorf("ja: no translation of %q", key)
+		}
+	}
+	for key := range catalogJA {
+		if _, ok := catalogDE[key]; !ok {
+			t.Errorf("de: no translation of %q", key)
+		}
+	}
+}
+
+func TestT(t *testing.T) {
+	defer SetLanguage("")
+
+	SetLanguage("de_DE.UTF-8")
+	if got := T("Commit successful!"); got != "Commit erfolgreich!" {
+		t.Errorf("T = %q", got)
+	}
+	if got := T("not in any catalog"); got != "not in any catalog" {
+		t.Errorf("T of an unknown text = %q, want it unchanged", got)
+	}
+	if got := Sprintf("Saved stash: %s\n", "WIP"); got != "Stash gesichert: WIP\n" {
+		t.Errorf("Sprintf = %q", got)
+	}
+
+	SetLanguage("fr")
+	if got := T("Commit successful!"); got != "Commit successful!" {
+		t.Errorf("T without a catalog = %q, want English", got)
+	}
+}
@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"strings"
+)
+
+// Language is a language commit messages can be written in
+type Language struct {
+	Code   string // ISO 639-1 code, e.g. "de"
+	Name   string // English name, used in the prompt
+	Native string // the language's own name
+}
+
+// Languages lists the supported languages
+var Languages = []Language{
+	{Code: "en", Name: "English", Native: "English"},
+	{Code: "de", Name: "German", Native: "Deutsch"},
+	{Code: "ja", Name: "Japanese", Native: "日本語"},
+	{Code: "fr", Name: "French", Native: "Français"},
+	{Code: "es", Name: "Spanish", Native: "Español"},
+	{Code: "it", Name: "Italian", Native: "Italiano"},
+	{Code: "pt", Name: "Portuguese", Native: "Português"},
+	{Code: "nl", Name: "Dutch", Native: "Nederlands"},
+	{Code: "zh", Name: "Chinese", Native: "中文"},
+	{Code: "ko", Name: "Korean", Native: "한국어"},
+}
+
+// Codes returns the codes of the supported languages
+func Codes() []string {
+	codes := make([]string, len(Languages))
+	for i, l := range Languages {
+		codes[i] = l.Code
+	}
+	return codes
+}
+
+// Normalize returns the code of a language given as a code, a locale such as
+// "de_DE.UTF-8" or "ja-JP", or an English or native name; "" if it's unknown
+func Normalize(lang string) string {
+	lang = strings.ToLower(strings.TrimSpace(lang))
+	if lang == "" {
+		return ""
+	}
+	code := lang
+	if i := strings.IndexAny(code, "_-.@"); i > 0 {
+		code = code[:i]
+	}
+	for _, l := range Languages {
+		if code == l.Code || lang == strings.ToLower(l.Name) || lang == strings.ToLower(l.Native) {
+			return l.Code
+		}
+	}
+	return ""
+}
+
+// Name returns the English name of a language code, or the code itself if it's unknown
+func Name(code string) string {
+	for _, l := range Languages {
+		if l.Code == code {
+			return l.Name
+		}
+	}
+	return code
+}
@@ -0,0 +1,229 @@
+package ledger
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gitr/internal/config"
+)
+
+// builtinPrices are list prices in USD per million tokens at the time of
+// writing. Entries in the <pricing> section override them.
+var builtinPrices = []config.ModelPrice{
+	{Name: "gpt-5", Prompt: 1.25, Completion: 10},
+	{Name: "gpt-5-mini", Prompt: 0.25, Completion: 2},
+	{Name: "gpt-5-nano", Prompt: 0.05, Completion: 0.40},
+	{Name: "gpt-4.1", Prompt: 2, Completion: 8},
+	{Name: "gpt-4.1-mini", Prompt: 0.40, Completion: 1.60},
+	{Name: "gpt-4.1-nano", Prompt: 0.10, Completion: 0.40},
+	{Name: "gpt-4o", Prompt: 2.50, Completion: 10},
+	{Name: "gpt-4o-mini", Prompt: 0.15, Completion: 0.60},
+	{Name: "gpt-4-turbo", Prompt: 10, Completion: 30},
+	{Name: "gpt-4", Prompt: 30, Completion: 60},
+	{Name: "gpt-3.5-turbo", Prompt: 0.50, Completion: 1.50},
+	{Name: "o3-mini", Prompt: 1.10, Completion: 4.40},
+	{Name: "o4-mini", Prompt: 1.10, Completion: 4.40},
+}
+
+// PriceFor returns the price of a model: the configured entry with the
+// longest matching name prefix, else the built-in one
+func PriceFor(model string, pricing config.PricingConfig) (config.ModelPrice, bool) {
+	if price, ok := matchPrice(model, pricing.Models); ok {
+		return price, true
+	}
+	return matchPrice(model, builtinPrices)
+}
+
+func matchPrice(model string, prices []config.ModelPrice) (config.ModelPrice, bool) {
+	name := strings.ToLower(model)
+	var best config.ModelPrice
+	found := false
+	for _, price := range prices {
+		prefix := strings.ToLower(price.Name)
+		if prefix != "" && strings.HasPrefix(name, prefix) && len(prefix) > len(best.Name) {
+			best, found = price, true
+		}
+	}
+	return best, found
+}
+
+// Cost returns the price of a request in USD
+func Cost(price config.ModelPrice, promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*price.Prompt + float64(completionTokens)*price.Completion) / 1e6
+}
+
+// Totals are the accumulated requests, tokens and cost of one model in one month
+type Totals struct {
+	Month            string  `json:"-"`
+	Model            string  `json:"-"`
+	Requests         int     `json:"requests"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	CostUSD          float64 `json:"cost_usd"`
+	// Unpriced counts requests for which no price was known
+	Unpriced int `json:"unpriced,omitempty"`
+}
+
+// book is the usage file: month (YYYY-MM) -> model -> totals
+type book map[string]map[string]*Totals
+
+// Path returns the location of the usage file
+func Path() (string, error) {
+	dir, err := config.DataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "usage.json"), nil
+}
+
+// Lock timing for the usage file
+const (
+	// lockTimeout is how long Record waits for another run to finish
+	lockTimeout = 5 * time.Second
+	// staleLock is the age after which a lock was left behind by a run that crashed
+	staleLock = 30 * time.Second
+)
+
+// Record adds a request to the totals of the current month. cost is nil
+// when the price of the model isn't known. Concurrent runs, e.g. from hooks
+// and editors, take turns through a lock file.
+func Record(model string, promptTokens, completionTokens int, cost *float64) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	unlock, err := lock(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	entries, err := load(path)
+	if err != nil {
+		return err
+	}
+
+	month := time.Now().Format("2006-01")
+	if entries[month] == nil {
+		entries[month] = map[string]*Totals{}
+	}
+	totals := entries[month][model]
+	if totals == nil {
+		totals = &Totals{}
+		entries[month][model] = totals
+	}
+	totals.Requests++
+	totals.PromptTokens += promptTokens
+	totals.CompletionTokens += completionTokens
+	if cost != nil {
+		totals.CostUSD += *cost
+	} else {
+		totals.Unpriced++
+	}
+
+	return save(path, entries)
+}
+
+// Months returns the recorded totals per month and model, newest month first
+func Months() ([]Totals, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := load(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var months []Totals
+	for month, models := range entries {
+		for model, totals := range models {
+			t := *totals
+			t.Month, t.Model = month, model
+			months = append(months, t)
+		}
+	}
+	sort.Slice(months, func(i, j int) bool {
+		if months[i].Month != months[j].Month {
+			return months[i].Month > months[j].Month
+		}
+		return months[i].Model < months[j].Model
+	})
+	return months, nil
+}
+
+// load reads the usage file; a missing file is empty
+func load(path string) (book, error) {
+	entries := book{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return entries, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// save writes the usage file through a temporary file, so it's never half written
+func save(path string, entries book) error {
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".usage-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// lock creates the lock file of path, waiting while another run holds it, and
+// returns the function that removes it. A lock file works the same on every
+// platform, unlike flock.
+func lock(path string) (func(), error) {
+	lockPath := path + ".lock"
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLock {
+			os.Remove(lockPath)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("%s is locked by another run; remove %s if none is running", path, lockPath)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,91 @@
+package ledger
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"gitr/internal/config"
+)
+
+func TestRecordConcurrently(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+
+	const runs = 20
+	cost := 0.01
+	var wg sync.WaitGroup
+	errs := make(chan error, runs)
+	for i := 0; i < runs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- Record("gpt-4o-mini", 100, 10, &cost)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	months, err := Months()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(months) != 1 {
+		t.Fatalf("months = %+v, want one entry", months)
+	}
+	got := months[0]
+	if got.Requests != runs || got.PromptTokens != runs*100 || got.CompletionTokens != runs*10 {
+		t.Errorf("totals = %+v, want %d requests", got, runs)
+	}
+
+	path, _ := Path()
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind: %v", err)
+	}
+}
+
+func TestStaleLock(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	path, err := Path()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * staleLock)
+	os.WriteFile(path+".lock", nil, 0600)
+	os.Chtimes(path+".lock", old, old)
+
+	if err := Record("m", 1, 1, nil); err != nil {
+		t.Fatalf("Record with a stale lock: %v", err)
+	}
+}
+
+func TestPriceFor(t *testing.T) {
+	pricing := config.PricingConfig{Models: []config.ModelPrice{{Name: "gpt-4o", Prompt: 1, Completion: 2}}}
+	tests := []struct {
+		model  string
+		prompt float64
+		ok     bool
+	}{
+		{"gpt-4.1-mini-2025-04-14", 0.40, true}, // the longest built-in prefix wins
+		{"gpt-4o-mini-2024-07-18", 1, true},     // configured entries override built-in ones
+		{"llama3", 0, false},
+	}
+	for _, tt := range tests {
+		price, ok := PriceFor(tt.model, pricing)
+		if ok != tt.ok || price.Prompt != tt.prompt {
+			t.Errorf("PriceFor(%q) = %+v, %v; want prompt %v, %v", tt.model, price, ok, tt.prompt, tt.ok)
+		}
+	}
+	if got := Cost(config.ModelPrice{Prompt: 2, Completion: 8}, 1_000_000, 500_000); got != 6 {
+		t.Errorf("Cost = %v, want 6", got)
+	}
+}
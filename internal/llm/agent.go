@@ -0,0 +1,220 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"gitr/internal/config"
+	"gitr/internal/git"
+)
+
+// Trace reports tool calls in agent mode. It does nothing unless the caller
+// enables it, e.g. for --verbose.
+var Trace = func(format string, args ...any) {}
+
+// agentInstruction is added to the system prompt in agent mode
+const agentInstruction = `
+You can call read-only tools to look at the repository: read a file at HEAD or in the index, list a directory, show the git log of a path and search the tracked files. Use them only when the diff alone doesn't show what a change is for, then reply with the commit message only.`
+
+// Limits for the output of a single tool call
+const (
+	maxToolOutput = 16 * 1024
+	maxGrepLines  = 50
+	maxLogEntries = 20
+)
+
+var revisionParam = &schema.ParameterInfo{
+	Type: schema.String,
+	Desc: "HEAD for the last commit (default) or index for the staged version",
+	Enum: []string{git.RevisionHead, git.RevisionIndex},
+}
+
+// agentTools describes the tools offered to the model
+var agentTools = []*schema.ToolInfo{
+	{
+		Name: "read_file",
+		Desc: "Read a file of the repository as it is at HEAD or in the index (staged). Long files are cut off.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"path":     {Type: schema.String, Desc: "Path relative to the repository root", Required: true},
+			"revision": revisionParam,
+		}),
+	},
+	{
+		Name: "list_directory",
+		Desc: "List the files and subdirectories (ending in /) of a directory at HEAD or in the index.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"path":     {Type: schema.String, Desc: "Directory relative to the repository root; empty for the root"},
+			"revision": revisionParam,
+		}),
+	},
+	{
+		Name: "git_log",
+		Desc: "Show recent commits (hash, date, subject) that touched a path, newest first.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"path":  {Type: schema.String, Desc: "File or directory relative to the repository root; empty for the whole repository"},
+			"count": {Type: schema.Integer, Desc: fmt.Sprintf("Number of commits, at most %d (default 10)", maxLogEntries)},
+		}),
+	},
+	{
+		Name: "grep",
+		Desc: "Search the tracked files (staged version) for an extended regular expression. Returns path:line:text matches.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"pattern": {Type: schema.String, Desc: "Extended regular expression", Required: true},
+			"path":    {Type: schema.String, Desc: "Limit the search to this file or directory"},
+		}),
+	},
+}
+
+// toolArgs are the arguments of any of the agent tools
+type toolArgs struct {
+	Path     string `json:"path"`
+	Revision string `json:"revision"`
+	Count    int    `json:"count"`
+	Pattern  string `json:"pattern"`
+}
+
+// generateWithTools lets the model call the agent tools until it answers. When
+// the call or time limit is reached, the model has to answer without tools; a
+// tool still running at the time limit is stopped. The returned usage adds up
+// all requests.
+func generateWithTools(ctx context.Context, chatModel model.ToolCallingChatModel, messages []*schema.Message, agent config.AgentConfig) (*schema.Message, *Usage, error) {
+	toolModel, err := chatModel.WithTools(agentTools)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	messages = slices.Clone(messages)
+	deadline := time.Now().Add(agent.Timeout())
+	toolCtx, cancel := context.WithDeadline(ctx, deadline)
+	defer cancel()
+	limit := agent.ToolCallLimit()
+	calls := 0
+
+	var usage *Usage
+	for {
+		var opts []model.Option
+		if calls >= limit || time.Now().After(deadline) {
+			Trace("Tool budget used (%d call(s)), asking for the message\n", calls)
+			messages = append(messages, schema.UserMessage("No more tool calls are available. Reply with the commit message now."))
+			opts = append(opts, model.WithToolChoice(schema.ToolChoiceForbidden))
+		}
+
+		result, err := toolModel.Generate(ctx, messages, opts...)
+		if err != nil {
+			return nil, nil, err
+		}
+		usage = addUsage(usage, responseUsage(result))
+		if len(result.ToolCalls) == 0 || len(opts) > 0 {
+			return result, usage, nil
+		}
+
+		messages = append(messages, result)
+		for _, call := range result.ToolCalls {
+			calls++
+			var output string
+			switch {
+			case calls > limit:
+				output = "Error: tool call limit reached"
+			case toolCtx.Err() != nil:
+				output = "Error: time limit reached"
+			default:
+				start := time.Now()
+				output = runTool(toolCtx, call.Function.Name, call.Function.Arguments)
+				Trace("Tool %s(%s): %d bytes in %s\n", call.Function.Name, call.Function.Arguments,
+					len(output), time.Since(start).Round(time.Millisecond))
+			}
+			messages = append(messages, schema.ToolMessage(output, call.ID))
+		}
+	}
+}
+
+// runTool runs one tool call; errors are returned as text for the model.
+// The git command of the tool is stopped when ctx is done.
+func runTool(ctx context.Context, name, arguments string) string {
+	var args toolArgs
+	if strings.TrimSpace(arguments) != "" {
+		if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+			return fmt.Sprintf("Error: invalid arguments: %v", err)
+		}
+	}
+
+	path, err := git.CleanPath(args.Path)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	var output string
+	switch name {
+	case "read_file":
+		if path == "" {
+			return "Error: path is required"
+		}
+		output, err = git.ShowFile(ctx, args.Revision, path)
+	case "list_directory":
+		var entries []string
+		entries, err = git.ListDirectory(ctx, args.Revision, path)
+		output = strings.Join(entries, "\n")
+	case "git_log":
+		count := args.Count
+		if count <= 0 {
+			count = 10
+		}
+		output, err = git.Log(ctx, path, min(count, maxLogEntries))
+	case "grep":
+		if args.Pattern == "" {
+			return "Error: pattern is required"
+		}
+		output, err = git.Grep(ctx, args.Pattern, path, maxGrepLines)
+		if err == nil && output == "" {
+			output = "No matches"
+		}
+	default:
+		return fmt.Sprintf("Error: unknown tool %q", name)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "Error: time limit reached, the tool was stopped"
+	}
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+
+	if len(output) > maxToolOutput {
+		output = truncate(output, maxToolOutput) + "\n... (cut off)"
+	}
+	return output
+}
+
+// truncate shortens text to at most limit bytes without splitting a rune
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit]
+}
+
+// addUsage adds up the token counts of two requests; nil counts as unknown
+func addUsage(total, usage *Usage) *Usage {
+	if usage == nil {
+		return total
+	}
+	if total == nil {
+		return &Usage{PromptTokens: usage.PromptTokens, CompletionTokens: usage.CompletionTokens, TotalTokens: usage.TotalTokens}
+	}
+	return &Usage{
+		PromptTokens:     total.PromptTokens + usage.PromptTokens,
+		CompletionTokens: total.CompletionTokens + usage.CompletionTokens,
+		TotalTokens:      total.TotalTokens + usage.TotalTokens,
+	}
+}
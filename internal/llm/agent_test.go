@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"gitr/internal/git"
+	"gitr/internal/gitfixture"
+)
+
+// slowGrep runs git, except for git grep, which hangs
+type slowGrep struct{ git.Runner }
+
+func (r slowGrep) Command(args ...string) *exec.Cmd {
+	if len(args) > 0 && args[0] == "grep" {
+		return exec.Command("sleep", "30")
+	}
+	return r.Runner.Command(args...)
+}
+
+func TestRunToolStopsAtDeadline(t *testing.T) {
+	repo := gitfixture.New(t)
+	repo.Commit("initial", map[string]string{"main.go": "package main\n"})
+	repo.Enter()
+	git.SetRunner(slowGrep{repo.Runner()})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	output := runTool(ctx, "grep", `{"pattern":"main"}`)
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("grep ran for %s after the deadline", elapsed)
+	}
+	if !strings.Contains(output, "time limit reached") {
+		t.Errorf("output = %q, want a time limit error", output)
+	}
+
+	// Tools that run in time aren't affected
+	output = runTool(context.Background(), "read_file", `{"path":"main.go"}`)
+	if output != "package main\n" {
+		t.Errorf("read_file = %q", output)
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		text  string
+		limit int
+		want  string
+	}{
+		{"hello", 10, "hello"},
+		{"hello", 5, "hello"},
+		{"hello", 3, "hel"},
+		{"日本語", 4, "日"},
+		{"日本語", 6, "日本"},
+		{"añb", 2, "a"},
+		{"日", 2, ""},
+	}
+	for _, tt := range tests {
+		got := truncate(tt.text, tt.limit)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("truncate(%q, %d) = %q, want %q", tt.text, tt.limit, got, tt.want)
+		}
+	}
+}
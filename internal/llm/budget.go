@@ -0,0 +1,92 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+
+	"gitr/internal/config"
+	"gitr/internal/git"
+	"gitr/internal/ledger"
+	"gitr/internal/tokens"
+)
+
+// defaultReplyTokens is kept free for the answer when max_tokens isn't set
+const defaultReplyTokens = 1024
+
+// summaryNote tells the model that it's looking at a shortened diff
+const summaryNote = "(The diff was too large for the model and has been summarized: long hunks are cut and some files may only be listed.)\n\n"
+
+// promptTokens estimates the size of the prompt for the given model
+func promptTokens(messages []*schema.Message, model string) int {
+	contents := make([]string, len(messages))
+	for i, message := range messages {
+		contents[i] = message.Content
+	}
+	return tokens.CountMessages(contents, model)
+}
+
+// fitContextWindow checks the prompt against the context window of a
+// profile's model before it's sent to it. A prompt that doesn't leave room for
+// the answer has its diff summarized, is sent with a warning, or fails, per
+// tokens.on_overflow. Each profile of the chain is fitted separately, since a
+// fallback model may have a smaller window. Without a diff the prompt is sent
+// as it is.
+func fitContextWindow(cfg *config.Config, settings *config.OpenAIConfig, messages []*schema.Message, diff string) ([]*schema.Message, error) {
+	if diff == "" {
+		return messages, nil
+	}
+	window := settings.ContextWindow
+	if window == 0 {
+		window = tokens.ContextWindow(settings.Model)
+	}
+	reply := defaultReplyTokens
+	if settings.MaxTokens != nil {
+		reply = *settings.MaxTokens
+	}
+	available := window - reply
+
+	size := promptTokens(messages, settings.Model)
+	if size <= available {
+		return messages, nil
+	}
+
+	switch cfg.Tokens.Overflow() {
+	case config.OverflowFail:
+		return nil, fmt.Errorf("prompt is about %d tokens but %s accepts %d (context window %d minus %d for the answer); stage fewer changes or set tokens.on_overflow to summarize",
+			size, settings.Model, available, window, reply)
+	case config.OverflowWarn:
+		Notify("Warning: prompt is about %d tokens, more than the %d that %s accepts\n", size, available, settings.Model)
+		return messages, nil
+	}
+
+	// Whatever the rest of the prompt leaves over goes to the diff
+	user := messages[len(messages)-1]
+	budget := available - (size - tokens.Count(diff, settings.Model)) - tokens.Count(summaryNote, settings.Model)
+	summary := git.SummarizeDiff(diff, func(text string) bool {
+		return tokens.Count(text, settings.Model) <= budget
+	})
+	Notify("Prompt is about %d tokens, more than the %d that %s accepts; sending a summary of the diff\n",
+		size, available, settings.Model)
+
+	summarized := append([]*schema.Message{}, messages...)
+	shortened := *user
+	shortened.Content = strings.Replace(user.Content, diff, summaryNote+summary, 1)
+	summarized[len(summarized)-1] = &shortened
+	return summarized, nil
+}
+
+// recordUsage prices a response and adds it to the monthly totals
+func recordUsage(cfg *config.Config, result *Result) {
+	if result.Usage == nil {
+		return
+	}
+	if price, ok := ledger.PriceFor(result.Model, cfg.Pricing); ok {
+		cost := ledger.Cost(price, result.Usage.PromptTokens, result.Usage.CompletionTokens)
+		result.Cost = &cost
+	}
+	if err := ledger.Record(result.Model, result.Usage.PromptTokens, result.Usage.CompletionTokens, result.Cost); err != nil {
+		Notify("Warning: failed to record usage: %v\n", err)
+	}
+}
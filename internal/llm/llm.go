@@ -2,7 +2,11 @@ package llm
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/cloudwego/eino-ext/components/model/openai"
@@ -10,18 +14,64 @@ import (
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/schema"
 
+	"gitr/internal/cache"
 	"gitr/internal/config"
+	"gitr/internal/i18n"
 )
 
-// GenerateCommitMessage generates a commit message using OpenAI
-func GenerateCommitMessage(cfg *config.Config, stagedChanges string) (string, error) {
-	ctx := context.Background()
+// Result holds a generated response and the provider that produced it
+type Result struct {
+	Content string
+	Profile string
+	Model   string
+	Cached  bool
+	// Usage is reported by the provider; nil for cached responses or when the provider omits it
+	Usage *Usage
+	// Latency is the time spent producing the response, including retries and fallbacks
+	Latency time.Duration
+	// Cost is the price of the request in USD; nil when the usage or the model's price is unknown
+	Cost *float64
+	// Heuristic is set when the message was built by rules from the changed files, not by a model
+	Heuristic bool
+}
 
-	// Create OpenAI chat model
-	chatModel, err := createOpenAIModel(ctx, &cfg.OpenAI)
-	if err != nil {
-		return "", err
-	}
+// Profile and model names reported for heuristic messages
+const (
+	HeuristicProfile = "offline"
+	HeuristicModel   = "heuristic"
+)
+
+// Usage holds the token counts of a request
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// Options controls a single generation request
+type Options struct {
+	// NoCache skips the response cache lookup (the fresh response is still stored)
+	NoCache bool
+	// Instruction is extra guidance from the user, e.g. when regenerating
+	Instruction string
+	// Breaking lists changes that break the public API; the model is told to
+	// mark the message as breaking
+	Breaking []string
+	// Notes describe what the staged changes combine, e.g. the commits of a merge
+	Notes string
+}
+
+// Notify reports retries and fallbacks to the user. It writes to stderr by default.
+var Notify = func(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format, args...)
+}
+
+// GenerateCommitMessage generates a commit message using OpenAI.
+// Requests are retried on 429/5xx/timeouts and, if the active profile keeps
+// failing, the configured fallback profiles are tried in order.
+func GenerateCommitMessage(cfg *config.Config, stagedChanges string, opts Options) (*Result, error) {
+	ctx := context.Background()
+	start := time.Now()
 
 	// Create prompt template for commit message generation
 	template := createCommitMessageTemplate(cfg)
@@ -43,25 +93,218 @@ func GenerateCommitMessage(cfg *config.Config, stagedChanges string) (string, er
 	})
 
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	if cfg.Agent.Enabled {
+		messages[0].Content += agentInstruction
+	}
+	messages[0].Content += commitLanguageInstruction(cfg)
+	messages[0].Content += opts.Notes
+	if len(opts.Breaking) > 0 {
+		messages[0].Content += breakingInstruction(cfg, opts.Breaking)
+	}
+	if opts.Instruction != "" {
+		messages[0].Content += "\nAdditional instruction from the user: " + opts.Instruction
+	}
+
+	// Identical diff, prompt, model and parameters give an identical request
+	var responseCache *cache.Cache
+	var cacheKey string
+	if cfg.Cache.IsEnabled() {
+		responseCache, err = cache.New(cfg.Cache)
+		if err != nil {
+			Notify("Warning: response cache unavailable: %v\n", err)
+		} else {
+			cacheKey = cache.Key(cache.NormalizeDiff(stagedChanges), messages[0].Content, profileFingerprint(cfg.Active()))
+		}
+	}
+
+	if responseCache != nil && !opts.NoCache {
+		if entry, ok := responseCache.Get(cacheKey); ok {
+			Notify("Using cached response from %s ago (profile %q). Use --no-cache to regenerate.\n",
+				time.Since(entry.CreatedAt).Round(time.Second), entry.Profile)
+			return &Result{
+				Content: entry.Content,
+				Profile: entry.Profile,
+				Model:   entry.Model,
+				Cached:  true,
+				Latency: time.Since(start),
+			}, nil
+		}
+	}
+
+	result, err := generateWithFallback(ctx, cfg, messages, stagedChanges)
+	if err != nil {
+		return nil, err
+	}
+	result.Latency = time.Since(start)
+	recordUsage(cfg, result)
+
+	// The key describes the active profile, so answers of a fallback profile
+	// aren't cached; they would come back later as the active model's answer
+	if responseCache != nil && result.Profile == cfg.ActiveProfileName() {
+		err = responseCache.Put(cache.Entry{
+			Key:     cacheKey,
+			Content: result.Content,
+			Profile: result.Profile,
+			Model:   result.Model,
+		})
+		if err != nil {
+			Notify("Warning: failed to cache response: %v\n", err)
+		}
 	}
 
-	// Generate response
-	result, err := chatModel.Generate(ctx, messages)
+	return result, nil
+}
+
+// Complete sends a system and a user prompt through the profile chain and
+// returns the answer. It's used for everything other than commit messages,
+// so agent mode and the response cache don't apply.
+func Complete(cfg *config.Config, system, user string) (*Result, error) {
+	return CompleteDiff(cfg, system, user, "")
+}
+
+// CompleteDiff is Complete for a user prompt that contains diff. A prompt
+// over the context window has the diff summarized, as for commit messages.
+func CompleteDiff(cfg *config.Config, system, user, diff string) (*Result, error) {
+	start := time.Now()
+	plain := *cfg
+	plain.Agent.Enabled = false
+
+	messages := []*schema.Message{schema.SystemMessage(system), schema.UserMessage(user)}
+	result, err := generateWithFallback(context.Background(), &plain, messages, diff)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	result.Latency = time.Since(start)
+	recordUsage(cfg, result)
+	return result, nil
+}
+
+// profileFingerprint describes the settings that influence a model response
+func profileFingerprint(cfg *config.OpenAIConfig) string {
+	return fmt.Sprintf("%s|%s|%s|%v|%v|%v|%q|%v|%v|%v",
+		cfg.Provider, cfg.BaseURL, cfg.Model,
+		derefInt(cfg.MaxTokens), derefFloat(cfg.Temperature), derefFloat(cfg.TopP),
+		cfg.Stop, derefFloat(cfg.PresencePenalty), derefFloat(cfg.FrequencyPenalty), derefInt(cfg.Seed))
+}
+
+func derefInt(v *int) any {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
+func derefFloat(v *float32) any {
+	if v == nil {
+		return nil
 	}
+	return *v
+}
+
+// generateWithFallback sends the messages to each profile of the chain in turn
+// until one of them answers. The diff in the last message is fitted to each
+// profile's context window.
+func generateWithFallback(ctx context.Context, cfg *config.Config, prompt []*schema.Message, diff string) (*Result, error) {
+	chain := cfg.ProfileChain()
+
+	var errs []error
+	for i, name := range chain {
+		settings, err := cfg.Profile(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if i > 0 {
+			Notify("Falling back to profile %q (%s)\n", name, settings.Model)
+		}
+
+		messages, err := fitContextWindow(cfg, settings, prompt, diff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		chatModel, err := NewChatModel(ctx, settings, cfg.Retry, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		var result *schema.Message
+		var usage *Usage
+		if cfg.Agent.Enabled {
+			result, usage, err = generateWithTools(ctx, chatModel, messages, cfg.Agent)
+		} else {
+			result, err = chatModel.Generate(ctx, messages)
+			if err == nil {
+				usage = responseUsage(result)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			if ctx.Err() != nil {
+				break
+			}
+			if i < len(chain)-1 {
+				Notify("Profile %q failed: %v\n", name, err)
+			}
+			continue
+		}
+
+		if i > 0 {
+			Notify("Answered by fallback profile %q (%s)\n", name, settings.Model)
+		}
+		if result.ResponseMeta != nil && result.ResponseMeta.FinishReason == "length" {
+			Notify("Warning: the answer was cut off at max_tokens; raise max_tokens of profile %q\n", name)
+		}
+
+		return &Result{
+			Content: result.Content,
+			Profile: name,
+			Model:   settings.Model,
+			Usage:   usage,
+		}, nil
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("all profiles failed: %w", errors.Join(errs...))
+}
 
-	return result.Content, nil
+// responseUsage extracts the token usage reported with a response
+func responseUsage(message *schema.Message) *Usage {
+	if message.ResponseMeta == nil || message.ResponseMeta.Usage == nil {
+		return nil
+	}
+	usage := message.ResponseMeta.Usage
+	return &Usage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+	}
 }
 
+// ModelFactory creates the chat model of a profile; label names the profile in notices
+type ModelFactory func(ctx context.Context, cfg *config.OpenAIConfig, retry config.RetryConfig, label string) (model.ToolCallingChatModel, error)
+
+// NewChatModel creates the chat model for each profile that's tried. It can
+// be replaced with a scripted model, e.g. to run without a provider.
+var NewChatModel ModelFactory = createOpenAIModel
+
 // createOpenAIModel creates an OpenAI chat model
-func createOpenAIModel(ctx context.Context, cfg *config.OpenAIConfig) (model.ChatModel, error) {
+func createOpenAIModel(ctx context.Context, cfg *config.OpenAIConfig, retry config.RetryConfig, label string) (model.ToolCallingChatModel, error) {
 	// Use API key from config, fallback to environment variable
 	apiKey := cfg.APIKey
 	if apiKey == "" {
 		apiKey = os.Getenv("OPENAI_API_KEY")
 	}
+	if apiKey == "" && cfg.IsLocal() {
+		// Local servers ignore the key, but the client always sends one
+		apiKey = "local"
+	}
 
 	// Set default timeout if not specified
 	timeout := time.Duration(cfg.Timeout) * time.Second
@@ -77,13 +320,16 @@ func createOpenAIModel(ctx context.Context, cfg *config.OpenAIConfig) (model.Cha
 
 	return openai.NewChatModel(ctx, &openai.ChatModelConfig{
 		// Azure OpenAI Service configuration (optional)
-		ByAzure:    cfg.ByAzure,
+		ByAzure:    cfg.IsAzure(),
 		BaseURL:    cfg.BaseURL,
 		APIVersion: cfg.APIVersion,
 
 		// Basic configuration
-		APIKey:  apiKey,
-		Timeout: timeout,
+		APIKey: apiKey,
+		// The retry transport applies the timeout to each attempt
+		HTTPClient: &http.Client{
+			Transport: newRetryTransport(retry, timeout, label, Notify),
+		},
 
 		// Model parameters
 		Model:            cfg.Model,
@@ -113,3 +359,46 @@ func createCommitMessageTemplate(cfg *config.Config) *prompt.DefaultChatTemplate
 		schema.UserMessage("Please generate a commit message for the following staged changes:\n\n{staged_changes}"),
 	)
 }
+
+// LanguageInstruction tells the model to answer in the configured language;
+// it's empty when none is set
+func LanguageInstruction(cfg *config.Config) string {
+	code := i18n.Normalize(cfg.CommitTemplate.Language)
+	if code == "" {
+		return ""
+	}
+	return fmt.Sprintf("\nWrite your answer in %s.", i18n.Name(code))
+}
+
+// commitLanguageInstruction is LanguageInstruction for commit messages,
+// whose Conventional Commit types stay in English so tools can parse them
+func commitLanguageInstruction(cfg *config.Config) string {
+	instruction := LanguageInstruction(cfg)
+	if instruction == "" || !strings.EqualFold(cfg.CommitTemplate.Style, "conventional") {
+		return instruction
+	}
+	return instruction + " Keep the Conventional Commit type (feat, fix, docs, refactor, ...), the scope and footer tokens such as BREAKING CHANGE in English; only the description, body and footer values are translated."
+}
+
+// maxBreakingChanges is how many API changes the prompt lists
+const maxBreakingChanges = 15
+
+// breakingInstruction tells the model that the change breaks the public API
+func breakingInstruction(cfg *config.Config, changes []string) string {
+	var b strings.Builder
+	b.WriteString("\nThis change breaks the public API:\n")
+	for i, change := range changes {
+		if i == maxBreakingChanges {
+			fmt.Fprintf(&b, "- and %d more\n", len(changes)-maxBreakingChanges)
+			break
+		}
+		b.WriteString("- " + change + "\n")
+	}
+	if strings.EqualFold(cfg.CommitTemplate.Style, "conventional") {
+		b.WriteString("Mark the commit as breaking: put ! before the colon of the header and ")
+	} else {
+		b.WriteString("Mark the commit as breaking: ")
+	}
+	b.WriteString("end the message with a BREAKING CHANGE: footer telling users what they have to change.")
+	return b.String()
+}
@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitr/internal/config"
+	"gitr/internal/fakeopenai"
+)
+
+func TestFallbackAnswersAreNotCached(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	previous := Notify
+	Notify = func(string, ...any) {}
+	defer func() { Notify = previous }()
+
+	primary := fakeopenai.New()
+	primary.Default = &fakeopenai.Reply{Status: 503}
+	defer primary.Close()
+	backup := fakeopenai.New(fakeopenai.Reply{Content: "fix: from backup"})
+	defer backup.Close()
+
+	cfg := &config.Config{
+		OpenAI:         config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: primary.URL, Model: "primary-model"},
+		Profiles:       []config.Profile{{Name: "backup", OpenAI: config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: backup.URL, Model: "backup-model"}}},
+		Fallback:       []string{"backup"},
+		Retry:          config.RetryConfig{MaxAttempts: 1},
+		CommitTemplate: config.CommitTemplateConfig{Style: "conventional", MaxLength: 72},
+	}
+
+	result, err := GenerateCommitMessage(cfg, "diff --git a/x b/x", Options{})
+	if err != nil || result.Profile != "backup" {
+		t.Fatalf("first run = %+v, %v; want the backup's answer", result, err)
+	}
+
+	// Once the active profile is back, it answers instead of the cache
+	primary.Default = &fakeopenai.Reply{Content: "feat: from primary"}
+	result, err = GenerateCommitMessage(cfg, "diff --git a/x b/x", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Cached || result.Content != "feat: from primary" || result.Model != "primary-model" {
+		t.Errorf("second run = %+v, want a fresh answer of the active profile", result)
+	}
+
+	// Its answer is cached
+	result, err = GenerateCommitMessage(cfg, "diff --git a/x b/x", Options{})
+	if err != nil || !result.Cached || result.Model != "primary-model" {
+		t.Errorf("third run = %+v, %v; want the cached answer of the active profile", result, err)
+	}
+}
+
+func TestFallbackPromptFitsItsContextWindow(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	previous := Notify
+	Notify = func(string, ...any) {}
+	defer func() { Notify = previous }()
+
+	primary := fakeopenai.New()
+	primary.Default = &fakeopenai.Reply{Status: 503}
+	defer primary.Close()
+	backup := fakeopenai.New(fakeopenai.Reply{Content: "feat: add lines"})
+	defer backup.Close()
+
+	var diff strings.Builder
+	diff.WriteString("diff --git a/x.txt b/x.txt\n--- a/x.txt\n+++ b/x.txt\n@@ -0,0 +1,1000 @@\n")
+	for i := range 1000 {
+		fmt.Fprintf(&diff, "+line %d of the new file\n", i)
+	}
+
+	disabled := false
+	cfg := &config.Config{
+		OpenAI:         config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: primary.URL, Model: "primary-model", ContextWindow: 100000},
+		Profiles:       []config.Profile{{Name: "backup", OpenAI: config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: backup.URL, Model: "backup-model", ContextWindow: 3000}}},
+		Fallback:       []string{"backup"},
+		Retry:          config.RetryConfig{MaxAttempts: 1},
+		Cache:          config.CacheConfig{Enabled: &disabled},
+		CommitTemplate: config.CommitTemplateConfig{Style: "conventional", MaxLength: 72},
+	}
+
+	result, err := GenerateCommitMessage(cfg, diff.String(), Options{})
+	if err != nil || result.Profile != "backup" {
+		t.Fatalf("result = %+v, %v; want the backup's answer", result, err)
+	}
+	sent, _ := primary.LastRequest()
+	if content := sent.Messages[len(sent.Messages)-1].Content; strings.Contains(content, summaryNote) || !strings.Contains(content, "+line 999 ") {
+		t.Errorf("the active profile got a summary, want the whole diff")
+	}
+	sent, _ = backup.LastRequest()
+	if content := sent.Messages[len(sent.Messages)-1].Content; !strings.Contains(content, summaryNote) {
+		t.Errorf("the backup profile got the whole diff, want it summarized for its window")
+	}
+
+	// A fallback that can't take the prompt fails on its own
+	cfg.Tokens.OnOverflow = config.OverflowFail
+	_, err = GenerateCommitMessage(cfg, diff.String(), Options{})
+	if err == nil || !strings.Contains(err.Error(), "backup: prompt is about") {
+		t.Errorf("err = %v, want the backup profile's overflow", err)
+	}
+	if got := len(primary.Requests()); got != 2 {
+		t.Errorf("primary requests = %d, want the prompt sent to it again", got)
+	}
+}
@@ -0,0 +1,231 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gitr/internal/config"
+)
+
+// Retry defaults used when the configuration leaves a value unset
+const (
+	defaultMaxAttempts    = 3
+	defaultInitialBackoff = 500 * time.Millisecond
+	defaultMaxBackoff     = 8 * time.Second
+
+	// maxRetryAfter caps how long a Retry-After header may make us wait
+	maxRetryAfter = 60 * time.Second
+)
+
+// retryTransport is an http.RoundTripper that retries requests failing with
+// 429, 5xx or timeouts using exponential backoff with jitter
+type retryTransport struct {
+	base           http.RoundTripper
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	attemptTimeout time.Duration
+
+	// label identifies the provider in retry notices
+	label string
+	// notify reports retries to the user
+	notify func(format string, args ...any)
+	// sleep waits for the given duration or until the context is done
+	sleep func(ctx context.Context, d time.Duration) error
+	// jitter returns a random duration in [0, d)
+	jitter func(d time.Duration) time.Duration
+}
+
+// newRetryTransport creates a retrying transport from the retry configuration
+func newRetryTransport(cfg config.RetryConfig, attemptTimeout time.Duration, label string, notify func(string, ...any)) *retryTransport {
+	t := &retryTransport{
+		base:           http.DefaultTransport,
+		maxAttempts:    cfg.MaxAttempts,
+		initialBackoff: time.Duration(cfg.InitialBackoffMs) * time.Millisecond,
+		maxBackoff:     time.Duration(cfg.MaxBackoffMs) * time.Millisecond,
+		attemptTimeout: attemptTimeout,
+		label:          label,
+		notify:         notify,
+		sleep:          sleepContext,
+		jitter: func(d time.Duration) time.Duration {
+			if d <= 0 {
+				return 0
+			}
+			return time.Duration(rand.Int63n(int64(d)))
+		},
+	}
+
+	// Fill in defaults for unset values
+	if t.maxAttempts <= 0 {
+		t.maxAttempts = defaultMaxAttempts
+	}
+	if t.initialBackoff <= 0 {
+		t.initialBackoff = defaultInitialBackoff
+	}
+	if t.maxBackoff <= 0 {
+		t.maxBackoff = defaultMaxBackoff
+	}
+	if t.notify == nil {
+		t.notify = func(string, ...any) {}
+	}
+
+	return t
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		attemptReq, cancel, err := t.prepareAttempt(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.base.RoundTrip(attemptReq)
+		if err == nil && !isRetryableStatus(resp.StatusCode) {
+			// Keep the attempt context alive until the body has been read
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		// Decide whether another attempt is worthwhile
+		retryable := err == nil || isRetryableError(err)
+		if !retryable || attempt >= t.maxAttempts || req.Context().Err() != nil {
+			if resp != nil {
+				resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			} else {
+				cancel()
+			}
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		reason := ""
+		if resp != nil {
+			reason = resp.Status
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > maxRetryAfter {
+					// The provider asked us to wait too long; report its answer instead
+					resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+					return resp, nil
+				}
+				delay = retryAfter
+			}
+			// Drain and close the body so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+			resp.Body.Close()
+		} else {
+			reason = err.Error()
+		}
+		cancel()
+
+		t.notify("Profile %q: request failed (%s), retrying in %s (attempt %d/%d)\n",
+			t.label, reason, delay.Round(100*time.Millisecond), attempt+1, t.maxAttempts)
+
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// prepareAttempt clones the request with a fresh body and per-attempt timeout
+func (t *retryTransport) prepareAttempt(req *http.Request, attempt int) (*http.Request, context.CancelFunc, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.attemptTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.attemptTimeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 1 && req.Body != nil {
+		if req.GetBody == nil {
+			cancel()
+			return nil, nil, fmt.Errorf("cannot retry request: body is not replayable")
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+// backoff returns the exponential backoff delay with jitter for the given attempt
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.initialBackoff << (attempt - 1)
+	if delay <= 0 || delay > t.maxBackoff {
+		delay = t.maxBackoff
+	}
+	// Equal jitter: half fixed, half random
+	return delay/2 + t.jitter(delay/2)
+}
+
+// isRetryableStatus reports whether an HTTP status is worth retrying
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status == http.StatusRequestTimeout || status >= 500
+}
+
+// isRetryableError reports whether a transport error is worth retrying
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := date.Sub(now)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnClose releases the attempt context once the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
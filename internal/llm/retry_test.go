@@ -0,0 +1,218 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"gitr/internal/config"
+	"gitr/internal/fakeopenai"
+)
+
+// testTransport returns a retry transport that records its waits instead of sleeping
+func testTransport(maxAttempts int, attemptTimeout time.Duration) (*retryTransport, *[]time.Duration) {
+	var waits []time.Duration
+	t := newRetryTransport(config.RetryConfig{MaxAttempts: maxAttempts, InitialBackoffMs: 100, MaxBackoffMs: 1000}, attemptTimeout, "test", nil)
+	t.sleep = func(ctx context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		return ctx.Err()
+	}
+	t.jitter = func(time.Duration) time.Duration { return 0 }
+	return t, &waits
+}
+
+func TestRetryTransport(t *testing.T) {
+	ok := fakeopenai.Reply{Content: "feat: add retries"}
+	soon := time.Now().Add(3 * time.Second).UTC().Format(http.TimeFormat)
+
+	tests := []struct {
+		name           string
+		replies        []fakeopenai.Reply
+		attemptTimeout time.Duration
+		wantStatus     int
+		wantRequests   int
+		// wantWaits are the expected waits; a negative value accepts any wait up to its magnitude
+		wantWaits []time.Duration
+	}{
+		{
+			name:         "success",
+			replies:      []fakeopenai.Reply{ok},
+			wantStatus:   200,
+			wantRequests: 1,
+		},
+		{
+			name:         "429 then success",
+			replies:      []fakeopenai.Reply{{Status: 429}, ok},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{50 * time.Millisecond},
+		},
+		{
+			name:         "5xx backs off exponentially",
+			replies:      []fakeopenai.Reply{{Status: 500}, {Status: 502}, {Status: 503}, ok},
+			wantStatus:   200,
+			wantRequests: 4,
+			wantWaits:    []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:         "Retry-After in seconds",
+			replies:      []fakeopenai.Reply{{Status: 429, RetryAfter: "2"}, ok},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{2 * time.Second},
+		},
+		{
+			name:         "Retry-After as HTTP date",
+			replies:      []fakeopenai.Reply{{Status: 503, RetryAfter: soon}, ok},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{-3 * time.Second},
+		},
+		{
+			name:         "Retry-After too long returns the answer",
+			replies:      []fakeopenai.Reply{{Status: 429, RetryAfter: "3600"}, ok},
+			wantStatus:   429,
+			wantRequests: 1,
+		},
+		{
+			name:         "retry limit reached",
+			replies:      []fakeopenai.Reply{{Status: 500}, {Status: 500}, {Status: 500}, {Status: 500}, {Status: 500}, ok},
+			wantStatus:   500,
+			wantRequests: 5,
+			wantWaits:    []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond},
+		},
+		{
+			name:         "4xx isn't retried",
+			replies:      []fakeopenai.Reply{{Status: 400}, ok},
+			wantStatus:   400,
+			wantRequests: 1,
+		},
+		{
+			name:           "attempt timeout is retried",
+			replies:        []fakeopenai.Reply{{Delay: time.Second}, ok},
+			attemptTimeout: 100 * time.Millisecond,
+			wantStatus:     200,
+			wantRequests:   2,
+			wantWaits:      []time.Duration{50 * time.Millisecond},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := fakeopenai.New(tt.replies...)
+			defer server.Close()
+			transport, waits := testTransport(5, tt.attemptTimeout)
+
+			request, err := http.NewRequest(http.MethodPost, server.URL+"/chat/completions", strings.NewReader(`{"model":"m","messages":[]}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			response, err := transport.RoundTrip(request)
+			if err != nil {
+				t.Fatalf("RoundTrip: %v", err)
+			}
+			response.Body.Close()
+
+			if response.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", response.StatusCode, tt.wantStatus)
+			}
+			if got := len(server.Requests()); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if len(*waits) != len(tt.wantWaits) {
+				t.Fatalf("waits = %v, want %v", *waits, tt.wantWaits)
+			}
+			for i, want := range tt.wantWaits {
+				got := (*waits)[i]
+				if want < 0 && (got <= 0 || got > -want) || want >= 0 && got != want {
+					t.Errorf("wait %d = %v, want %v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	transport, _ := testTransport(10, 0)
+	want := []time.Duration{50, 100, 200, 400, 500, 500}
+	for i, w := range want {
+		if got := transport.backoff(i + 1); got != w*time.Millisecond {
+			t.Errorf("backoff(%d) = %v, want %v", i+1, got, w*time.Millisecond)
+		}
+	}
+
+	// Jitter only adds to the fixed half
+	transport.jitter = func(d time.Duration) time.Duration { return d - 1 }
+	if got := transport.backoff(1); got != 100*time.Millisecond-1 {
+		t.Errorf("backoff with jitter = %v", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"5", 5 * time.Second, true},
+		{"0", 0, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:59:00 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFallbackProfile(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	primary := fakeopenai.New()
+	primary.Default = &fakeopenai.Reply{Status: 503}
+	defer primary.Close()
+	backup := fakeopenai.New(fakeopenai.Reply{Content: "fix: handle empty input"})
+	defer backup.Close()
+
+	var notices []string
+	previous := Notify
+	Notify = func(format string, args ...any) { notices = append(notices, fmt.Sprintf(format, args...)) }
+	defer func() { Notify = previous }()
+
+	disabled := false
+	cfg := &config.Config{
+		OpenAI:   config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: primary.URL, Model: "primary-model"},
+		Profiles: []config.Profile{{Name: "backup", OpenAI: config.OpenAIConfig{Provider: config.ProviderLocal, BaseURL: backup.URL, Model: "backup-model"}}},
+		Fallback: []string{"backup"},
+		Retry:    config.RetryConfig{MaxAttempts: 2, InitialBackoffMs: 1, MaxBackoffMs: 1},
+		Cache:    config.CacheConfig{Enabled: &disabled},
+		CommitTemplate: config.CommitTemplateConfig{
+			Style:     "conventional",
+			MaxLength: 72,
+		},
+	}
+
+	result, err := GenerateCommitMessage(cfg, "diff --git a/x b/x", Options{})
+	if err != nil {
+		t.Fatalf("GenerateCommitMessage: %v", err)
+	}
+	if result.Content != "fix: handle empty input" || result.Profile != "backup" || result.Model != "backup-model" {
+		t.Errorf("result = %+v, want the backup profile's answer", result)
+	}
+	if got := len(primary.Requests()); got != 2 {
+		t.Errorf("primary requests = %d, want 2 (one retry)", got)
+	}
+	if !strings.Contains(strings.Join(notices, ""), `Falling back to profile "backup"`) {
+		t.Errorf("notices don't mention the fallback:\n%s", strings.Join(notices, ""))
+	}
+}
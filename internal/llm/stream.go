@@ -0,0 +1,106 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	"gitr/internal/config"
+)
+
+// Stream is CompleteDiff with the answer written to w as it arrives. A
+// profile that fails before it answers is replaced by the next fallback
+// profile; once text has been written, an error ends the stream.
+func Stream(cfg *config.Config, system, user, diff string, w io.Writer) (*Result, error) {
+	ctx := context.Background()
+	start := time.Now()
+
+	prompt := []*schema.Message{schema.SystemMessage(system), schema.UserMessage(user)}
+
+	chain := cfg.ProfileChain()
+	var errs []error
+	for i, name := range chain {
+		settings, err := cfg.Profile(name)
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			Notify("Falling back to profile %q (%s)\n", name, settings.Model)
+		}
+
+		messages, err := fitContextWindow(cfg, settings, prompt, diff)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		chatModel, err := NewChatModel(ctx, settings, cfg.Retry, name)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		result, written, err := streamAnswer(ctx, chatModel, messages, w)
+		if err != nil {
+			if written {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			if i < len(chain)-1 {
+				Notify("Profile %q failed: %v\n", name, err)
+			}
+			continue
+		}
+
+		result.Profile = name
+		result.Model = settings.Model
+		result.Latency = time.Since(start)
+		recordUsage(cfg, result)
+		return result, nil
+	}
+
+	if len(errs) == 1 {
+		return nil, errs[0]
+	}
+	return nil, fmt.Errorf("all profiles failed: %w", errors.Join(errs...))
+}
+
+// streamAnswer copies the chunks of one streaming request to w and reports
+// whether anything was written
+func streamAnswer(ctx context.Context, chatModel model.BaseChatModel, messages []*schema.Message, w io.Writer) (*Result, bool, error) {
+	reader, err := chatModel.Stream(ctx, messages)
+	if err != nil {
+		return nil, false, err
+	}
+	defer reader.Close()
+
+	var content strings.Builder
+	result := &Result{}
+	for {
+		chunk, err := reader.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, content.Len() > 0, err
+		}
+		if chunk.Content != "" {
+			content.WriteString(chunk.Content)
+			if _, err := io.WriteString(w, chunk.Content); err != nil {
+				return nil, true, err
+			}
+		}
+		if usage := responseUsage(chunk); usage != nil {
+			result.Usage = usage
+		}
+		if chunk.ResponseMeta != nil && chunk.ResponseMeta.FinishReason == "length" {
+			Notify("\nWarning: the answer was cut off at max_tokens\n")
+		}
+	}
+	result.Content = content.String()
+	return result, content.Len() > 0, nil
+}
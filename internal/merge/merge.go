@@ -0,0 +1,95 @@
+// Package merge describes merges and squashes to the model, so that the
+// message covers the combined change instead of repeating every commit.
+package merge
+
+import (
+	"fmt"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Commit is one of the commits a merge or squash combines
+type Commit struct {
+	Hash    string
+	Message string
+}
+
+// Limits on how much of the combined commits goes into the prompt
+const (
+	maxCommits   = 100 // commits listed at all
+	fullMessages = 30  // commits listed with their body, the rest by subject
+	maxBodyLines = 10  // lines of each body
+)
+
+// Commits returns the newest non-merge commits of a range such as HEAD..MERGE_HEAD
+func Commits(revisions string) ([]Commit, error) {
+	hashes, err := git.Commits(revisions, maxCommits)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]Commit, 0, len(hashes))
+	for _, hash := range hashes {
+		message, err := git.CommitMessage(hash)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, Commit{Hash: hash, Message: message})
+	}
+	return commits, nil
+}
+
+// MergeNotes tells the model that the staged changes complete a merge
+func MergeNotes(state *git.MergeState, commits []Commit) string {
+	var b strings.Builder
+	b.WriteString("\nThis is a merge commit.")
+	if header, _, _ := strings.Cut(state.Message, "\n"); header != "" {
+		fmt.Fprintf(&b, " Use the header git proposed: %q.", header)
+	}
+	b.WriteString(" In the body, summarize what the merged commits change as a whole")
+	if len(state.Conflicts) > 0 {
+		b.WriteString(" and how the conflicts were resolved")
+	}
+	b.WriteString("; don't list the commits one by one.\n")
+	if len(state.Conflicts) > 0 {
+		fmt.Fprintf(&b, "Conflicts were resolved in: %s\n", strings.Join(state.Conflicts, ", "))
+	}
+	b.WriteString("The merged commits, newest first:\n")
+	writeCommits(&b, commits)
+	return b.String()
+}
+
+// SquashNotes tells the model that the staged changes replace total commits,
+// the newest of which are given
+func SquashNotes(commits []Commit, total int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\nThese changes squash %d commits into one. Write a single message for the combined change; don't list the commits one by one or repeat their headers.\n", total)
+	b.WriteString("The squashed commits, newest first:\n")
+	writeCommits(&b, commits)
+	return b.String()
+}
+
+// writeCommits lists commits with their subjects and, for the newest ones,
+// the start of their bodies
+func writeCommits(b *strings.Builder, commits []Commit) {
+	for i, commit := range commits {
+		subject, body, _ := strings.Cut(commit.Message, "\n")
+		fmt.Fprintf(b, "- %s %s\n", commit.Hash[:min(12, len(commit.Hash))], subject)
+		if i >= fullMessages {
+			continue
+		}
+		lines := strings.Split(strings.TrimSpace(body), "\n")
+		for j, line := range lines {
+			if j == maxBodyLines {
+				b.WriteString("    ...\n")
+				break
+			}
+			if strings.TrimSpace(line) != "" {
+				b.WriteString("    " + line + "\n")
+			}
+		}
+	}
+	if len(commits) == maxCommits {
+		fmt.Fprintf(b, "(only the newest %d commits are listed)\n", maxCommits)
+	}
+}
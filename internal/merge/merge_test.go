@@ -0,0 +1,76 @@
+package merge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitr/internal/git"
+)
+
+// commits returns n commits, newest first, each with a body of the given lines
+func commits(n, bodyLines int) []Commit {
+	list := make([]Commit, n)
+	for i := range list {
+		message := fmt.Sprintf("fix: change %d\n", i)
+		for j := range bodyLines {
+			message += fmt.Sprintf("\nbody %d line %d", i, j)
+		}
+		list[i] = Commit{Hash: fmt.Sprintf("%04d", i) + strings.Repeat("a", 36), Message: message}
+	}
+	return list
+}
+
+func TestSquashNotes(t *testing.T) {
+	notes := SquashNotes(commits(maxCommits, maxBodyLines+2), 250)
+
+	if !strings.Contains(notes, "squash 250 commits") {
+		t.Errorf("notes don't give the total:\n%s", notes)
+	}
+	if got := strings.Count(notes, "\n- "); got != maxCommits {
+		t.Errorf("%d commits listed, want %d", got, maxCommits)
+	}
+	if !strings.Contains(notes, fmt.Sprintf("(only the newest %d commits are listed)", maxCommits)) {
+		t.Error("notes don't say the list is cut")
+	}
+	// Only the newest commits keep their bodies, cut at maxBodyLines
+	if got := strings.Count(notes, "    ...\n"); got != fullMessages {
+		t.Errorf("%d bodies cut, want %d", got, fullMessages)
+	}
+	last := fmt.Sprintf("body %d line %d", fullMessages-1, maxBodyLines-1)
+	if !strings.Contains(notes, last) || strings.Contains(notes, fmt.Sprintf("body %d line %d", fullMessages-1, maxBodyLines)) {
+		t.Errorf("body of commit %d isn't cut after %d lines", fullMessages-1, maxBodyLines)
+	}
+	if strings.Contains(notes, fmt.Sprintf("body %d ", fullMessages)) {
+		t.Errorf("commit %d is listed with its body, want the subject only", fullMessages)
+	}
+
+	short := SquashNotes(commits(2, 1), 2)
+	if strings.Contains(short, "only the newest") || !strings.Contains(short, "- 0001aaaaaaaa fix: change 1\n") {
+		t.Errorf("notes for two commits:\n%s", short)
+	}
+}
+
+func TestMergeNotes(t *testing.T) {
+	state := &git.MergeState{Message: "Merge branch 'topic'\n\nmore", Conflicts: []string{"a.go", "b.go"}}
+	notes := MergeNotes(state, commits(fullMessages+1, 1))
+
+	for _, want := range []string{
+		`Use the header git proposed: "Merge branch 'topic'".`,
+		"and how the conflicts were resolved",
+		"Conflicts were resolved in: a.go, b.go\n",
+		fmt.Sprintf("body %d line 0", fullMessages-1),
+	} {
+		if !strings.Contains(notes, want) {
+			t.Errorf("notes don't contain %q:\n%s", want, notes)
+		}
+	}
+	if strings.Contains(notes, fmt.Sprintf("body %d ", fullMessages)) || strings.Contains(notes, "only the newest") {
+		t.Errorf("notes:\n%s", notes)
+	}
+
+	plain := MergeNotes(&git.MergeState{}, nil)
+	if strings.Contains(plain, "conflicts") || strings.Contains(plain, "header git proposed") {
+		t.Errorf("notes without conflicts or message:\n%s", plain)
+	}
+}
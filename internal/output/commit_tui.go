@@ -3,42 +3,196 @@ package output
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+
+	"gitr/internal/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
 )
 
+// stdin is shared by all line-based prompts, so input buffered by one isn't lost to the next
+var stdin = bufio.NewReader(os.Stdin)
+
+// SetInput makes the line-based prompts read from r instead of stdin, e.g. to
+// script the answers to the confirmation prompts
+func SetInput(r io.Reader) {
+	stdin = bufio.NewReader(r)
+}
+
 type CommitTUI struct {
-	message string
+	// kind is what the message is for, "commit" unless set
+	kind       string
+	message    string
+	regenerate func(instruction string) (string, error)
+
+	// diff is shown in the full-screen review UI
+	diff      string
+	maxLength int
+
+	// generated is the latest message produced by the model, before user edits
+	generated     string
+	regenerations int
+
+	// coAuthors are the roster aliases offered when picking co-authors, and
+	// addTrailers adds Co-authored-by trailers for a comma-separated list
+	coAuthors    []string
+	addTrailers  func(message, refs string) (string, error)
+	coAuthorRefs []string
+
+	// notice is shown above the message, e.g. to flag a heuristic message
+	notice string
+
+	reader *bufio.Reader
+	// out receives the review UI and prompts
+	out *os.File
 }
 
 func NewCommitTUI(message string) *CommitTUI {
 	return &CommitTUI{
-		message: message,
+		kind:      "commit",
+		message:   message,
+		generated: message,
+		reader:    stdin,
+		out:       os.Stdout,
 	}
 }
 
+// SetRegenerate enables the regenerate options, which call fn for a fresh
+// message. The instruction is empty unless the user provided extra guidance.
+func (t *CommitTUI) SetRegenerate(fn func(instruction string) (string, error)) {
+	t.regenerate = fn
+}
+
+// SetKind sets what the message is for, e.g. "tag", as shown in titles and prompts
+func (t *CommitTUI) SetKind(kind string) {
+	t.kind = kind
+}
+
+// SetDiff sets the staged diff shown in the full-screen review UI
+func (t *CommitTUI) SetDiff(diff string) {
+	t.diff = diff
+}
+
+// SetMaxLength sets the header length the review UI counts against
+func (t *CommitTUI) SetMaxLength(maxLength int) {
+	t.maxLength = maxLength
+}
+
+// SetCoAuthors enables picking co-authors during review. aliases are offered
+// to the user; add appends the trailers for a comma-separated list of them.
+func (t *CommitTUI) SetCoAuthors(aliases []string, add func(message, refs string) (string, error)) {
+	t.coAuthors = aliases
+	t.addTrailers = add
+}
+
+// SetNotice shows a notice about the message until it's regenerated
+func (t *CommitTUI) SetNotice(notice string) {
+	t.notice = notice
+}
+
+// SetOutput sends the review UI and prompts to out instead of stdout,
+// e.g. to keep stdout free for machine-readable output
+func (t *CommitTUI) SetOutput(out *os.File) {
+	t.out = out
+}
+
+// Edited reports whether the user changed the latest generated message
+func (t *CommitTUI) Edited() bool {
+	return t.message != t.generated
+}
+
+// Regenerations returns how many times the user asked for a new message
+func (t *CommitTUI) Regenerations() int {
+	return t.regenerations
+}
+
+// addCoAuthors adds co-authors to the message. Trailers aren't an edit, so
+// they're added to the generated message as well.
+func (t *CommitTUI) addCoAuthors(refs string) error {
+	message, err := t.addTrailers(t.message, refs)
+	if err != nil {
+		return err
+	}
+	generated, err := t.addTrailers(t.generated, refs)
+	if err != nil {
+		return err
+	}
+	t.message = message
+	t.generated = generated
+	t.coAuthorRefs = append(t.coAuthorRefs, refs)
+	return nil
+}
+
+// regenerated replaces the message with a fresh one, keeping the co-authors picked so far
+func (t *CommitTUI) regenerated(message string) {
+	for _, refs := range t.coAuthorRefs {
+		if withTrailers, err := t.addTrailers(message, refs); err == nil {
+			message = withTrailers
+		}
+	}
+	t.message = message
+	t.generated = message
+	t.notice = ""
+	t.regenerations++
+}
+
+// ShowCommitEditor lets the user review the message and returns the final
+// message and whether to commit. It uses the full-screen review UI when
+// running in a terminal and falls back to line-based prompts otherwise.
 func (t *CommitTUI) ShowCommitEditor() (string, bool, error) {
-	fmt.Println("Generated Commit Message:")
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println(t.message)
-	fmt.Println(strings.Repeat("=", 50))
-	fmt.Println("")
+	if t.isTerminal() {
+		return t.showReviewUI()
+	}
+	return t.showPrompts()
+}
+
+// isTerminal reports whether both stdin and the output are attached to a terminal
+func (t *CommitTUI) isTerminal() bool {
+	return isatty.IsTerminal(t.out.Fd()) && isatty.IsTerminal(os.Stdin.Fd())
+}
+
+// showReviewUI runs the full-screen Bubble Tea review UI
+func (t *CommitTUI) showReviewUI() (string, bool, error) {
+	program := tea.NewProgram(newReviewModel(t), tea.WithAltScreen(), tea.WithOutput(t.out))
+	finalModel, err := program.Run()
+	if err != nil {
+		return "", false, err
+	}
+
+	if m, ok := finalModel.(reviewModel); ok && m.accepted {
+		return t.message, true, nil
+	}
+	return "", false, nil
+}
+
+// showPrompts runs the line-based review prompts
+func (t *CommitTUI) showPrompts() (string, bool, error) {
+	t.printMessage(t.text("Generated Commit Message:"))
 
 	for {
-		fmt.Println("Options:")
-		fmt.Println(" [a] Accept and commit (default)")
-		fmt.Println(" [e] Edit message")
-		fmt.Println(" [r] Reject (don't commit)")
-		fmt.Println("")
-		fmt.Print("Choose an option [a/e/r] (or press Enter to accept): ")
-
-		reader := bufio.NewReader(os.Stdin)
-		choice, err := reader.ReadString('\n')
+		fmt.Fprintln(t.out, i18n.T("Options:"))
+		fmt.Fprintln(t.out, " [a] "+t.text("Accept and commit (default)"))
+		fmt.Fprintln(t.out, " [e] "+i18n.T("Edit message"))
+		if t.regenerate != nil {
+			fmt.Fprintln(t.out, " [g] "+i18n.T("Regenerate message"))
+			fmt.Fprintln(t.out, " [i] "+i18n.T("Regenerate with an extra instruction"))
+		}
+		if t.addTrailers != nil {
+			fmt.Fprintln(t.out, " [w] "+i18n.T("Add co-authors"))
+		}
+		fmt.Fprintln(t.out, " [r] "+t.text("Reject (don't commit)"))
+		fmt.Fprintln(t.out)
+		i18n.Fprintf(t.out, "Choose an option [%s] (or press Enter to accept): ", t.optionKeys())
+
+		choice, err := t.readLine()
 		if err != nil {
 			return "", false, err
 		}
 
-		choice = strings.TrimSpace(strings.ToLower(choice))
+		choice = strings.ToLower(choice)
 
 		// If no choice provided, default to 'a' (accept)
 		if choice == "" {
@@ -51,43 +205,146 @@ func (t *CommitTUI) ShowCommitEditor() (string, bool, error) {
 		case "e", "edit":
 			editedMessage, err := t.editMessage()
 			if err != nil {
-				fmt.Printf("Error editing message: %v\n", err)
+				i18n.Fprintf(t.out, "Error editing message: %v\n", err)
 				continue
 			}
 			if editedMessage != "" {
 				t.message = editedMessage
-				fmt.Println("")
-				fmt.Println("Updated Commit Message:")
-				fmt.Println(strings.Repeat("=", 50))
-				fmt.Println(t.message)
-				fmt.Println(strings.Repeat("=", 50))
-				fmt.Println("")
+				t.printMessage(t.text("Updated Commit Message:"))
+			}
+		case "g", "regenerate", "i", "instruct":
+			if t.regenerate == nil {
+				i18n.Fprintf(t.out, "Invalid option. Please choose %s.\n", t.optionList())
+				fmt.Fprintln(t.out)
+				continue
+			}
+
+			instruction := ""
+			if choice == "i" || choice == "instruct" {
+				fmt.Fprint(t.out, i18n.T("Extra instruction: "))
+				instruction, err = t.readLine()
+				if err != nil {
+					return "", false, err
+				}
+			}
+
+			fmt.Fprintln(t.out, t.text("Regenerating commit message..."))
+			newMessage, err := t.regenerate(instruction)
+			if err != nil {
+				i18n.Fprintf(t.out, "Error regenerating message: %v\n", err)
+				continue
+			}
+			if newMessage != "" {
+				t.regenerated(newMessage)
+				t.printMessage(t.text("Regenerated Commit Message:"))
+			}
+		case "w", "with":
+			if t.addTrailers == nil {
+				i18n.Fprintf(t.out, "Invalid option. Please choose %s.\n", t.optionList())
+				fmt.Fprintln(t.out)
+				continue
+			}
+
+			if len(t.coAuthors) > 0 {
+				i18n.Fprintf(t.out, "Team: %s\n", strings.Join(t.coAuthors, ", "))
+			}
+			fmt.Fprint(t.out, i18n.T("Co-authors (comma-separated): "))
+			refs, err := t.readLine()
+			if err != nil {
+				return "", false, err
+			}
+			if refs == "" {
+				continue
+			}
+			if err := t.addCoAuthors(refs); err != nil {
+				i18n.Fprintf(t.out, "Error adding co-authors: %v\n", err)
+				continue
 			}
+			t.printMessage(t.text("Updated Commit Message:"))
 		case "r", "reject":
 			return "", false, nil
 		default:
-			fmt.Println("Invalid option. Please choose 'a', 'e', or 'r'.")
-			fmt.Println("")
+			i18n.Fprintf(t.out, "Invalid option. Please choose %s.\n", t.optionList())
+			fmt.Fprintln(t.out)
 		}
 	}
 }
 
+// text translates s, which is written for commits, for the kind of message:
+// for tags, "Edit Commit Message:" is looked up as "Edit Tag Message:"
+func (t *CommitTUI) text(s string) string {
+	if t.kind != "commit" {
+		s = strings.ReplaceAll(s, "commit", t.kind)
+		s = strings.ReplaceAll(s, "Commit", strings.ToUpper(t.kind[:1])+t.kind[1:])
+	}
+	return i18n.T(s)
+}
+
+func (t *CommitTUI) printMessage(title string) {
+	fmt.Fprintln(t.out)
+	fmt.Fprintln(t.out, title)
+	if t.notice != "" {
+		fmt.Fprintln(t.out, t.notice)
+	}
+	fmt.Fprintln(t.out, strings.Repeat("=", 50))
+	fmt.Fprintln(t.out, t.message)
+	fmt.Fprintln(t.out, strings.Repeat("=", 50))
+	fmt.Fprintln(t.out)
+}
+
+// options returns the keys of the available prompt options
+func (t *CommitTUI) options() []string {
+	keys := []string{"a", "e"}
+	if t.regenerate != nil {
+		keys = append(keys, "g", "i")
+	}
+	if t.addTrailers != nil {
+		keys = append(keys, "w")
+	}
+	return append(keys, "r")
+}
+
+func (t *CommitTUI) optionKeys() string {
+	return strings.Join(t.options(), "/")
+}
+
+func (t *CommitTUI) optionList() string {
+	keys := t.options()
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = "'" + key + "'"
+	}
+	return strings.Join(quoted[:len(quoted)-1], ", ") + i18n.T(", or ") + quoted[len(quoted)-1]
+}
+
+// readLine reads one trimmed line from stdin
+func (t *CommitTUI) readLine() (string, error) {
+	return readLine(t.reader)
+}
+
+// readLine reads one trimmed line. A final line without a newline is accepted.
+func readLine(reader *bufio.Reader) (string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil && !(err == io.EOF && line != "") {
+		return "", err
+	}
+	return strings.TrimSpace(line), nil
+}
+
 // editMessage allows the user to edit the commit message
 func (t *CommitTUI) editMessage() (string, error) {
-	fmt.Println("")
-	fmt.Println("Edit Commit Message:")
-	fmt.Println("")
-	fmt.Printf("Current message: %s\n", t.message)
-	fmt.Println("")
-	fmt.Print("Edit message: ")
-
-	reader := bufio.NewReader(os.Stdin)
-	newMessage, err := reader.ReadString('\n')
+	fmt.Fprintln(t.out)
+	fmt.Fprintln(t.out, t.text("Edit Commit Message:"))
+	fmt.Fprintln(t.out)
+	i18n.Fprintf(t.out, "Current message: %s\n", t.message)
+	fmt.Fprintln(t.out)
+	fmt.Fprint(t.out, i18n.T("Edit message: "))
+
+	newMessage, err := t.readLine()
 	if err != nil {
 		return "", err
 	}
 
-	newMessage = strings.TrimSpace(newMessage)
 	if newMessage == "" {
 		return t.message, nil
 	}
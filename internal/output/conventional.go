@@ -0,0 +1,126 @@
+package output
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Footer is a git trailer such as "Refs: #123" or "BREAKING CHANGE: ..."
+type Footer struct {
+	Token string `json:"token"`
+	Value string `json:"value"`
+}
+
+// Message is a commit message split into its conventional commit parts
+type Message struct {
+	Header   string   `json:"header"`
+	Type     string   `json:"type,omitempty"`
+	Scope    string   `json:"scope,omitempty"`
+	Breaking bool     `json:"breaking"`
+	Subject  string   `json:"subject"`
+	Body     string   `json:"body,omitempty"`
+	Footers  []Footer `json:"footers"`
+}
+
+var (
+	headerPattern = regexp.MustCompile(`^(\w[\w-]*)(?:\(([^)]*)\))?(!)?: (.+)$`)
+	footerPattern = regexp.MustCompile(`^([\w-]+|BREAKING CHANGE)(: | #)(.*)$`)
+)
+
+// ParseConventional splits a commit message into header, body and footers.
+// Type and scope are only filled in when the header follows the conventional format.
+func ParseConventional(message string) Message {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	header, rest, _ := strings.Cut(message, "\n")
+
+	parsed := Message{
+		Header:  strings.TrimSpace(header),
+		Subject: strings.TrimSpace(header),
+		Footers: []Footer{},
+	}
+	if m := headerPattern.FindStringSubmatch(parsed.Header); m != nil {
+		parsed.Type = m[1]
+		parsed.Scope = m[2]
+		parsed.Breaking = m[3] == "!"
+		parsed.Subject = m[4]
+	}
+
+	// Footers are the trailing paragraph if every line in it is a trailer
+	paragraphs := strings.Split(strings.TrimSpace(rest), "\n\n")
+	if last := paragraphs[len(paragraphs)-1]; last != "" {
+		if footers, ok := parseFooters(last); ok {
+			parsed.Footers = footers
+			paragraphs = paragraphs[:len(paragraphs)-1]
+		}
+	}
+	parsed.Body = strings.TrimSpace(strings.Join(paragraphs, "\n\n"))
+
+	for _, footer := range parsed.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			parsed.Breaking = true
+		}
+	}
+
+	return parsed
+}
+
+// parseFooters parses a paragraph of trailers; continuation lines start with whitespace
+func parseFooters(paragraph string) ([]Footer, bool) {
+	var footers []Footer
+	for _, line := range strings.Split(paragraph, "\n") {
+		if m := footerPattern.FindStringSubmatch(line); m != nil {
+			value := strings.TrimSpace(m[3])
+			if m[2] == " #" {
+				// "Fixes #123" keeps the issue reference intact
+				value = "#" + value
+			}
+			footers = append(footers, Footer{Token: m[1], Value: value})
+			continue
+		}
+		if len(footers) > 0 && strings.TrimSpace(line) != "" && (line[0] == ' ' || line[0] == '\t') {
+			footers[len(footers)-1].Value += "\n" + strings.TrimSpace(line)
+			continue
+		}
+		return nil, false
+	}
+	return footers, true
+}
+
+// MarkBreaking flags a message as a breaking change: a conventional header
+// gets a "!" and the footers a BREAKING CHANGE entry with the description.
+// Either is left alone when the message already has it.
+func MarkBreaking(message, description string) string {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	if message == "" {
+		return message
+	}
+	parsed := ParseConventional(message)
+	header, rest, _ := strings.Cut(message, "\n")
+	if m := headerPattern.FindStringSubmatch(strings.TrimSpace(header)); m != nil && m[3] == "" {
+		header = m[1]
+		if m[2] != "" {
+			header += "(" + m[2] + ")"
+		}
+		header += "!: " + m[4]
+	}
+	message = strings.TrimRight(header+"\n"+rest, "\n")
+
+	for _, footer := range parsed.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			return message
+		}
+	}
+	footer := "BREAKING CHANGE: " + description
+	if len(parsed.Footers) == 0 {
+		return message + "\n\n" + footer
+	}
+	// The footers are the paragraph ParseConventional matched, which may
+	// follow the header directly; join them so they stay one trailer block
+	// after a blank line
+	paragraphs := strings.Split(strings.TrimSpace(rest), "\n\n")
+	message = header
+	if body := strings.Join(paragraphs[:len(paragraphs)-1], "\n\n"); body != "" {
+		message += "\n\n" + body
+	}
+	return message + "\n\n" + footer + "\n" + paragraphs[len(paragraphs)-1]
+}
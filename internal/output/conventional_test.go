@@ -0,0 +1,59 @@
+package output
+
+import "testing"
+
+func TestMarkBreaking(t *testing.T) {
+	const description = "api: changed func Hello"
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{
+			name:    "no footers",
+			message: "feat(api): add name\n\nHello takes a name now.",
+			want:    "feat(api)!: add name\n\nHello takes a name now.\n\nBREAKING CHANGE: api: changed func Hello",
+		},
+		{
+			name:    "header only",
+			message: "feat(api): add name",
+			want:    "feat(api)!: add name\n\nBREAKING CHANGE: api: changed func Hello",
+		},
+		{
+			name:    "footers right after the header",
+			message: "feat(api): add name\nRefs: #12",
+			want:    "feat(api)!: add name\n\nBREAKING CHANGE: api: changed func Hello\nRefs: #12",
+		},
+		{
+			name:    "body then footers",
+			message: "feat(api): add name\n\nHello takes a name now.\n\nRefs: #12\nCo-authored-by: Ada <ada@example.com>",
+			want:    "feat(api)!: add name\n\nHello takes a name now.\n\nBREAKING CHANGE: api: changed func Hello\nRefs: #12\nCo-authored-by: Ada <ada@example.com>",
+		},
+		{
+			name:    "already marked",
+			message: "feat(api)!: add name\n\nBREAKING-CHANGE: Hello takes a name",
+			want:    "feat(api)!: add name\n\nBREAKING-CHANGE: Hello takes a name",
+		},
+		{
+			name:    "not conventional",
+			message: "Add a name to Hello",
+			want:    "Add a name to Hello\n\nBREAKING CHANGE: api: changed func Hello",
+		},
+		{
+			name:    "empty",
+			message: "  ",
+			want:    "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := MarkBreaking(tt.message, description)
+			if got != tt.want {
+				t.Errorf("MarkBreaking =\n%q\nwant\n%q", got, tt.want)
+			}
+			if got != "" && !ParseConventional(got).Breaking {
+				t.Errorf("%q doesn't parse as breaking", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package output
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ConventionalTypes are the commit types of the Conventional Commits convention
+var ConventionalTypes = []string{"feat", "fix", "docs", "style", "refactor", "perf", "test", "build", "ci", "chore", "revert"}
+
+// LintOptions selects the rules Lint applies
+type LintOptions struct {
+	// MaxLength is the longest allowed header; 0 means no limit
+	MaxLength int
+	// Conventional requires a conventional header with a known type
+	Conventional bool
+}
+
+// Lint checks a commit message against common conventions and returns the
+// problems found, if any
+func Lint(message string, opts LintOptions) []string {
+	message = strings.TrimSpace(strings.ReplaceAll(message, "\r\n", "\n"))
+	if message == "" {
+		return []string{"message is empty"}
+	}
+
+	var problems []string
+	parsed := ParseConventional(message)
+
+	if opts.Conventional {
+		switch {
+		case parsed.Type == "":
+			problems = append(problems, "header is not in the form type(scope): subject")
+		case !slices.Contains(ConventionalTypes, strings.ToLower(parsed.Type)):
+			problems = append(problems, fmt.Sprintf("unknown type %q", parsed.Type))
+		}
+	}
+	if length := utf8.RuneCountInString(parsed.Header); opts.MaxLength > 0 && length > opts.MaxLength {
+		problems = append(problems, fmt.Sprintf("header is %d characters, over the limit of %d", length, opts.MaxLength))
+	}
+	if strings.HasSuffix(parsed.Subject, ".") {
+		problems = append(problems, "subject ends with a period")
+	}
+	if opts.Conventional && parsed.Type != "" {
+		if first, _ := utf8.DecodeRuneInString(parsed.Subject); unicode.IsUpper(first) {
+			problems = append(problems, "subject starts with an uppercase letter")
+		}
+	}
+	if _, rest, ok := strings.Cut(message, "\n"); ok && !strings.HasPrefix(rest, "\n") {
+		problems = append(problems, "no blank line between header and body")
+	}
+
+	return problems
+}
@@ -0,0 +1,251 @@
+package output
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"gitr/internal/git"
+	"gitr/internal/i18n"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-isatty"
+)
+
+// PickFiles asks the user which changed files to stage and returns their
+// paths, or nothing when the user cancels. It uses a full-screen picker in a
+// terminal and numbered line-based prompts otherwise.
+func PickFiles(files []git.FileStatus, out *os.File) ([]string, error) {
+	if isatty.IsTerminal(out.Fd()) && isatty.IsTerminal(os.Stdin.Fd()) {
+		return pickFilesUI(files, out)
+	}
+	return pickFilesPrompt(files, out)
+}
+
+// fileLabel describes a file with its kind of change and line counts
+func fileLabel(f git.FileStatus) string {
+	stats := fmt.Sprintf("+%d -%d", f.Added, f.Deleted)
+	if f.Binary {
+		stats = i18n.T("binary")
+	}
+	path := f.Path
+	if f.OrigPath != "" {
+		path = f.OrigPath + " → " + f.Path
+	}
+	label := fmt.Sprintf("%-10s %s %s", i18n.T(f.Kind()), path, stats)
+	if f.HasStaged() {
+		label += i18n.T(" (partly staged)")
+	}
+	return label
+}
+
+// pickFilesUI runs the full-screen Bubble Tea picker
+func pickFilesUI(files []git.FileStatus, out *os.File) ([]string, error) {
+	program := tea.NewProgram(pickerModel{files: files, checked: make([]bool, len(files))}, tea.WithAltScreen(), tea.WithOutput(out))
+	finalModel, err := program.Run()
+	if err != nil {
+		return nil, err
+	}
+
+	m, ok := finalModel.(pickerModel)
+	if !ok || !m.confirmed {
+		return nil, nil
+	}
+	var paths []string
+	for i, f := range m.files {
+		if m.checked[i] {
+			paths = append(paths, f.Path)
+		}
+	}
+	return paths, nil
+}
+
+// pickFilesPrompt lists the files with numbers and reads a selection such as "1,3-4"
+func pickFilesPrompt(files []git.FileStatus, out *os.File) ([]string, error) {
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, i18n.T("Unstaged changes:"))
+	for i, f := range files {
+		fmt.Fprintf(out, " [%d] %s\n", i+1, fileLabel(f))
+	}
+	fmt.Fprintln(out)
+
+	for {
+		fmt.Fprint(out, i18n.T("Files to stage (e.g. 1,3-4, 'a' for all, Enter to cancel): "))
+		line, err := readLine(stdin)
+		if err != nil {
+			return nil, err
+		}
+		if line == "" {
+			return nil, nil
+		}
+
+		indexes, err := parseSelection(line, len(files))
+		if err != nil {
+			i18n.Fprintf(out, "Invalid selection: %v\n", err)
+			continue
+		}
+		paths := make([]string, 0, len(indexes))
+		for _, i := range indexes {
+			paths = append(paths, files[i].Path)
+		}
+		return paths, nil
+	}
+}
+
+// parseSelection parses "a" or a list of numbers and ranges into zero-based indexes
+func parseSelection(selection string, count int) ([]int, error) {
+	if strings.EqualFold(selection, "a") || strings.EqualFold(selection, "all") {
+		indexes := make([]int, count)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	}
+
+	seen := make([]bool, count)
+	var indexes []int
+	for _, part := range strings.FieldsFunc(selection, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			to = from
+		}
+		start, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, errors.New(i18n.Sprintf("%q is not a number", part))
+		}
+		end, err := strconv.Atoi(to)
+		if err != nil {
+			return nil, errors.New(i18n.Sprintf("%q is not a number", part))
+		}
+		if start < 1 || end > count || start > end {
+			return nil, errors.New(i18n.Sprintf("%q is out of range 1-%d", part, count))
+		}
+		for i := start - 1; i < end; i++ {
+			if !seen[i] {
+				seen[i] = true
+				indexes = append(indexes, i)
+			}
+		}
+	}
+	return indexes, nil
+}
+
+// pickerModel is the full-screen staging picker
+type pickerModel struct {
+	files   []git.FileStatus
+	checked []bool
+	cursor  int
+	offset  int
+	height  int
+
+	// Outcome
+	confirmed bool
+}
+
+func (m pickerModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		m.scroll()
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+c", "q", "esc":
+			return m, tea.Quit
+
+		case "enter":
+			m.confirmed = true
+			return m, tea.Quit
+
+		case " ", "x":
+			m.checked[m.cursor] = !m.checked[m.cursor]
+
+		case "a":
+			// Select all, or clear the selection when everything is selected
+			all := true
+			for _, checked := range m.checked {
+				all = all && checked
+			}
+			for i := range m.checked {
+				m.checked[i] = !all
+			}
+
+		case "up", "k":
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case "down", "j":
+			if m.cursor < len(m.files)-1 {
+				m.cursor++
+			}
+
+		case "home", "g":
+			m.cursor = 0
+
+		case "end", "G":
+			m.cursor = len(m.files) - 1
+		}
+		m.scroll()
+	}
+
+	return m, nil
+}
+
+// listHeight is the number of files that fit below the title and above the help
+func (m pickerModel) listHeight() int {
+	if height := m.height - 5; height > 1 {
+		return height
+	}
+	return 1
+}
+
+// scroll keeps the cursor within the visible part of the list
+func (m *pickerModel) scroll() {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+m.listHeight() {
+		m.offset = m.cursor - m.listHeight() + 1
+	}
+}
+
+func (m pickerModel) View() string {
+	if m.height == 0 {
+		return i18n.T("Loading...")
+	}
+
+	var content strings.Builder
+	content.WriteString(reviewTitleStyle.Render(i18n.T("GitR Stage Changes")) + "\n\n")
+
+	selected := 0
+	for _, checked := range m.checked {
+		if checked {
+			selected++
+		}
+	}
+
+	end := min(m.offset+m.listHeight(), len(m.files))
+	for i := m.offset; i < end; i++ {
+		box := "[ ]"
+		if m.checked[i] {
+			box = "[x]"
+		}
+		line := box + " " + fileLabel(m.files[i])
+		if i == m.cursor {
+			line = reviewSelectedStyle.Render(line)
+		}
+		content.WriteString(line + "\n")
+	}
+
+	content.WriteString("\n" + reviewHelpStyle.Render(i18n.Sprintf(
+		"%d of %d selected • Space: Toggle • a: All • Enter: Stage and continue • q/Esc: Cancel", selected, len(m.files))))
+	return content.String()
+}
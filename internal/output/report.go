@@ -0,0 +1,155 @@
+package output
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"gitr/internal/llm"
+)
+
+// Output formats for generation commands
+const (
+	FormatText = "text" // decorated, for people
+	FormatJSON = "json" // stable schema, for scripts and editor plugins
+	FormatRaw  = "raw"  // the bare commit message
+)
+
+// ReportVersion is the version of the JSON schema; it changes only on incompatible changes
+const ReportVersion = 1
+
+// Exit codes shared by all generation commands
+const (
+	ExitOK            = 0
+	ExitError         = 1 // unexpected error
+	ExitUsage         = 2 // invalid flags or arguments
+	ExitNotRepository = 3 // not inside a git repository
+	ExitNoChanges     = 4 // nothing staged
+	ExitConfig        = 5 // missing or invalid configuration
+	ExitProvider      = 6 // the model provider failed
+	ExitCommit        = 7 // git commit failed
+	ExitCancelled     = 8 // the user rejected the message
+	ExitReview        = 9 // the review found problems that block the commit
+)
+
+// errorCodes names the exit codes in JSON error reports
+var errorCodes = map[int]string{
+	ExitError:         "error",
+	ExitUsage:         "usage",
+	ExitNotRepository: "not_a_repository",
+	ExitNoChanges:     "no_staged_changes",
+	ExitConfig:        "config",
+	ExitProvider:      "provider",
+	ExitCommit:        "commit_failed",
+	ExitCancelled:     "cancelled",
+	ExitReview:        "review_blocked",
+}
+
+// Report is the result of a generation command
+type Report struct {
+	Version     int        `json:"version"`
+	Message     string     `json:"message"`
+	Parsed      Message    `json:"parsed"`
+	RawResponse string     `json:"raw_response"`
+	Profile     string     `json:"profile"`
+	Model       string     `json:"model"`
+	Cached      bool       `json:"cached"`
+	Heuristic   bool       `json:"heuristic,omitempty"`
+	Usage       *llm.Usage `json:"usage"`
+	CostUSD     *float64   `json:"cost_usd,omitempty"`
+	LatencyMs   int64      `json:"latency_ms"`
+
+	// Set by commands that commit
+	Committed *bool  `json:"committed,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
+
+// ErrorReport is printed instead of a Report when a command fails
+type ErrorReport struct {
+	Version int         `json:"version"`
+	Error   ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes a failure
+type ErrorDetail struct {
+	Code     string `json:"code"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
+}
+
+// NewReport builds the report for a generated message
+func NewReport(message string, result *llm.Result) Report {
+	return Report{
+		Version:     ReportVersion,
+		Message:     message,
+		Parsed:      ParseConventional(message),
+		RawResponse: result.Content,
+		Profile:     result.Profile,
+		Model:       result.Model,
+		Cached:      result.Cached,
+		Heuristic:   result.Heuristic,
+		Usage:       result.Usage,
+		CostUSD:     result.Cost,
+		LatencyMs:   result.Latency.Milliseconds(),
+	}
+}
+
+// ValidFormat reports whether format is a supported output format
+func ValidFormat(format string) bool {
+	switch format {
+	case FormatText, FormatJSON, FormatRaw:
+		return true
+	}
+	return false
+}
+
+// WriteReport writes a report in the given format
+func WriteReport(w io.Writer, format string, report Report) error {
+	report.Version = ReportVersion
+
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, report)
+	case FormatRaw:
+		_, err := fmt.Fprintln(w, strings.TrimSpace(report.Message))
+		return err
+	default:
+		_, err := fmt.Fprint(w, FormatCommitMessageOutput(report.Message))
+		return err
+	}
+}
+
+// WriteError writes a failure and an optional hint on how to fix it in the given
+// format. Text and raw errors are plain lines; JSON errors carry a stable code
+// alongside the exit code.
+func WriteError(w io.Writer, format string, exitCode int, message, hint string) error {
+	if format != FormatJSON {
+		if hint != "" {
+			message += "\n" + hint
+		}
+		_, err := fmt.Fprintln(w, message)
+		return err
+	}
+
+	code, ok := errorCodes[exitCode]
+	if !ok {
+		code = errorCodes[ExitError]
+	}
+	return writeJSON(w, ErrorReport{
+		Version: ReportVersion,
+		Error: ErrorDetail{
+			Code:     code,
+			ExitCode: exitCode,
+			Message:  message,
+			Hint:     hint,
+		},
+	})
+}
+
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,558 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+
+	"gitr/internal/i18n"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Review UI modes
+type reviewMode int
+
+const (
+	modeReview reviewMode = iota
+	modeEdit
+	modeInstruction
+	modeCoAuthors
+	modeBusy
+)
+
+// Pane that receives navigation keys in review mode
+type reviewFocus int
+
+const (
+	focusDiff reviewFocus = iota
+	focusFiles
+)
+
+// Styles
+var (
+	reviewTitleStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#FAFAFA")).
+				Background(lipgloss.Color("#7D56F4")).
+				Padding(0, 1)
+
+	reviewPaneStyle = lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color("#626262"))
+
+	reviewFocusedPaneStyle = reviewPaneStyle.
+				BorderForeground(lipgloss.Color("#7D56F4"))
+
+	reviewHelpStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#626262"))
+
+	reviewSelectedStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#7D56F4")).
+				Background(lipgloss.Color("#FAFAFA"))
+
+	reviewOKStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	reviewErrorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#04B575"))
+	diffDelStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87"))
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("#00AFD7"))
+	diffHeaderStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA"))
+)
+
+// messagePaneHeight is the number of text lines shown in the message pane
+const messagePaneHeight = 6
+
+// regeneratedMsg carries the result of a regeneration request
+type regeneratedMsg struct {
+	message string
+	err     error
+}
+
+// reviewModel is the full-screen commit review UI
+type reviewModel struct {
+	tui *CommitTUI
+
+	files    []diffFile
+	selected int
+	focus    reviewFocus
+	mode     reviewMode
+
+	diff        viewport.Model
+	editor      textarea.Model
+	instruction textinput.Model
+	coAuthors   textinput.Model
+
+	status    string
+	statusErr bool
+	width     int
+	height    int
+
+	// Outcome
+	accepted bool
+}
+
+func newReviewModel(t *CommitTUI) reviewModel {
+	editor := textarea.New()
+	editor.ShowLineNumbers = false
+	editor.Prompt = ""
+	editor.CharLimit = 0
+	editor.SetHeight(messagePaneHeight)
+
+	instruction := textinput.New()
+	instruction.Placeholder = i18n.T("e.g. mention the migration, use scope api")
+	instruction.Prompt = i18n.T("Instruction: ")
+
+	coAuthors := textinput.New()
+	coAuthors.Placeholder = i18n.T("alias, name or \"Name <email>\", comma-separated")
+	if len(t.coAuthors) > 0 {
+		coAuthors.Placeholder = strings.Join(t.coAuthors, ", ")
+	}
+	coAuthors.Prompt = i18n.T("Co-authors: ")
+
+	m := reviewModel{
+		tui:         t,
+		files:       parseDiffFiles(t.diff),
+		diff:        viewport.New(0, 0),
+		editor:      editor,
+		instruction: instruction,
+		coAuthors:   coAuthors,
+	}
+	return m
+}
+
+func (m reviewModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m reviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+		return m, nil
+
+	case regeneratedMsg:
+		m.mode = modeReview
+		if msg.err != nil {
+			m.setStatus(i18n.Sprintf("Error regenerating message: %v", msg.err), true)
+			return m, nil
+		}
+		if msg.message != "" {
+			m.tui.regenerated(msg.message)
+			m.setStatus(i18n.T("Message regenerated"), false)
+		}
+		return m, nil
+
+	case tea.KeyMsg:
+		switch m.mode {
+		case modeEdit:
+			return m.updateEdit(msg)
+		case modeInstruction:
+			return m.updateInstruction(msg)
+		case modeCoAuthors:
+			return m.updateCoAuthors(msg)
+		case modeBusy:
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+		return m.updateReview(msg)
+	}
+
+	return m, nil
+}
+
+func (m reviewModel) updateReview(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c", "q", "esc":
+		return m, tea.Quit
+
+	case "a", "enter":
+		m.accepted = true
+		return m, tea.Quit
+
+	case "e":
+		m.mode = modeEdit
+		m.editor.SetValue(m.tui.message)
+		m.setStatus("", false)
+		return m, m.editor.Focus()
+
+	case "r":
+		if m.tui.regenerate == nil {
+			return m, nil
+		}
+		return m.startRegenerate("")
+
+	case "i":
+		if m.tui.regenerate == nil {
+			return m, nil
+		}
+		m.mode = modeInstruction
+		m.instruction.SetValue("")
+		return m, m.instruction.Focus()
+
+	case "w":
+		if m.tui.addTrailers == nil {
+			return m, nil
+		}
+		m.mode = modeCoAuthors
+		m.coAuthors.SetValue("")
+		m.setStatus("", false)
+		return m, m.coAuthors.Focus()
+
+	case "tab":
+		if m.focus == focusDiff {
+			m.focus = focusFiles
+		} else {
+			m.focus = focusDiff
+		}
+		return m, nil
+
+	case "up", "k":
+		if m.focus == focusFiles {
+			m.selectFile(m.selected - 1)
+		} else {
+			m.diff.ScrollUp(1)
+		}
+		return m, nil
+
+	case "down", "j":
+		if m.focus == focusFiles {
+			m.selectFile(m.selected + 1)
+		} else {
+			m.diff.ScrollDown(1)
+		}
+		return m, nil
+
+	case "pgup", "b":
+		m.diff.PageUp()
+		return m, nil
+
+	case "pgdown", " ", "f":
+		m.diff.PageDown()
+		return m, nil
+
+	case "home", "g":
+		m.diff.GotoTop()
+		return m, nil
+
+	case "end", "G":
+		m.diff.GotoBottom()
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m reviewModel) updateEdit(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc", "ctrl+s":
+		// Keep the edit unless it left the message empty
+		edited := strings.TrimSpace(m.editor.Value())
+		if edited != "" {
+			m.tui.message = edited
+		}
+		m.editor.Blur()
+		m.mode = modeReview
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.editor, cmd = m.editor.Update(msg)
+	return m, cmd
+}
+
+func (m reviewModel) updateInstruction(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.instruction.Blur()
+		m.mode = modeReview
+		return m, nil
+
+	case "enter":
+		instruction := strings.TrimSpace(m.instruction.Value())
+		m.instruction.Blur()
+		return m.startRegenerate(instruction)
+	}
+
+	var cmd tea.Cmd
+	m.instruction, cmd = m.instruction.Update(msg)
+	return m, cmd
+}
+
+func (m reviewModel) updateCoAuthors(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "ctrl+c":
+		return m, tea.Quit
+
+	case "esc":
+		m.coAuthors.Blur()
+		m.mode = modeReview
+		return m, nil
+
+	case "enter":
+		refs := strings.TrimSpace(m.coAuthors.Value())
+		if refs == "" {
+			return m, nil
+		}
+		if err := m.tui.addCoAuthors(refs); err != nil {
+			m.setStatus(err.Error(), true)
+			return m, nil
+		}
+		m.coAuthors.Blur()
+		m.mode = modeReview
+		m.setStatus(i18n.T("Co-authors added"), false)
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.coAuthors, cmd = m.coAuthors.Update(msg)
+	return m, cmd
+}
+
+// startRegenerate asks for a new message in the background
+func (m reviewModel) startRegenerate(instruction string) (tea.Model, tea.Cmd) {
+	m.mode = modeBusy
+	m.setStatus(m.tui.text("Regenerating commit message..."), false)
+
+	regenerate := m.tui.regenerate
+	return m, func() tea.Msg {
+		message, err := regenerate(instruction)
+		return regeneratedMsg{message: message, err: err}
+	}
+}
+
+func (m *reviewModel) selectFile(index int) {
+	if index < 0 || index >= len(m.files) {
+		return
+	}
+	m.selected = index
+	m.diff.SetYOffset(m.files[index].offset)
+}
+
+func (m *reviewModel) setStatus(status string, isErr bool) {
+	m.status = status
+	m.statusErr = isErr
+}
+
+// layout sizes the panes to the terminal
+func (m *reviewModel) layout() {
+	// Title, blank line, message pane (+ borders), status and help lines
+	topHeight := m.height - (messagePaneHeight + 2) - 5
+	if topHeight < 3 {
+		topHeight = 3
+	}
+
+	filesWidth := m.filesWidth()
+	m.diff.Width = m.width - filesWidth - 4
+	m.diff.Height = topHeight
+	m.diff.SetContent(colorizeDiff(m.tui.diff, m.diff.Width))
+
+	m.editor.SetWidth(m.width - 2)
+	m.instruction.Width = m.width - len(m.instruction.Prompt) - 2
+	m.coAuthors.Width = m.width - len(m.coAuthors.Prompt) - 2
+}
+
+func (m reviewModel) filesWidth() int {
+	width := 0
+	for _, f := range m.files {
+		if w := lipgloss.Width(f.label()); w > width {
+			width = w
+		}
+	}
+	if width > m.width/3 {
+		width = m.width / 3
+	}
+	if width < 12 {
+		width = 12
+	}
+	return width
+}
+
+func (m reviewModel) View() string {
+	if m.width == 0 {
+		return i18n.T("Loading...")
+	}
+
+	var content strings.Builder
+
+	// Title
+	content.WriteString(reviewTitleStyle.Render(m.tui.text("GitR Commit Review")))
+	if m.tui.notice != "" {
+		content.WriteString("  " + reviewErrorStyle.Render(m.tui.notice))
+	}
+	content.WriteString("\n\n")
+
+	// Files and diff
+	filesStyle, diffStyle := reviewPaneStyle, reviewFocusedPaneStyle
+	if m.focus == focusFiles {
+		filesStyle, diffStyle = reviewFocusedPaneStyle, reviewPaneStyle
+	}
+	files := filesStyle.Width(m.filesWidth()).Height(m.diff.Height).Render(m.renderFiles())
+	diff := diffStyle.Render(m.diff.View())
+	content.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, files, diff) + "\n")
+
+	// Message
+	var message string
+	if m.mode == modeEdit {
+		message = m.editor.View()
+	} else {
+		message = m.tui.message
+	}
+	messageStyle := reviewPaneStyle
+	if m.mode == modeEdit {
+		messageStyle = reviewFocusedPaneStyle
+	}
+	content.WriteString(messageStyle.Width(m.width-2).Height(messagePaneHeight).Render(message) + "\n")
+
+	// Header length counter and status
+	content.WriteString(m.renderHeaderCounter())
+	if m.status != "" {
+		style := reviewHelpStyle
+		if m.statusErr {
+			style = reviewErrorStyle
+		}
+		content.WriteString("  " + style.Render(m.status))
+	}
+	content.WriteString("\n")
+
+	// Help
+	switch m.mode {
+	case modeEdit:
+		content.WriteString(reviewHelpStyle.Render(m.tui.text("Editing • Esc/Ctrl+S: Done • Ctrl+C: Cancel commit")))
+	case modeInstruction:
+		content.WriteString(m.instruction.View() + "  " + reviewHelpStyle.Render(i18n.T("Enter: Regenerate • Esc: Back")))
+	case modeCoAuthors:
+		content.WriteString(m.coAuthors.View() + "  " + reviewHelpStyle.Render(i18n.T("Enter: Add • Esc: Back")))
+	case modeBusy:
+		content.WriteString(reviewHelpStyle.Render(i18n.T("Please wait...")))
+	default:
+		help := i18n.T("a/Enter: Accept • e: Edit")
+		if m.tui.regenerate != nil {
+			help += " • " + i18n.T("r: Regenerate • i: Regenerate with instruction")
+		}
+		if m.tui.addTrailers != nil {
+			help += " • " + i18n.T("w: Co-authors")
+		}
+		help += " • " + i18n.T("Tab: Files/Diff • ↑/↓: Navigate • q/Esc: Cancel")
+		content.WriteString(reviewHelpStyle.Render(help))
+	}
+
+	return content.String()
+}
+
+func (m reviewModel) renderFiles() string {
+	if len(m.files) == 0 {
+		return reviewHelpStyle.Render(i18n.T("No files"))
+	}
+
+	var lines []string
+	for i, f := range m.files {
+		label := truncate(f.label(), m.filesWidth())
+		if i == m.selected && m.focus == focusFiles {
+			label = reviewSelectedStyle.Render(label)
+		}
+		lines = append(lines, label)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (m reviewModel) renderHeaderCounter() string {
+	message := m.tui.message
+	if m.mode == modeEdit {
+		message = m.editor.Value()
+	}
+	header, _, _ := strings.Cut(message, "\n")
+	length := len([]rune(strings.TrimSpace(header)))
+
+	counter := i18n.Sprintf("Header: %d", length)
+	if m.tui.maxLength <= 0 {
+		return reviewHelpStyle.Render(counter)
+	}
+
+	counter = fmt.Sprintf("%s/%d", counter, m.tui.maxLength)
+	if length > m.tui.maxLength {
+		return reviewErrorStyle.Render(counter + i18n.T(" (too long)"))
+	}
+	return reviewOKStyle.Render(counter)
+}
+
+// diffFile summarizes one file of a unified diff
+type diffFile struct {
+	path    string
+	added   int
+	deleted int
+	offset  int // line of the file header within the diff
+}
+
+func (f diffFile) label() string {
+	return fmt.Sprintf("%s +%d -%d", f.path, f.added, f.deleted)
+}
+
+// parseDiffFiles lists the files of a unified diff with their line stats
+func parseDiffFiles(diff string) []diffFile {
+	var files []diffFile
+	for i, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "diff --git "):
+			path := line[len("diff --git "):]
+			if idx := strings.Index(path, " b/"); idx >= 0 {
+				path = path[idx+3:]
+			}
+			files = append(files, diffFile{path: path, offset: i})
+		case len(files) == 0:
+			continue
+		case strings.HasPrefix(line, "+++ "), strings.HasPrefix(line, "--- "):
+			continue
+		case strings.HasPrefix(line, "+"):
+			files[len(files)-1].added++
+		case strings.HasPrefix(line, "-"):
+			files[len(files)-1].deleted++
+		}
+	}
+	return files
+}
+
+// colorizeDiff applies syntax colors to a unified diff, cutting lines to width
+func colorizeDiff(diff string, width int) string {
+	lines := strings.Split(strings.TrimRight(diff, "\n"), "\n")
+	for i, line := range lines {
+		line = truncate(strings.ReplaceAll(line, "\t", "    "), width)
+		lines[i] = line
+		switch {
+		case strings.HasPrefix(line, "diff --git "),
+			strings.HasPrefix(line, "+++ "),
+			strings.HasPrefix(line, "--- "),
+			strings.HasPrefix(line, "index "),
+			strings.HasPrefix(line, "new file mode"),
+			strings.HasPrefix(line, "deleted file mode"):
+			lines[i] = diffHeaderStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffDelStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func truncate(s string, width int) string {
+	runes := []rune(s)
+	if width <= 1 || len(runes) <= width {
+		return s
+	}
+	return string(runes[:width-1]) + "…"
+}
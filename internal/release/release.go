@@ -0,0 +1,206 @@
+// Package release collects the commits since the previous tag and turns them
+// into the message of an annotated tag: a headline and grouped highlights.
+package release
+
+import (
+	"fmt"
+	"strings"
+
+	"gitr/internal/git"
+	"gitr/internal/output"
+)
+
+// Commit is a commit of the release with its parsed message
+type Commit struct {
+	Hash    string         `json:"hash"`
+	Message output.Message `json:"message"`
+}
+
+// Group is a heading of the tag message and the commits under it
+type Group struct {
+	Title   string   `json:"title"`
+	Commits []Commit `json:"commits"`
+}
+
+// Release is what a new tag covers
+type Release struct {
+	Name     string  // the new tag
+	Rev      string  // the commit it points at
+	Previous string  // the previous tag, or "" for the first one
+	Groups   []Group // the commits since the previous tag, grouped
+	Total    int     // commits since the previous tag, including ones beyond maxCommits
+	Diff     string  // the changes since the previous tag; empty for the first tag
+}
+
+// maxCommits is how many commits since the previous tag are read
+const maxCommits = 200
+
+// groupOrder lists the headings in the order they appear in the message.
+// Breaking changes come first; types without a group of their own go under
+// "Other changes", as do messages that don't follow Conventional Commits.
+var groupOrder = []struct {
+	title string
+	types []string
+}{
+	{title: "Breaking changes"},
+	{title: "Features", types: []string{"feat"}},
+	{title: "Fixes", types: []string{"fix"}},
+	{title: "Performance", types: []string{"perf"}},
+	{title: "Documentation", types: []string{"docs"}},
+	{title: "Other changes"},
+}
+
+// Load reads the commits from the previous tag reachable from rev up to rev
+func Load(name, rev string) (*Release, error) {
+	hash, err := git.ResolveCommit(rev)
+	if err != nil {
+		return nil, err
+	}
+	previous, err := git.PreviousTag(hash, name)
+	if err != nil {
+		return nil, err
+	}
+	r := &Release{Name: name, Rev: hash, Previous: previous}
+
+	revisions := hash
+	if previous != "" {
+		revisions = previous + ".." + hash
+	}
+	if r.Total, err = git.CountCommits(revisions); err != nil {
+		return nil, err
+	}
+	hashes, err := git.Commits(revisions, maxCommits)
+	if err != nil {
+		return nil, err
+	}
+	var commits []Commit
+	for _, h := range hashes {
+		message, err := git.CommitMessage(h)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, Commit{Hash: h, Message: output.ParseConventional(message)})
+	}
+	r.Groups = group(commits)
+
+	if previous != "" {
+		if r.Diff, err = git.EnrichedDiff(git.EnrichOptions{From: previous, To: hash}); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// group sorts commits under the headings of groupOrder and drops empty groups
+func group(commits []Commit) []Group {
+	groups := make([]Group, len(groupOrder))
+	for i, g := range groupOrder {
+		groups[i].Title = g.title
+	}
+	for _, commit := range commits {
+		i := groupIndex(commit.Message)
+		groups[i].Commits = append(groups[i].Commits, commit)
+	}
+
+	var nonEmpty []Group
+	for _, g := range groups {
+		if len(g.Commits) > 0 {
+			nonEmpty = append(nonEmpty, g)
+		}
+	}
+	return nonEmpty
+}
+
+// groupIndex returns the index in groupOrder of the group a message belongs to
+func groupIndex(message output.Message) int {
+	if message.Breaking {
+		return 0
+	}
+	for i, g := range groupOrder {
+		for _, t := range g.types {
+			if strings.EqualFold(message.Type, t) {
+				return i
+			}
+		}
+	}
+	return len(groupOrder) - 1
+}
+
+// Instruction is the system prompt for a tag message
+func Instruction(maxLength int) string {
+	return fmt.Sprintf(`You write the message of an annotated git tag from the commits since the previous tag.
+The first line is a headline of at most %d characters that sums up the release.
+After a blank line, list the highlights under the headings given in the prompt, as a "Heading:" line followed by "- " bullets, with a blank line between groups.
+Keep the order of the headings, merge related commits into one bullet, leave out trivial changes such as typo fixes and chores, and drop headings that end up empty.
+Write plain text: no Markdown headings, bold text, code fences or commit hashes.
+Answer with the tag message only.`, maxLength)
+}
+
+// Prompt lists the grouped commits for the model
+func Prompt(r *Release) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Tag: %s\n", r.Name)
+	if r.Previous != "" {
+		fmt.Fprintf(&b, "Previous tag: %s\n", r.Previous)
+	} else {
+		b.WriteString("This is the first tag of the repository.\n")
+	}
+	fmt.Fprintf(&b, "Commits: %d", r.Total)
+	if r.Total > maxCommits {
+		fmt.Fprintf(&b, " (only the newest %d are listed)", maxCommits)
+	}
+	b.WriteString("\n")
+
+	for _, g := range r.Groups {
+		fmt.Fprintf(&b, "\n%s:\n", g.Title)
+		for _, commit := range g.Commits {
+			fmt.Fprintf(&b, "- %s\n", commit.Message.Header)
+			for _, footer := range commit.Message.Footers {
+				if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+					fmt.Fprintf(&b, "    BREAKING CHANGE: %s\n", footer.Value)
+				}
+			}
+		}
+	}
+	return b.String()
+}
+
+// Message builds the tag message without a model: a headline and the
+// subjects of the commits under their headings
+func Message(r *Release) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Release %s\n", r.Name)
+	for _, g := range r.Groups {
+		fmt.Fprintf(&b, "\n%s:\n", g.Title)
+		for _, commit := range g.Commits {
+			fmt.Fprintf(&b, "- %s\n", highlight(commit.Message))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// highlight describes a commit in one line, prefixed with its scope if it has one
+func highlight(message output.Message) string {
+	subject := message.Subject
+	for _, footer := range message.Footers {
+		if footer.Token == "BREAKING CHANGE" || footer.Token == "BREAKING-CHANGE" {
+			subject = footer.Value
+			break
+		}
+	}
+	if message.Scope != "" {
+		return message.Scope + ": " + subject
+	}
+	return subject
+}
+
+// Clean extracts the tag message from a model answer, dropping a code fence
+// around it
+func Clean(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if strings.HasPrefix(answer, "```") && strings.HasSuffix(answer, "```") {
+		_, inner, _ := strings.Cut(answer, "\n")
+		answer = strings.TrimSuffix(strings.TrimSpace(inner), "```")
+	}
+	return strings.TrimSpace(answer)
+}
@@ -0,0 +1,90 @@
+package release
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"gitr/internal/output"
+)
+
+func TestGroup(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []string
+		want     []string
+	}{
+		{
+			name:     "ordered by heading",
+			messages: []string{"docs: explain tags", "fix: close the file", "feat(api): add Hello", "perf: cache lookups"},
+			want:     []string{"Features: feat(api): add Hello", "Fixes: fix: close the file", "Performance: perf: cache lookups", "Documentation: docs: explain tags"},
+		},
+		{
+			name:     "breaking changes go first",
+			messages: []string{"feat: add Open", "fix!: drop Close", "refactor: rename\n\nBREAKING CHANGE: Load is gone"},
+			want:     []string{"Breaking changes: fix!: drop Close", "Breaking changes: refactor: rename", "Features: feat: add Open"},
+		},
+		{
+			name:     "unknown types and plain messages go to other changes",
+			messages: []string{"chore: bump deps", "Update README", "FEAT: shout"},
+			want:     []string{"Features: FEAT: shout", "Other changes: chore: bump deps", "Other changes: Update README"},
+		},
+		{
+			name: "no commits",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var commits []Commit
+			for i, message := range tt.messages {
+				commits = append(commits, Commit{Hash: fmt.Sprint(i), Message: output.ParseConventional(message)})
+			}
+			var got []string
+			for _, g := range group(commits) {
+				if len(g.Commits) == 0 {
+					t.Errorf("empty group %q", g.Title)
+				}
+				for _, commit := range g.Commits {
+					got = append(got, g.Title+": "+commit.Message.Header)
+				}
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("groups:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"feat(api): add Hello", "api: add Hello"},
+		{"fix: close the file", "close the file"},
+		{"feat!: new config\n\nBREAKING CHANGE: the XML format changed", "the XML format changed"},
+		{"Update README", "Update README"},
+	}
+	for _, tt := range tests {
+		if got := highlight(output.ParseConventional(tt.message)); got != tt.want {
+			t.Errorf("highlight(%q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+}
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		answer string
+		want   string
+	}{
+		{"Release v1\n\nFeatures:\n- a", "Release v1\n\nFeatures:\n- a"},
+		{"\n```\nRelease v1\n```\n", "Release v1"},
+		{"```text\nRelease v1\n\n- a\n```", "Release v1\n\n- a"},
+		{"Release with `code`", "Release with `code`"},
+	}
+	for _, tt := range tests {
+		if got := Clean(tt.answer); got != tt.want {
+			t.Errorf("Clean(%q) = %q, want %q", tt.answer, got, tt.want)
+		}
+	}
+}
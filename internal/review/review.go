@@ -0,0 +1,221 @@
+// Package review asks a model to review a diff and parses its findings.
+package review
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gitr/internal/config"
+)
+
+// Finding is one problem the reviewer found
+type Finding struct {
+	File       string `json:"file"`
+	Line       int    `json:"line,omitempty"` // in the new version of the file; 0 when unknown
+	Severity   string `json:"severity"`       // high, medium or low
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// Location returns file:line, or the file alone when the line is unknown
+func (f Finding) Location() string {
+	if f.Line > 0 {
+		return fmt.Sprintf("%s:%d", f.File, f.Line)
+	}
+	return f.File
+}
+
+// Instruction is the system prompt of the reviewer
+const Instruction = `You are a careful senior engineer reviewing changes before they are committed.
+Look for bugs, security problems, data loss, race conditions, missing error handling and clear maintainability problems. Ignore style nits and anything a formatter or linter would catch.
+Lines of the diff are prefixed with their line number in the new version of the file.
+Answer with a JSON array only, one object per finding:
+[{"file": "path/in/repository", "line": 42, "severity": "high", "message": "what is wrong and why it matters", "suggestion": "how to fix it"}]
+Use high for bugs and security problems that must be fixed before committing, medium for likely problems and low for minor improvements. Answer [] when there is nothing worth reporting.`
+
+// Prompt builds the user prompt for a diff
+func Prompt(diff string) string {
+	return "Review the following changes:\n\n" + diff
+}
+
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,\d+)? @@`)
+
+// NumberLines prefixes the added and context lines of a unified diff with
+// their line number in the new file, so the reviewer can point at them
+func NumberLines(diff string) string {
+	var b strings.Builder
+	line, inHunk := 0, false
+	for _, text := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if m := hunkHeader.FindStringSubmatch(text); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			inHunk = true
+			b.WriteString(text + "\n")
+			continue
+		}
+		if inHunk && text != "" {
+			switch text[0] {
+			case '+', ' ':
+				fmt.Fprintf(&b, "%5d %s\n", line, text)
+				line++
+				continue
+			case '-', '\\':
+				fmt.Fprintf(&b, "%5s %s\n", "", text)
+				continue
+			}
+		}
+		// Anything else, e.g. the next "diff --git", ends the hunk
+		inHunk = false
+		b.WriteString(text + "\n")
+	}
+	return b.String()
+}
+
+// rawFinding accepts the shapes models tend to answer with
+type rawFinding struct {
+	File       string `json:"file"`
+	Path       string `json:"path"`
+	Line       any    `json:"line"`
+	Severity   string `json:"severity"`
+	Message    string `json:"message"`
+	Suggestion string `json:"suggestion"`
+}
+
+// Parse extracts the findings from the reviewer's answer. The JSON may be
+// wrapped in a code fence or in an object with a "findings" array, and may
+// follow or precede prose. Findings are sorted by severity, then location.
+func Parse(answer string) ([]Finding, error) {
+	raw, err := decodeFindings(unfence(answer))
+	if err != nil {
+		// A fence may only be quoted in the prose
+		if raw, err = decodeFindings(answer); err != nil {
+			return nil, err
+		}
+	}
+
+	findings := []Finding{}
+	for _, r := range raw {
+		if strings.TrimSpace(r.Message) == "" {
+			continue
+		}
+		finding := Finding{
+			File:       strings.TrimSpace(r.File),
+			Severity:   normalizeSeverity(r.Severity),
+			Message:    strings.TrimSpace(r.Message),
+			Suggestion: strings.TrimSpace(r.Suggestion),
+		}
+		if finding.File == "" {
+			finding.File = strings.TrimSpace(r.Path)
+		}
+		switch line := r.Line.(type) {
+		case float64:
+			finding.Line = int(line)
+		case string:
+			finding.Line, _ = strconv.Atoi(strings.TrimSpace(line))
+		}
+		findings = append(findings, finding)
+	}
+	slices.SortStableFunc(findings, func(a, b Finding) int {
+		if d := Rank(b.Severity) - Rank(a.Severity); d != 0 {
+			return d
+		}
+		if c := strings.Compare(a.File, b.File); c != 0 {
+			return c
+		}
+		return a.Line - b.Line
+	})
+	return findings, nil
+}
+
+// unfence returns the contents of the first code fence in the answer, or the
+// answer itself when it has none
+func unfence(answer string) string {
+	_, rest, ok := strings.Cut(answer, "```")
+	if !ok {
+		return answer
+	}
+	// The opening fence may name a language
+	if _, body, ok := strings.Cut(rest, "\n"); ok {
+		rest = body
+	}
+	fenced, _, _ := strings.Cut(rest, "```")
+	return fenced
+}
+
+// decodeFindings decodes the first JSON value in the text that is a list of
+// findings or an object holding one. Brackets in prose before it, such as
+// "I found [2] issues", are skipped.
+func decodeFindings(text string) ([]rawFinding, error) {
+	var first error
+	for i, c := range text {
+		if c != '[' && c != '{' {
+			continue
+		}
+		decoder := json.NewDecoder(strings.NewReader(text[i:]))
+		var err error
+		if c == '{' {
+			var wrapped struct {
+				Findings *[]rawFinding `json:"findings"`
+			}
+			if err = decoder.Decode(&wrapped); err == nil {
+				if wrapped.Findings != nil {
+					return *wrapped.Findings, nil
+				}
+				err = fmt.Errorf("object without a findings array")
+			}
+		} else {
+			var raw []rawFinding
+			if err = decoder.Decode(&raw); err == nil {
+				return raw, nil
+			}
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	if first == nil {
+		return nil, fmt.Errorf("no findings in review answer %q", strings.TrimSpace(text))
+	}
+	return nil, fmt.Errorf("invalid review answer: %w", first)
+}
+
+// normalizeSeverity maps the severities models come up with to high, medium or low
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "high", "critical", "blocker", "error", "severe":
+		return config.SeverityHigh
+	case "low", "minor", "info", "nit", "note", "suggestion", "trivial":
+		return config.SeverityLow
+	}
+	return config.SeverityMedium
+}
+
+// Rank orders severities: higher is more serious, none ranks above everything
+// so that it blocks nothing
+func Rank(severity string) int {
+	switch severity {
+	case config.SeverityHigh:
+		return 3
+	case config.SeverityMedium:
+		return 2
+	case config.SeverityLow:
+		return 1
+	case config.SeverityNone:
+		return 4
+	}
+	return 0
+}
+
+// AtLeast returns the findings of the given severity or worse
+func AtLeast(findings []Finding, severity string) []Finding {
+	var serious []Finding
+	for _, finding := range findings {
+		if Rank(finding.Severity) >= Rank(severity) {
+			serious = append(serious, finding)
+		}
+	}
+	return serious
+}
@@ -0,0 +1,113 @@
+package review
+
+import (
+	"strings"
+	"testing"
+
+	"gitr/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		answer  string
+		want    []string
+		wantErr string
+	}{
+		{
+			name:   "plain array",
+			answer: `[{"file": "a.go", "line": 3, "severity": "low", "message": "unused"}]`,
+			want:   []string{"low a.go:3 unused"},
+		},
+		{
+			name:   "empty array",
+			answer: "[]",
+		},
+		{
+			name:   "code fence",
+			answer: "Here is my review:\n```json\n[{\"file\": \"a.go\", \"line\": \"7\", \"severity\": \"critical\", \"message\": \"nil map\"}]\n```\nThat's all [for now].",
+			want:   []string{"high a.go:7 nil map"},
+		},
+		{
+			name:   "prose with brackets before the JSON",
+			answer: `I found [2] issues: {"findings": [{"path": "b.go", "severity": "nit", "message": "typo"}, {"file": "a.go", "line": 9, "severity": "error", "message": "leak"}]}`,
+			want:   []string{"high a.go:9 leak", "low b.go typo"},
+		},
+		{
+			name:   "object without findings before the answer",
+			answer: `Using {"format": "json"}: [{"file": "a.go", "message": "race"}]`,
+			want:   []string{"medium a.go race"},
+		},
+		{
+			name:   "findings without a message are dropped",
+			answer: `[{"file": "a.go", "severity": "high", "message": " "}]`,
+		},
+		{
+			name:   "sorted by severity, then location",
+			answer: `[{"file": "b.go", "line": 2, "severity": "high", "message": "x"}, {"file": "a.go", "line": 5, "severity": "high", "message": "y"}, {"file": "a.go", "line": 1, "severity": "high", "message": "z"}]`,
+			want:   []string{"high a.go:1 z", "high a.go:5 y", "high b.go:2 x"},
+		},
+		{
+			name:    "no JSON",
+			answer:  "Looks good to me.",
+			wantErr: "no findings",
+		},
+		{
+			name:    "broken JSON",
+			answer:  `[{"file": "a.go", "message": }]`,
+			wantErr: "invalid review answer",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Parse(tt.answer)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, f := range findings {
+				got = append(got, f.Severity+" "+f.Location()+" "+f.Message)
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("findings:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	order := []string{"unknown", config.SeverityLow, config.SeverityMedium, config.SeverityHigh, config.SeverityNone}
+	for i := 1; i < len(order); i++ {
+		if Rank(order[i]) <= Rank(order[i-1]) {
+			t.Errorf("Rank(%q) = %d, want more than Rank(%q) = %d", order[i], Rank(order[i]), order[i-1], Rank(order[i-1]))
+		}
+	}
+}
+
+func TestAtLeast(t *testing.T) {
+	findings := []Finding{
+		{File: "a.go", Severity: config.SeverityHigh},
+		{File: "b.go", Severity: config.SeverityMedium},
+		{File: "c.go", Severity: config.SeverityLow},
+	}
+	tests := []struct {
+		severity string
+		want     int
+	}{
+		{config.SeverityHigh, 1},
+		{config.SeverityMedium, 2},
+		{config.SeverityLow, 3},
+		{config.SeverityNone, 0},
+	}
+	for _, tt := range tests {
+		if got := AtLeast(findings, tt.severity); len(got) != tt.want {
+			t.Errorf("AtLeast(%q) = %v, want %d findings", tt.severity, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package review
+
+import (
+	"encoding/json"
+	"io"
+
+	"gitr/internal/config"
+)
+
+// SARIF 2.1.0, the format code scanning tools such as GitHub's import
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifResult struct {
+	RuleID     string            `json:"ruleId"`
+	Level      string            `json:"level"`
+	Message    sarifMessage      `json:"message"`
+	Locations  []sarifLocation   `json:"locations,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifRuleID is the rule every finding is reported under
+const sarifRuleID = "gitr-review"
+
+// sarifLevels maps severities to SARIF result levels
+var sarifLevels = map[string]string{
+	config.SeverityHigh:   "error",
+	config.SeverityMedium: "warning",
+	config.SeverityLow:    "note",
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log
+func WriteSARIF(w io.Writer, findings []Finding) error {
+	results := []sarifResult{}
+	for _, finding := range findings {
+		text := finding.Message
+		if finding.Suggestion != "" {
+			text += "\nSuggestion: " + finding.Suggestion
+		}
+		result := sarifResult{
+			RuleID:  sarifRuleID,
+			Level:   sarifLevels[finding.Severity],
+			Message: sarifMessage{Text: text},
+		}
+		if finding.File != "" {
+			location := sarifPhysicalLocation{ArtifactLocation: sarifArtifact{URI: finding.File}}
+			if finding.Line > 0 {
+				location.Region = &sarifRegion{StartLine: finding.Line}
+			}
+			result.Locations = []sarifLocation{{PhysicalLocation: location}}
+		}
+		if finding.Suggestion != "" {
+			result.Properties = map[string]string{"suggestion": finding.Suggestion}
+		}
+		results = append(results, result)
+	}
+
+	log := sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool: sarifTool{Driver: sarifDriver{
+				Name:  "gitr",
+				Rules: []sarifRule{{ID: sarifRuleID, ShortDescription: sarifMessage{Text: "Problem found by an LLM code review"}}},
+			}},
+			Results: results,
+		}},
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(log)
+}
@@ -0,0 +1,54 @@
+// Package stash names stash entries after the work they hold, so they still
+// make sense after "WIP on main" has stopped meaning anything.
+package stash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxUntracked is how many untracked files are listed in the prompt
+const maxUntracked = 20
+
+// Instruction is the system prompt for a stash message
+func Instruction(maxLength int) string {
+	return fmt.Sprintf(`You name git stash entries. The changes are unfinished work someone put aside to come back to later.
+Answer with a single line of at most %d characters that says what the work is about, specific enough to recognize it days later, e.g. "Half-done retry logic for the sync client".
+Don't use a Conventional Commit type, quotes or a trailing period.`, maxLength)
+}
+
+// Prompt lists the changes of a stash: the diff of the tracked files and the
+// names of untracked files
+func Prompt(diff string, untracked []string) string {
+	var b strings.Builder
+	b.WriteString("Name the stash with these changes.\n")
+	if len(untracked) > 0 {
+		b.WriteString("\nNew untracked files:\n")
+		for i, path := range untracked {
+			if i == maxUntracked {
+				fmt.Fprintf(&b, "- and %d more\n", len(untracked)-maxUntracked)
+				break
+			}
+			fmt.Fprintf(&b, "- %s\n", path)
+		}
+	}
+	if diff != "" {
+		fmt.Fprintf(&b, "\nDiff:\n\n%s", diff)
+	}
+	return b.String()
+}
+
+// Clean extracts the stash message from a model answer: its first line
+// without quotes, backticks or a trailing period
+func Clean(answer string) string {
+	for _, line := range strings.Split(answer, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "```") {
+			continue
+		}
+		if line = strings.TrimSpace(strings.Trim(line, "\"'`")); line != "" {
+			return strings.TrimSuffix(line, ".")
+		}
+	}
+	return ""
+}
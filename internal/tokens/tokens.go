@@ -0,0 +1,168 @@
+// Package tokens estimates how many tokens a text is for a model and knows
+// the context windows of common models. The estimate is a heuristic over
+// words, identifiers and punctuation with ratios per tokenizer family; no BPE
+// vocabulary is included, so counts are approximate.
+package tokens
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Family is a group of models that split text into tokens the same way
+type Family struct {
+	Name string
+	// wordChars is the number of letters per token within a word; common
+	// words are a single token and long identifiers split into several
+	wordChars float64
+	// symbolChars is the average number of punctuation characters per token
+	symbolChars float64
+}
+
+// Tokenizer families. The ratios approximate English prose and source code;
+// counts are estimates, not exact tokenizer output.
+var (
+	O200k         = Family{Name: "o200k", wordChars: 6.5, symbolChars: 2.0}
+	CL100k        = Family{Name: "cl100k", wordChars: 6.0, symbolChars: 2.0}
+	SentencePiece = Family{Name: "sentencepiece", wordChars: 4.5, symbolChars: 1.5}
+	Claude        = Family{Name: "claude", wordChars: 5.5, symbolChars: 1.8}
+)
+
+// modelInfo describes the models whose names start with prefix
+type modelInfo struct {
+	prefix        string
+	family        Family
+	contextWindow int
+}
+
+// models lists known models; the longest matching prefix wins
+var models = []modelInfo{
+	{"gpt-5", O200k, 400000},
+	{"gpt-4.1", O200k, 1047576},
+	{"gpt-4o", O200k, 128000},
+	{"gpt-4-turbo", CL100k, 128000},
+	{"gpt-4-32k", CL100k, 32768},
+	{"gpt-4", CL100k, 8192},
+	{"gpt-3.5-turbo", CL100k, 16385},
+	{"o1", O200k, 200000},
+	{"o3", O200k, 200000},
+	{"o4", O200k, 200000},
+	{"claude", Claude, 200000},
+	{"gemini", SentencePiece, 1048576},
+	{"llama3.1", SentencePiece, 131072},
+	{"llama-3.1", SentencePiece, 131072},
+	{"llama3.2", SentencePiece, 131072},
+	{"llama-3.2", SentencePiece, 131072},
+	{"llama3.3", SentencePiece, 131072},
+	{"llama-3.3", SentencePiece, 131072},
+	{"llama", SentencePiece, 8192},
+	{"mistral", SentencePiece, 32768},
+	{"mixtral", SentencePiece, 32768},
+	{"qwen", SentencePiece, 32768},
+	{"deepseek", SentencePiece, 65536},
+	{"gemma", SentencePiece, 8192},
+	{"phi", SentencePiece, 16384},
+}
+
+// DefaultContextWindow is assumed for models that aren't known
+const DefaultContextWindow = 8192
+
+// lookup returns the entry for a model, matching the name without any
+// provider prefix such as "openai/" and case-insensitively
+func lookup(model string) (modelInfo, bool) {
+	name := strings.ToLower(model)
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+
+	var best modelInfo
+	found := false
+	for _, info := range models {
+		if strings.HasPrefix(name, info.prefix) && len(info.prefix) > len(best.prefix) {
+			best, found = info, true
+		}
+	}
+	return best, found
+}
+
+// FamilyFor returns the tokenizer family of a model, cl100k if it isn't known
+func FamilyFor(model string) Family {
+	if info, ok := lookup(model); ok {
+		return info.family
+	}
+	return CL100k
+}
+
+// ContextWindow returns how many tokens a model accepts for prompt and answer together
+func ContextWindow(model string) int {
+	if info, ok := lookup(model); ok {
+		return info.contextWindow
+	}
+	return DefaultContextWindow
+}
+
+// pieces splits text the way BPE tokenizers do before merging: contractions,
+// words with their leading space, groups of up to three digits, runs of
+// punctuation and runs of whitespace
+var pieces = regexp.MustCompile(`'(?:s|t|re|ve|m|ll|d)|[^\r\n\p{L}\p{N}]?\p{L}+|\p{N}{1,3}| ?[^\s\p{L}\p{N}]+[\r\n]*|\s*[\r\n]+|\s+`)
+
+// Count estimates the number of tokens in text for the given model
+func Count(text, model string) int {
+	return FamilyFor(model).Count(text)
+}
+
+// Count estimates the number of tokens in text
+func (f Family) Count(text string) int {
+	total := 0
+	for _, piece := range pieces.FindAllString(text, -1) {
+		total += f.countPiece(piece)
+	}
+	return total
+}
+
+// countPiece estimates the tokens of one pre-tokenized piece
+func (f Family) countPiece(piece string) int {
+	trimmed := strings.TrimLeft(piece, " ")
+	if trimmed == "" || strings.TrimSpace(piece) == "" {
+		// Runs of whitespace merge into about one token per line
+		return max(1, strings.Count(piece, "\n"))
+	}
+
+	first, _ := utf8.DecodeRuneInString(trimmed)
+	switch {
+	case unicode.IsDigit(first):
+		return 1
+	case unicode.IsLetter(first):
+		// Scripts without spaces between words (CJK) take about a token per character
+		if unicode.In(first, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+			return utf8.RuneCountInString(trimmed)
+		}
+		// Non-ASCII letters are split into smaller pieces
+		length := float64(utf8.RuneCountInString(trimmed))
+		if first > unicode.MaxASCII {
+			length *= 2
+		}
+		return int(math.Ceil(length / f.wordChars))
+	}
+	return int(math.Ceil(float64(utf8.RuneCountInString(strings.TrimSpace(trimmed))) / f.symbolChars))
+}
+
+// messageOverhead is the tokens a chat message costs beyond its content
+// (role and separators), and replyOverhead primes the answer
+const (
+	messageOverhead = 4
+	replyOverhead   = 3
+)
+
+// CountMessages estimates the prompt tokens of a chat request with the given message contents
+func CountMessages(contents []string, model string) int {
+	family := FamilyFor(model)
+	total := replyOverhead
+	for _, content := range contents {
+		total += messageOverhead + family.Count(content)
+	}
+	return total
+}
@@ -0,0 +1,149 @@
+package trailers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Member is a person from the team roster
+type Member struct {
+	Alias string `xml:"alias,attr"`
+	Name  string `xml:"name,attr"`
+	Email string `xml:"email,attr"`
+}
+
+// Identity returns the member as used in trailers: "Name <email>"
+func (m Member) Identity() string {
+	return fmt.Sprintf("%s <%s>", m.Name, m.Email)
+}
+
+// Roster represents the team roster file
+type Roster struct {
+	XMLName xml.Name `xml:"team"`
+	Members []Member `xml:"member"`
+
+	// path is the file the roster was loaded from, empty if none was found
+	path string
+}
+
+// identityPattern matches a literal "Name <email>"
+var identityPattern = regexp.MustCompile(`^(.+?)\s*<([^<>\s]+@[^<>\s]+)>$`)
+
+// RosterPaths returns the locations searched for the roster, in order:
+// the configured path, .gitr_team in the repository root and ~/.config/gitr/team
+func RosterPaths(configured string) []string {
+	var paths []string
+	if configured != "" {
+		paths = append(paths, expandHome(configured))
+	}
+	if root, err := git.RepoRoot(); err == nil {
+		paths = append(paths, filepath.Join(root, ".gitr_team"))
+	}
+	if homeDir, err := os.UserHomeDir(); err == nil {
+		paths = append(paths, filepath.Join(homeDir, ".config", "gitr", "team"))
+	}
+	return paths
+}
+
+// LoadRoster loads the first roster file found. A missing roster is not an
+// error; it gives an empty roster, so literal "Name <email>" entries still work.
+func LoadRoster(configured string) (*Roster, error) {
+	for _, path := range RosterPaths(configured) {
+		data, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			if path == expandHome(configured) {
+				return nil, fmt.Errorf("roster %s not found", path)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var roster Roster
+		if err := xml.Unmarshal(data, &roster); err != nil {
+			return nil, fmt.Errorf("invalid roster %s: %v", path, err)
+		}
+		for _, member := range roster.Members {
+			if member.Name == "" || member.Email == "" {
+				return nil, fmt.Errorf("invalid roster %s: member %q needs a name and an email", path, member.Alias)
+			}
+		}
+		roster.path = path
+		return &roster, nil
+	}
+	return &Roster{}, nil
+}
+
+// Path returns the file the roster was loaded from
+func (r *Roster) Path() string {
+	return r.path
+}
+
+// Aliases returns the aliases of all members
+func (r *Roster) Aliases() []string {
+	aliases := make([]string, 0, len(r.Members))
+	for _, member := range r.Members {
+		if member.Alias != "" {
+			aliases = append(aliases, member.Alias)
+		}
+	}
+	return aliases
+}
+
+// Find looks up a member by alias, email or name (case-insensitive).
+// A literal "Name <email>" is accepted even if it isn't in the roster.
+func (r *Roster) Find(ref string) (Member, error) {
+	ref = strings.TrimSpace(ref)
+	for _, match := range []func(Member) string{
+		func(m Member) string { return m.Alias },
+		func(m Member) string { return m.Email },
+		func(m Member) string { return m.Name },
+	} {
+		for _, member := range r.Members {
+			if value := match(member); value != "" && strings.EqualFold(value, ref) {
+				return member, nil
+			}
+		}
+	}
+
+	if m := identityPattern.FindStringSubmatch(ref); m != nil {
+		return Member{Name: m[1], Email: m[2]}, nil
+	}
+
+	if len(r.Members) == 0 {
+		return Member{}, fmt.Errorf("unknown co-author %q: no team roster found (use \"Name <email>\" or create .gitr_team)", ref)
+	}
+	return Member{}, fmt.Errorf("unknown co-author %q (known: %s)", ref, strings.Join(r.Aliases(), ", "))
+}
+
+// Resolve looks up a comma-separated list of members
+func (r *Roster) Resolve(refs string) ([]Member, error) {
+	var members []Member
+	for _, ref := range strings.Split(refs, ",") {
+		if strings.TrimSpace(ref) == "" {
+			continue
+		}
+		member, err := r.Find(ref)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func expandHome(path string) string {
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			return filepath.Join(homeDir, rest)
+		}
+	}
+	return path
+}
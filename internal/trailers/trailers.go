@@ -0,0 +1,87 @@
+package trailers
+
+import (
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+
+	"gitr/internal/git"
+)
+
+// Common trailer tokens
+const (
+	CoAuthoredBy = "Co-authored-by"
+	SignedOffBy  = "Signed-off-by"
+)
+
+// Trailer is a "Token: value" line at the end of a commit message
+type Trailer struct {
+	Token string
+	Value string
+}
+
+func (t Trailer) String() string {
+	return t.Token + ": " + t.Value
+}
+
+// CoAuthors returns Co-authored-by trailers for the members
+func CoAuthors(members []Member) []Trailer {
+	trailers := make([]Trailer, 0, len(members))
+	for _, member := range members {
+		trailers = append(trailers, Trailer{Token: CoAuthoredBy, Value: member.Identity()})
+	}
+	return trailers
+}
+
+// Apply adds trailers to a message with git interpret-trailers, so they merge
+// into an existing trailer block the way git does. Trailers already present,
+// including ones the model wrote itself, aren't repeated.
+func Apply(message string, trailers []Trailer) (string, error) {
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	args := []string{"interpret-trailers", "--no-divider", "--if-exists", "addIfDifferent"}
+	for _, trailer := range trailers {
+		args = append(args, "--trailer", trailer.String())
+	}
+
+	cmd := git.Command(args...)
+	cmd.Stdin = strings.NewReader(strings.TrimSpace(message) + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("git interpret-trailers failed: %s", strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+
+	return Dedupe(strings.TrimSpace(string(output))), nil
+}
+
+var trailerLine = regexp.MustCompile(`^([\w-]+)\s*:\s*(.*)$`)
+
+// Dedupe removes repeated trailers from the last paragraph of a message.
+// Tokens and values are compared case-insensitively, as git compares tokens.
+func Dedupe(message string) string {
+	start := strings.LastIndex(message, "\n\n")
+	if start < 0 {
+		return message
+	}
+
+	seen := map[string]bool{}
+	var kept []string
+	for _, line := range strings.Split(message[start+2:], "\n") {
+		if m := trailerLine.FindStringSubmatch(line); m != nil {
+			key := strings.ToLower(m[1]) + ":" + strings.ToLower(strings.Join(strings.Fields(m[2]), " "))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+		}
+		kept = append(kept, line)
+	}
+
+	return message[:start+2] + strings.Join(kept, "\n")
+}